@@ -2,30 +2,42 @@
 package app
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/eth"
 	"github.com/FourthState/plasma-mvp-sidechain/handlers"
+	"github.com/FourthState/plasma-mvp-sidechain/metrics"
 	"github.com/FourthState/plasma-mvp-sidechain/msgs"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
 	dbm "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 	tmtypes "github.com/tendermint/tendermint/types"
 	"io"
 	"math/big"
-	"os"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
 const (
 	appName = "plasmaMVP"
+
+	// interval at which the local mirror of the rootchain is synced
+	rootchainPollInterval = 5 * time.Second
+	// interval at which a transaction waiting on the rootchain mirror is delivered again
+	rootchainRetryInterval = time.Second
 )
 
 // PlasmaMVPChain is an extended ABCI application
@@ -33,27 +45,61 @@ type PlasmaMVPChain struct {
 	*baseapp.BaseApp
 	cdc *codec.Codec
 
-	txIndex   uint16
-	feeAmount *big.Int
+	txIndex        uint16
+	maxTxsPerBlock uint16 // set in genesis
+	feeAmount      *big.Int
+	blockTxs       [][]byte // transactions delivered in the current block
+
+	validatorUpdates map[string]uint64 // voting power by amino encoded public key, updated in the current block
+
+	mempoolLimiter *handlers.MempoolLimiter // spends admitted into the mempool since the last commit
+	feeSchedule    *handlers.FeeSchedule    // fee policy enforced in CheckTx, replaced by SetFeePolicy
+
+	committedHeight int64           // latest committed height. accessed atomically
+	committedState  *committedState // read-only view of the committed state for the background services
 
 	// persistent stores
-	dataStore store.DataStore
+	dataStoreKey *sdk.KVStoreKey
+	dataStore    store.DataStore
+	mailboxDB    dbm.DB // confirm signature mailbox is disabled if nil
+	verifierDB   dbm.DB // progress of the header verifier. Kept in memory if nil
 
 	// smart contract connection
-	ethConnection *eth.Plasma
+	ethConnection  *eth.Plasma
+	depositWatcher *eth.DepositWatcher
+	committer      *headerCommitter
+	verifier       *headerVerifier
+	feeSweeper     *feeSweeper
+	metricsServer  *http.Server
 
 	/* Config */
 	isOperator            bool // contract operator
 	operatorPrivateKey    *ecdsa.PrivateKey
-	operatorAddress       common.Address
 	plasmaContractAddress common.Address
 	blockCommitmentRate   time.Duration
 	nodeURL               string // client that satisfies the web3 interface
 	blockFinality         uint64 // presumed finality bound for the ethereum network
+	deploymentBlock       uint64 // ethereum block the contract was deployed in
+	timePegHeight         uint64 // plasma block pegged by block time onwards. 0 if disabled
+	includeDeposits       bool   // operator automatically includes finalized deposits
+	verifyHeaders         bool   // non-operators verify the headers committed to the rootchain
+	tendermintRPCAddress  string // rpc endpoint of the local tendermint node
+	submissionConfig      eth.SubmissionConfig
+	metricsAddress        string // prometheus metrics are not served if empty
+	feePolicy             handlers.FeePolicy
+	mempoolLimits         handlers.MempoolLimits
+	feeSweepInterval      time.Duration // operator does not sweep fees if zero
+	feeSweepThreshold     int
+	storeOnly             bool // the rootchain is not connected and no background service is started
+	genesisFile           string
+
+	legacyGenesis *GenesisState // validator of a chain started by an earlier release, persisted in the next block
+	legacyWallets bool          // wallets stored by an earlier release, migrated in the next block
 }
 
-// NewPlasmaMVPChain creates a PlasmaMVPChain instance
-func NewPlasmaMVPChain(logger log.Logger, db dbm.DB, traceStore io.Writer, options ...func(*PlasmaMVPChain)) *PlasmaMVPChain {
+// NewPlasmaMVPChain creates a PlasmaMVPChain instance. An error is returned if
+// the stores cannot be loaded or the rootchain contract cannot be bound to
+func NewPlasmaMVPChain(logger log.Logger, db dbm.DB, traceStore io.Writer, options ...func(*PlasmaMVPChain)) (*PlasmaMVPChain, error) {
 	baseApp := baseapp.NewBaseApp(appName, logger, db, msgs.TxDecoder)
 	cdc := MakeCodec()
 	baseApp.SetCommitMultiStoreTracer(traceStore)
@@ -67,48 +113,62 @@ func NewPlasmaMVPChain(logger log.Logger, db dbm.DB, traceStore io.Writer, optio
 		txIndex:   0,
 		feeAmount: big.NewInt(0), // we do not use `utils.BigZero` because the feeAmount is going to be updated
 
-		dataStore: dataStore,
+		validatorUpdates: make(map[string]uint64),
+
+		dataStoreKey: dataStoreKey,
+		dataStore:    dataStore,
 	}
 
 	// set configs
 	for _, option := range options {
 		option(app)
 	}
+	app.mempoolLimiter = handlers.NewMempoolLimiter(app.mempoolLimits)
+	app.feeSchedule = handlers.NewFeeSchedule(app.feePolicy)
+
+	// the state is read offline, without the rootchain
+	if app.storeOnly {
+		if err := app.loadStores(db); err != nil {
+			return nil, err
+		}
+		return app, nil
+	}
 
-	// connect to remote client
+	// connect to remote client. The node is reconnected in the background if it cannot be reached
 	eth.SetLogger(logger)
-	ethClient, err := eth.InitEthConn(app.nodeURL)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	ethClient := eth.ConnectEthConn(app.nodeURL)
+	if !ethClient.Healthy() {
+		logger.Error(fmt.Sprintf("ethereum node is %s. transactions are rejected until it is available", ethClient.Health().Status))
 	}
 	plasmaClient, err := eth.InitPlasma(app.plasmaContractAddress, ethClient, app.blockFinality)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return nil, err
+	}
+	plasmaClient, err = plasmaClient.WithDeploymentBlock(app.deploymentBlock).WithCache(rootchainPollInterval)
+	if err == nil && app.timePegHeight > 0 {
+		plasmaClient, err = plasmaClient.WithTimePeg(app.timePegHeight)
+	}
+	if err != nil {
+		return nil, err
 	}
 	if app.isOperator {
 		plasmaClient, err = plasmaClient.WithOperatorSession(app.operatorPrivateKey, app.blockCommitmentRate)
+		if err == nil {
+			plasmaClient, err = plasmaClient.WithSubmissionConfig(app.submissionConfig)
+		}
 	}
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return nil, err
 	}
 	app.ethConnection = plasmaClient
 
-	// query for the operator address
-	addr, err := plasmaClient.OperatorAddress()
-	if err != nil {
-		logger.Error("unable to query the contract for the operator address")
-		fmt.Println(err)
-		os.Exit(1)
-	}
-	app.operatorAddress = addr
-
 	// Route spends to the handler
-	nextTxIndex := func() uint16 {
+	nextTxIndex := func() (uint16, bool) {
+		if app.txIndex >= app.maxTxsPerBlock {
+			return 0, false
+		}
 		app.txIndex++
-		return app.txIndex - 1
+		return app.txIndex - 1, true
 	}
 	feeUpdater := func(amt *big.Int) sdk.Error {
 		app.feeAmount = app.feeAmount.Add(app.feeAmount, amt)
@@ -116,26 +176,80 @@ func NewPlasmaMVPChain(logger log.Logger, db dbm.DB, traceStore io.Writer, optio
 	}
 	app.Router().AddRoute(msgs.SpendMsgRoute, handlers.NewSpendHandler(app.dataStore, nextTxIndex, feeUpdater))
 	app.Router().AddRoute(msgs.IncludeDepositMsgRoute, handlers.NewDepositHandler(app.dataStore, nextTxIndex, plasmaClient))
+	app.Router().AddRoute(msgs.FeeAddressMsgRoute, handlers.NewFeeAddressHandler(app.dataStore, nextTxIndex))
+	validatorUpdater := func(consPubKey []byte, power uint64) {
+		app.validatorUpdates[string(consPubKey)] = power
+	}
+	app.Router().AddRoute(msgs.ValidatorMsgRoute, handlers.NewValidatorHandler(app.dataStore, nextTxIndex, validatorUpdater))
+
+	// validators check the headers committed by the operator
+	var headerVerifier *eth.HeaderVerifier
+	if !app.isOperator && app.verifyHeaders {
+		if app.verifierDB == nil {
+			app.verifierDB = dbm.NewMemDB()
+		}
+		headerVerifier = plasmaClient.NewHeaderVerifier(app.verifierDB)
+	}
 
 	// custom queriers
 	app.QueryRouter().AddRoute(store.QuerierRouteName, store.NewQuerier(app.dataStore))
+	app.QueryRouter().AddRoute(OperatorQuerierRouteName, newOperatorQuerier(plasmaClient, headerVerifier))
+	app.QueryRouter().AddRoute(handlers.FeeQuerierRouteName, handlers.NewFeeQuerier(app.feeSchedule))
+	if app.mailboxDB != nil {
+		app.QueryRouter().AddRoute(store.MailboxQuerierRouteName, store.NewMailboxQuerier(app.dataStore, store.NewMailbox(app.mailboxDB)))
+	}
 
 	// Set the AnteHandler
-	app.SetAnteHandler(handlers.NewAnteHandler(app.dataStore, plasmaClient))
+	app.SetAnteHandler(meteredAnteHandler(handlers.NewAnteHandler(app.dataStore, plasmaClient, app.feeSchedule, app.mempoolLimiter)))
 
 	// set the rest of the chain flow
+	app.SetBeginBlocker(app.beginBlocker)
 	app.SetEndBlocker(app.endBlocker)
 	app.SetInitChainer(app.initChainer)
 
-	// mount and load stores
-	// IAVL store used by default. `fauxMerkleMode` defaults to false
-	app.MountStores(dataStoreKey)
-	if err := app.LoadLatestVersion(dataStoreKey); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if err := app.loadStores(db); err != nil {
+		return nil, err
+	}
+	if err := app.loadLegacyGenesis(); err != nil {
+		return nil, err
+	}
+
+	if app.metricsAddress != "" {
+		app.metricsServer = metrics.NewServer(app.metricsAddress)
+		go func() {
+			logger.Info(fmt.Sprintf("serving prometheus metrics on %s", app.metricsAddress))
+			if err := app.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error(fmt.Sprintf("error serving prometheus metrics: %s", err))
+			}
+		}()
+	}
+
+	// the operator submits plasma headers to the rootchain
+	if app.isOperator {
+		app.committer = newHeaderCommitter(app.committedState, app.dataStore, plasmaClient, logger)
+		app.committer.start(headerCommitInterval)
+	}
+
+	// committed headers are verified against the blocks agreed upon by the validators
+	if headerVerifier != nil {
+		app.verifier = newHeaderVerifier(app.committedState, app.dataStore, headerVerifier, logger)
+		app.verifier.start(headerVerifyInterval)
+	}
+
+	// the operator credits deposits on behalf of the depositors
+	if app.isOperator && app.includeDeposits && app.tendermintRPCAddress != "" {
+		includer := newDepositIncluder(app, app.tendermintRPCAddress)
+		app.depositWatcher = plasmaClient.NewDepositWatcher(includer, depositPollInterval)
+		app.depositWatcher.Start()
 	}
 
-	return app
+	// the operator consolidates the fee outputs of each block
+	if app.isOperator && app.feeSweepInterval > 0 && app.tendermintRPCAddress != "" {
+		app.feeSweeper = newFeeSweeper(app, plasmaClient, app.tendermintRPCAddress, app.feeSweepThreshold, logger)
+		app.feeSweeper.start(app.feeSweepInterval)
+	}
+
+	return app, nil
 }
 
 // initChainer initializes genesis state before the chain begins
@@ -147,23 +261,140 @@ func (app *PlasmaMVPChain) initChainer(ctx sdk.Context, req abci.RequestInitChai
 		panic(err) // TODO https://github.com/cosmos/cosmos-sdk/issues/468
 		// return sdk.ErrGenesisParse("").TraceCause(err, "")
 	}
+	if genesisState.Version != "" && genesisState.Version != GenesisVersion {
+		panic(fmt.Sprintf("unsupported genesis version %s. expected version %s", genesisState.Version, GenesisVersion))
+	}
+
+	if err := genesisState.ValidateBasic(); err != nil {
+		panic(fmt.Sprintf("invalid genesis state: %s", err))
+	}
+
+	// load the exported plasma state
+	if err := app.dataStore.InitGenesis(ctx, genesisState.Data); err != nil {
+		panic(fmt.Sprintf("invalid genesis state: %s", err))
+	}
+
+	// persist the validator so that it can be exported
+	app.storeValidator(ctx, genesisState)
+
+	// load the initial stake information. Fees are only collected by the first validator
+	var validators []abci.ValidatorUpdate
+	var totalPower int64
+	seen := make(map[string]bool)
+	for i, validator := range append([]GenesisValidator{genesisState.Validator}, genesisState.Validators...) {
+		if i > 0 && validator.Address != "" {
+			panic("fee address specified for an additional validator. fees are collected by the first validator")
+		}
+		if validator.power() < 0 {
+			panic("validator voting power must be positive")
+		}
+
+		consPubKey := app.cdc.MustMarshalBinaryBare(validator.ConsPubKey)
+		if seen[string(consPubKey)] {
+			panic(fmt.Sprintf("duplicate validator %X in genesis", consPubKey))
+		}
+		seen[string(consPubKey)] = true
+
+		totalPower += validator.power()
+		if totalPower > msgs.MaxValidatorPower {
+			panic(fmt.Sprintf("total voting power of the validator set must not exceed %d", int64(msgs.MaxValidatorPower)))
+		}
+
+		app.dataStore.SetValidatorPower(ctx, consPubKey, uint64(validator.power()))
+		validators = append(validators, abci.ValidatorUpdate{
+			PubKey: tmtypes.TM2PB.PubKey(validator.ConsPubKey),
+			Power:  validator.power(),
+		})
+	}
+	app.dataStore.SetValidatorUpdateNonce(ctx, genesisState.ValidatorUpdateNonce)
+
+	params := store.DefaultParams()
+	if genesisState.MaxTxsPerBlock != 0 {
+		params.MaxTxsPerBlock = genesisState.MaxTxsPerBlock
+	}
+	app.dataStore.StoreParams(ctx, params)
+	app.setParams(ctx)
+
+	return abci.ResponseInitChain{Validators: validators}
+}
+
+// loadStores mounts and loads the stores at the latest committed version
+func (app *PlasmaMVPChain) loadStores(db dbm.DB) error {
+	// IAVL store used by default. `fauxMerkleMode` defaults to false
+	app.MountStores(app.dataStoreKey)
+	if err := app.LoadLatestVersion(app.dataStoreKey); err != nil {
+		return err
+	}
+	app.legacyWallets = app.dataStore.HasLegacyWallets(app.NewContext(true, abci.Header{}))
+	app.setParams(app.NewContext(true, abci.Header{}))
+	app.committedHeight = app.LastBlockHeight()
+	app.committedState = newCommittedState(db, app.dataStoreKey, func() int64 {
+		return atomic.LoadInt64(&app.committedHeight)
+	}, app.Logger())
+
+	return nil
+}
 
-	// load the initial stake information
-	return abci.ResponseInitChain{Validators: []abci.ValidatorUpdate{abci.ValidatorUpdate{
-		PubKey: tmtypes.TM2PB.PubKey(genesisState.Validator.ConsPubKey),
-		Power:  1,
-	}}}
+// storeValidator persists the validator of `genesisState`, which collects the
+// fees, and the operator, which updates the validator set
+func (app *PlasmaMVPChain) storeValidator(ctx sdk.Context, genesisState GenesisState) {
+	app.dataStore.SetOperator(ctx, common.HexToAddress(genesisState.Operator))
+	app.dataStore.StoreValidator(ctx, store.Validator{
+		ConsPubKey: app.cdc.MustMarshalBinaryBare(genesisState.Validator.ConsPubKey),
+		FeeAddress: common.HexToAddress(genesisState.Validator.Address),
+	})
+	app.dataStore.SetFeeAddressNonce(ctx, genesisState.Validator.FeeAddressNonce)
+}
+
+// setParams applies the consensus parameters stored in genesis. Chains
+// started before the parameters were stored use the defaults
+func (app *PlasmaMVPChain) setParams(ctx sdk.Context) {
+	params, ok := app.dataStore.GetParams(ctx)
+	if !ok {
+		params = store.DefaultParams()
+	}
+
+	app.maxTxsPerBlock = params.MaxTxsPerBlock
+	app.mempoolLimiter.SetMaxPending(int(params.MaxTxsPerBlock))
+}
+
+// DeliverTx records the transaction bytes before delivering the transaction.
+// Every transaction of the block is part of the merkle tree in the block
+// header, regardless of the result of its execution. If the rootchain state a
+// transaction depends on has not been mirrored yet, the transaction is
+// delivered again once the mirror has caught up, rather than commit a result
+// that differs from the other validators. A transaction failing with
+// ErrRootchainUnavailable has not written to the state nor taken up a tx index
+func (app *PlasmaMVPChain) DeliverTx(txBytes []byte) abci.ResponseDeliverTx {
+	app.blockTxs = append(app.blockTxs, txBytes)
+	res := app.BaseApp.DeliverTx(txBytes)
+	for handlers.IsRootchainUnavailable(res.Codespace, res.Code) {
+		app.Logger().Error(fmt.Sprintf("rootchain state required to deliver the block is not available. retrying in %s: %s", rootchainRetryInterval, res.Log))
+		time.Sleep(rootchainRetryInterval)
+		res = app.BaseApp.DeliverTx(txBytes)
+	}
+
+	return res
+}
+
+// Commit resets the mempool limits once the block is committed. Tendermint
+// rechecks the transactions left in the mempool afterwards, counting them again
+func (app *PlasmaMVPChain) Commit() abci.ResponseCommit {
+	res := app.BaseApp.Commit()
+	atomic.StoreInt64(&app.committedHeight, app.LastBlockHeight())
+	app.mempoolLimiter.Reset()
+	return res
 }
 
 // Reset state at the end of each block
 func (app *PlasmaMVPChain) endBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
 	ds := app.dataStore
+	validatorUpdates := app.endValidatorUpdates()
 
 	// skip if the block is empty
 	if app.txIndex == 0 {
-		// try to commit any headers in the store
-		app.ethConnection.CommitPlasmaHeaders(ctx, ds)
-		return abci.ResponseEndBlock{}
+		app.blockTxs = nil
+		return abci.ResponseEndBlock{ValidatorUpdates: validatorUpdates}
 	}
 
 	tmBlockHeight := uint64(ctx.BlockHeight())
@@ -173,27 +404,194 @@ func (app *PlasmaMVPChain) endBlocker(ctx sdk.Context, req abci.RequestEndBlock)
 	copy(header[:], ctx.BlockHeader().DataHash)
 	block := plasma.NewBlock(header, app.txIndex, app.feeAmount, plasmaBlockHeight)
 	ds.StoreBlock(ctx, tmBlockHeight, block)
+	ds.StoreBlockTree(ctx, plasmaBlockHeight, app.blockTxs)
+
+	metrics.PlasmaBlockHeight.Set(float64(plasmaBlockHeight.Int64()))
+	metrics.TxsPerBlock.Observe(float64(app.txIndex))
+	fees, _ := new(big.Float).SetInt(app.feeAmount).Float64()
+	metrics.Fees.Add(fees)
 
 	if app.feeAmount.Sign() == 1 {
-		ds.StoreFee(ctx, plasmaBlockHeight, plasma.NewOutput(app.operatorAddress, app.feeAmount))
+		ds.StoreFee(ctx, plasmaBlockHeight, plasma.NewOutput(app.feeRecipient(ctx), app.feeAmount))
 	}
 
-	app.ethConnection.CommitPlasmaHeaders(ctx, ds)
-
 	app.txIndex = 0
 	app.feeAmount = big.NewInt(0)
+	app.blockTxs = nil
+
+	return abci.ResponseEndBlock{ValidatorUpdates: validatorUpdates}
+}
+
+// endValidatorUpdates returns the validator updates of the current block
+// ordered by public key and resets them for the next block
+func (app *PlasmaMVPChain) endValidatorUpdates() []abci.ValidatorUpdate {
+	if len(app.validatorUpdates) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(app.validatorUpdates))
+	for key := range app.validatorUpdates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	updates := make([]abci.ValidatorUpdate, 0, len(keys))
+	for _, key := range keys {
+		var pubKey crypto.PubKey
+		if err := app.cdc.UnmarshalBinaryBare([]byte(key), &pubKey); err != nil {
+			panic(fmt.Sprintf("invalid validator public key: %s", err))
+		}
+
+		updates = append(updates, abci.ValidatorUpdate{
+			PubKey: tmtypes.TM2PB.PubKey(pubKey),
+			Power:  int64(app.validatorUpdates[key]),
+		})
+	}
+
+	app.validatorUpdates = make(map[string]uint64)
+	return updates
+}
+
+// feeRecipient returns the fee address of the validator. The fee address is
+// persisted in genesis, or when a chain started by an earlier release is
+// migrated, so the rootchain is never consulted when a block ends
+func (app *PlasmaMVPChain) feeRecipient(ctx sdk.Context) common.Address {
+	validator, ok := app.dataStore.GetValidator(ctx)
+	if !ok || utils.IsZeroAddress(validator.FeeAddress) {
+		panic("Corrupted store: fee address of the validator not found")
+	}
+
+	return validator.FeeAddress
+}
+
+// SetFeePolicy replaces the fee policy spends must pay to enter the mempool of
+// the node. The policy is local to the node, so it may change without a restart
+func (app *PlasmaMVPChain) SetFeePolicy(policy handlers.FeePolicy) {
+	app.feeSchedule.SetPolicy(policy)
+	app.Logger().Info(fmt.Sprintf("fee policy updated. minimum fee: %s, fee per byte: %s", policy.MinFee, policy.FeePerByte))
+}
+
+// Stop halts the background services of the app. The header committer
+// finishes any submission in progress before returning
+func (app *PlasmaMVPChain) Stop() {
+	if app.committer != nil {
+		app.committer.stop()
+	}
+	if app.verifier != nil {
+		app.verifier.stop()
+	}
+	if app.depositWatcher != nil {
+		app.depositWatcher.Stop()
+	}
+	if app.feeSweeper != nil {
+		app.feeSweeper.stop()
+	}
+	if app.ethConnection != nil {
+		app.ethConnection.StopCache()
+		app.ethConnection.Client().Close()
+	}
+	if app.metricsServer != nil {
+		app.metricsServer.Close()
+	}
+	if app.mailboxDB != nil {
+		app.mailboxDB.Close()
+	}
+	if app.verifierDB != nil {
+		app.verifierDB.Close()
+	}
+}
+
+// meteredAnteHandler counts the transactions rejected by `anteHandler` by error code
+func meteredAnteHandler(anteHandler sdk.AnteHandler) sdk.AnteHandler {
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, sdk.Result, bool) {
+		newCtx, res, abort := anteHandler(ctx, tx, simulate)
+		if abort {
+			metrics.AnteRejections.WithLabelValues(string(res.Codespace), strconv.Itoa(int(res.Code))).Inc()
+		}
 
-	return abci.ResponseEndBlock{}
+		return newCtx, res, abort
+	}
 }
 
-// ExportAppStateJSON exports the current applicatoin state into JSON.
+// ExportAppStateJSON exports the current application state into JSON. The
+// exported state can be loaded by `initChainer`.
 func (app *PlasmaMVPChain) ExportAppStateJSON() (appState json.RawMessage, validators []tmtypes.GenesisValidator, err error) {
-	// TODO: Implement
-	// Currently non-functional, just enough to compile
-	tx := msgs.SpendMsg{}
-	appState, err = json.MarshalIndent(tx, "", "\t")
-	validators = []tmtypes.GenesisValidator{}
-	return appState, validators, err
+	ctx := app.NewContext(true, abci.Header{})
+	// the migration is not committed
+	if app.legacyWallets {
+		app.dataStore.MigrateWallets(ctx)
+	}
+
+	validator, ok := app.dataStore.GetValidator(ctx)
+	if !ok {
+		return nil, nil, fmt.Errorf("no validator exists in the store")
+	}
+
+	operator, ok := app.dataStore.GetOperator(ctx)
+	if !ok {
+		return nil, nil, fmt.Errorf("no operator exists in the store")
+	}
+
+	var feeAddress string
+	if !utils.IsZeroAddress(validator.FeeAddress) {
+		feeAddress = validator.FeeAddress.Hex()
+	}
+
+	// fees are collected by the stored validator. The first member of the set
+	// collects the fees if the stored validator has been removed
+	var genesisValidators []GenesisValidator
+	first := 0
+	for i, member := range app.dataStore.GetValidatorSet(ctx) {
+		var pubKey crypto.PubKey
+		if err := app.cdc.UnmarshalBinaryBare(member.ConsPubKey, &pubKey); err != nil {
+			return nil, nil, fmt.Errorf("amino: %s", err)
+		}
+		if bytes.Equal(member.ConsPubKey, validator.ConsPubKey) {
+			first = i
+		}
+
+		genesisValidators = append(genesisValidators, GenesisValidator{ConsPubKey: pubKey, Power: int64(member.Power)})
+		validators = append(validators, tmtypes.GenesisValidator{
+			Address: pubKey.Address(),
+			PubKey:  pubKey,
+			Power:   int64(member.Power),
+		})
+	}
+	if len(genesisValidators) == 0 {
+		return nil, nil, fmt.Errorf("the validator set is empty")
+	}
+
+	genesisValidator := genesisValidators[first]
+	genesisValidator.Address = feeAddress
+	genesisValidator.FeeAddressNonce = app.dataStore.GetFeeAddressNonce(ctx)
+	genesisValidators = append(genesisValidators[:first], genesisValidators[first+1:]...)
+
+	params, ok := app.dataStore.GetParams(ctx)
+	if !ok {
+		params = store.DefaultParams()
+	}
+
+	genesisState := GenesisState{
+		Version:              GenesisVersion,
+		Validator:            genesisValidator,
+		Validators:           genesisValidators,
+		Operator:             operator.Hex(),
+		ValidatorUpdateNonce: app.dataStore.GetValidatorUpdateNonce(ctx),
+		MaxTxsPerBlock:       params.MaxTxsPerBlock,
+		Data:                 app.dataStore.ExportGenesis(ctx),
+	}
+
+	appState, err = codec.MarshalJSONIndent(app.cdc, genesisState)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return appState, validators, nil
+}
+
+// LoadHeight loads the state at the specified tendermint block height
+func (app *PlasmaMVPChain) LoadHeight(height int64) error {
+	return app.LoadVersion(height, app.dataStoreKey)
 }
 
 // MakeCodec returns a new codec with registered sdk and crypto types
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/eth"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/tendermint/tendermint/libs/log"
+	"time"
+)
+
+// interval at which the committer checks for plasma blocks to submit
+const headerCommitInterval = 5 * time.Second
+
+// headerCommitter submits plasma headers to the rootchain on its own schedule so
+// that block production never waits on the ethereum node. Blocks are read from
+// the read-only view of the committed state.
+type headerCommitter struct {
+	plasma *eth.Plasma
+	ds     store.DataStore
+	state  *committedState
+	logger log.Logger
+
+	task *backgroundTask
+}
+
+func newHeaderCommitter(state *committedState, ds store.DataStore, plasma *eth.Plasma, logger log.Logger) *headerCommitter {
+	return &headerCommitter{
+		plasma: plasma,
+		ds:     ds,
+		state:  state,
+		logger: logger,
+		task:   newBackgroundTask("error committing plasma headers", logger),
+	}
+}
+
+// start commits headers every `interval` in a separate goroutine
+func (c *headerCommitter) start(interval time.Duration) {
+	c.task.start(interval, c.commit)
+}
+
+// stop halts the committer. A submission in progress is completed before returning
+func (c *headerCommitter) stop() {
+	c.task.stop()
+}
+
+// commit submits all plasma blocks in the latest committed state that have not
+// been committed to the rootchain
+func (c *headerCommitter) commit() error {
+	ctx, err := c.state.context()
+	if err != nil {
+		return err
+	}
+
+	return c.plasma.CommitPlasmaHeaders(ctx, c.ds)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	abci "github.com/tendermint/tendermint/abci/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"math/big"
+	"time"
+)
+
+// interval at which the operator checks the rootchain for new deposits
+const depositPollInterval = 5 * time.Second
+
+// depositIncluder implements eth.DepositIncluder. Include-deposit transactions
+// are broadcasted through the rpc endpoint of the local tendermint node
+type depositIncluder struct {
+	app    *PlasmaMVPChain
+	client rpcclient.Client
+}
+
+func newDepositIncluder(app *PlasmaMVPChain, rpcAddress string) depositIncluder {
+	return depositIncluder{
+		app:    app,
+		client: rpcclient.NewHTTP(rpcAddress, "/websocket"),
+	}
+}
+
+// HasDeposit checks the latest committed sidechain state for the deposit
+func (includer depositIncluder) HasDeposit(nonce *big.Int) bool {
+	ctx, err := includer.app.committedState.context()
+	if err != nil {
+		includer.app.Logger().Error(fmt.Sprintf("error checking for deposit %s: %s", nonce, err))
+		return false
+	}
+
+	return includer.app.dataStore.HasDeposit(ctx, nonce)
+}
+
+// IncludeDeposit broadcasts an include-deposit transaction for the deposit
+func (includer depositIncluder) IncludeDeposit(nonce *big.Int, owner common.Address, replayNonce uint64) error {
+	msg := msgs.IncludeDepositMsg{
+		DepositNonce: nonce,
+		Owner:        owner,
+		ReplayNonce:  replayNonce,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	txBytes, err := rlp.EncodeToBytes(&msg)
+	if err != nil {
+		return err
+	}
+
+	res, err := includer.client.BroadcastTxSync(txBytes)
+	if err != nil {
+		return err
+	}
+	if res.Code != abci.CodeTypeOK {
+		return fmt.Errorf("transaction rejected: %s", res.Log)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/eth"
+	"github.com/FourthState/plasma-mvp-sidechain/handlers"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"time"
+)
+
+// feeSweeper periodically consolidates the fee outputs collected to the fee
+// address into a single output. Sweeps are signed with the operator key, so
+// fees collected to a separate fee address are not swept. Each sweep spends the
+// output of the previous sweep along with the next fee output, so sweeps are
+// chained until the fee outputs are consolidated. Sweeps are broadcasted through the rpc endpoint of
+// the local tendermint node and wait for the block to be committed so that the
+// next sweep, read from the committed state, spends the output of the previous one
+type feeSweeper struct {
+	app       *PlasmaMVPChain
+	plasma    *eth.Plasma
+	client    rpcclient.Client
+	key       *ecdsa.PrivateKey
+	threshold int // minimum number of fee outputs to sweep
+	logger    log.Logger
+	skipped   common.Address // last fee address that could not be swept
+
+	task *backgroundTask
+}
+
+func newFeeSweeper(app *PlasmaMVPChain, plasma *eth.Plasma, rpcAddress string, threshold int, logger log.Logger) *feeSweeper {
+	return &feeSweeper{
+		app:       app,
+		plasma:    plasma,
+		client:    rpcclient.NewHTTP(rpcAddress, "/websocket"),
+		key:       app.operatorPrivateKey,
+		threshold: threshold,
+		logger:    logger,
+		task:      newBackgroundTask("error sweeping fees", logger),
+	}
+}
+
+// start sweeps fees every `interval` in a separate goroutine. An error is
+// logged right away if the current fee address cannot be swept
+func (s *feeSweeper) start(interval time.Duration) {
+	if ctx, err := s.app.committedState.context(); err == nil {
+		if owner, ok := s.owner(ctx); !ok {
+			s.warnSkipped(owner)
+		}
+	}
+	s.task.start(interval, s.sweep)
+}
+
+// stop halts the sweeper. A sweep in progress is completed before returning
+func (s *feeSweeper) stop() {
+	s.task.stop()
+}
+
+// sweep chains sweeps of the unspent fee outputs of the fee address if at least
+// `threshold` of them have not been exited
+func (s *feeSweeper) sweep() error {
+	ctx, err := s.app.committedState.context()
+	if err != nil {
+		return err
+	}
+	owner, ok := s.owner(ctx)
+	if !ok {
+		s.warnSkipped(owner)
+		return nil
+	}
+	fees := s.app.dataStore.GetFeeOutputs(ctx, owner)
+
+	unspent := 0
+	for _, fee := range fees.Fees {
+		exited, err := s.plasma.HasTxExited(nil, time.Time{}, fee.Position)
+		if err != nil {
+			return fmt.Errorf("checking for exited fees: %s", err)
+		}
+		if !exited {
+			unspent++
+		}
+	}
+	if unspent < s.threshold {
+		return nil
+	}
+
+	for !s.task.stopping() {
+		if swept, err := s.sweepNext(); err != nil || !swept {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sweepNext broadcasts the next sweep of the unspent fee outputs of the fee address.
+// False is returned if there is nothing left to consolidate
+func (s *feeSweeper) sweepNext() (bool, error) {
+	ctx, err := s.app.committedState.context()
+	if err != nil {
+		return false, err
+	}
+	owner, ok := s.owner(ctx)
+	if !ok {
+		return false, nil
+	}
+	fees := s.app.dataStore.GetFeeOutputs(ctx, owner)
+
+	exited := func(pos plasma.Position) bool {
+		ok, e := s.plasma.HasTxExited(nil, time.Time{}, pos)
+		if e != nil {
+			err = e
+			return true
+		}
+		return ok
+	}
+	sweep, ok := handlers.NewFeeSweep(fees, owner, s.app.feeSchedule.Policy(), exited)
+	if err != nil {
+		return false, fmt.Errorf("checking for exited fees: %s", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	tx, err := sweep.Sign(func(hash []byte) ([]byte, error) {
+		return crypto.Sign(utils.ToEthSignedMessageHash(hash), s.key)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	txBytes, err := rlp.EncodeToBytes(&msgs.SpendMsg{Transaction: tx})
+	if err != nil {
+		return false, err
+	}
+	res, err := s.client.BroadcastTxCommit(txBytes)
+	if err != nil {
+		return false, err
+	}
+	if res.CheckTx.Code != abci.CodeTypeOK {
+		return false, fmt.Errorf("sweep rejected: %s", res.CheckTx.Log)
+	}
+	if res.DeliverTx.Code != abci.CodeTypeOK {
+		return false, fmt.Errorf("sweep failed: %s", res.DeliverTx.Log)
+	}
+
+	s.logger.Info(fmt.Sprintf("swept %d outputs into %s", len(tx.Inputs), tx.Outputs[0].Amount))
+	return true, nil
+}
+
+// owner returns the current fee address. False is returned if the fees it
+// collects cannot be signed for with the operator key
+func (s *feeSweeper) owner(ctx sdk.Context) (common.Address, bool) {
+	validator, _ := s.app.dataStore.GetValidator(ctx)
+	return validator.FeeAddress, validator.FeeAddress == crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// warnSkipped logs once per fee address that the fees it collects are not swept.
+// No fee address is set before genesis
+func (s *feeSweeper) warnSkipped(owner common.Address) {
+	if utils.IsZeroAddress(owner) || owner == s.skipped {
+		return
+	}
+	s.skipped = owner
+	s.logger.Error(fmt.Sprintf("fee sweeping is enabled but fees are collected to 0x%x, which is not the operator. "+
+		"fees are only swept while they are collected to the operator", owner))
+}
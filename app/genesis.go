@@ -1,25 +1,89 @@
 package app
 
 import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/tendermint/tendermint/crypto"
 )
 
-// GenesisState specifies the validator of the chain
+// GenesisVersion is the version of the genesis format produced by
+// `plasmad export`. Genesis files without a version only specify the
+// validator.
+const GenesisVersion = "2"
+
+// GenesisState specifies the validators of the chain and the plasma state
+// the chain starts from
 type GenesisState struct {
+	Version string `json:"version"`
+
+	// Validator collects the fees
 	Validator GenesisValidator `json:"validator"`
+	// Validators lists any additional members of the validator set
+	Validators []GenesisValidator `json:"validators"`
+
+	// Operator is the ethereum address authorized to update the validator set
+	Operator string `json:"operator"`
+	// ValidatorUpdateNonce is the number of updates made to the validator set
+	// before the state was exported
+	ValidatorUpdateNonce uint64 `json:"validator_update_nonce"`
+
+	// MaxTxsPerBlock bounds the transactions of a plasma block. Defaults to 65535
+	MaxTxsPerBlock uint16             `json:"max_txs_per_block"`
+	Data           store.GenesisState `json:"data"`
 }
 
 // GenesisValidator holds the consensus public key and fee address of
-// the validator. ConsPubKey is tendermint Ed25119 public key for signing
-// consensus messages. Address is Ethereum address for collecting fees.
+// the validator.
 type GenesisValidator struct {
+	// ConsPubKey is tendermint Ed25119 public key for signing consensus messages
 	ConsPubKey crypto.PubKey `json:"validator_pubkey"`
-	Address    string        `json:"fee_address"`
+	// Address is Ethereum address for collecting fees. Must be set for the first validator
+	Address string `json:"fee_address"`
+	// FeeAddressNonce is the number of updates made to the fee address before
+	// the state was exported
+	FeeAddressNonce uint64 `json:"fee_address_nonce"`
+	// Power is the voting power of the validator. Defaults to 1
+	Power int64 `json:"power"`
+}
+
+// power returns the voting power of the validator
+func (v GenesisValidator) power() int64 {
+	if v.Power == 0 {
+		return 1
+	}
+
+	return v.Power
+}
+
+// ValidateBasic checks that the fee address of the validator and the operator
+// are set, so that the genesis state never depends on the rootchain
+func (state GenesisState) ValidateBasic() error {
+	if state.Validator.Address == "" {
+		return fmt.Errorf("fee_address of the validator is not set")
+	} else if !common.IsHexAddress(state.Validator.Address) {
+		return fmt.Errorf("invalid fee address. please use hex format")
+	} else if utils.IsZeroAddress(common.HexToAddress(state.Validator.Address)) {
+		return fmt.Errorf("fees cannot be collected to the zero address")
+	}
+
+	if state.Operator == "" {
+		return fmt.Errorf("operator is not set")
+	} else if !common.IsHexAddress(state.Operator) || utils.IsZeroAddress(common.HexToAddress(state.Operator)) {
+		return fmt.Errorf("invalid operator address. please use a non-zero address in hex format")
+	}
+
+	return nil
 }
 
-// NewDefaultGenesisState returns a GenesisState instance
-func NewDefaultGenesisState(pubKey crypto.PubKey) GenesisState {
+// NewDefaultGenesisState returns a GenesisState instance collecting fees to
+// `feeAddress` with the validator set updated by `operator`
+func NewDefaultGenesisState(pubKey crypto.PubKey, feeAddress, operator string) GenesisState {
 	return GenesisState{
-		Validator: GenesisValidator{pubKey, ""},
+		Version:        GenesisVersion,
+		Validator:      GenesisValidator{pubKey, feeAddress, 0, 1},
+		Operator:       operator,
+		MaxTxsPerBlock: store.DefaultMaxTxsPerBlock,
 	}
 }
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// loadLegacyGenesis prepares the migration of chains started by a release that
+// did not persist the validator. Such chains collected fees to the operator of
+// the rootchain contract as seen by each node. The validator is read from the
+// genesis file shared by every node instead, so that the migration is
+// deterministic, and persisted at the start of the next block
+func (app *PlasmaMVPChain) loadLegacyGenesis() error {
+	if app.LastBlockHeight() == 0 {
+		return nil
+	}
+	if _, ok := app.dataStore.GetValidator(app.NewContext(true, abci.Header{})); ok {
+		return nil
+	}
+
+	if app.genesisFile == "" {
+		return fmt.Errorf("the chain was started by an earlier release and the genesis file to migrate it from is not set")
+	}
+	genDoc, err := tmtypes.GenesisDocFromFile(app.genesisFile)
+	if err != nil {
+		return err
+	}
+
+	var genesisState GenesisState
+	if err := app.cdc.UnmarshalJSON(genDoc.AppState, &genesisState); err != nil {
+		return fmt.Errorf("parsing the app state of %s: %s", app.genesisFile, err)
+	}
+	if err := genesisState.ValidateBasic(); err != nil {
+		return fmt.Errorf("the chain was started by an earlier release and is migrated from the app state of %s. "+
+			"Set the same validator and operator on every node before upgrading: %s", app.genesisFile, err)
+	}
+
+	app.Logger().Info(fmt.Sprintf("migrating the chain started by an earlier release. fees are collected to %s from the next block", genesisState.Validator.Address))
+	app.legacyGenesis = &genesisState
+	return nil
+}
+
+// beginBlocker persists the validator and migrates the wallets of a chain
+// started by an earlier release in the first block after the upgrade
+func (app *PlasmaMVPChain) beginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	if app.legacyGenesis != nil {
+		app.storeValidator(ctx, *app.legacyGenesis)
+		app.legacyGenesis = nil
+	}
+	if app.legacyWallets {
+		app.Logger().Info("migrating the wallets stored by an earlier release")
+		app.dataStore.MigrateWallets(ctx)
+		app.legacyWallets = false
+	}
+
+	return abci.ResponseBeginBlock{}
+}
@@ -5,8 +5,12 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmad/config"
+	"github.com/FourthState/plasma-mvp-sidechain/eth"
+	"github.com/FourthState/plasma-mvp-sidechain/handlers"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"math/big"
 	"strconv"
 	"time"
 )
@@ -38,6 +42,16 @@ func SetPlasmaOptionsFromConfig(conf config.PlasmaConfig) func(*PlasmaMVPChain)
 		panic(errMsg)
 	}
 
+	deploymentBlock, err := strconv.ParseUint(conf.EthDeploymentBlock, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("Could not parse the deployment block: %v", err))
+	}
+
+	timePegHeight, err := strconv.ParseUint(conf.TimePegHeight, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("Could not parse the time peg height: %v", err))
+	}
+
 	if !common.IsHexAddress(conf.EthPlasmaContractAddr) {
 		panic("invalid contract address. please use hex format")
 	}
@@ -47,6 +61,70 @@ func SetPlasmaOptionsFromConfig(conf config.PlasmaConfig) func(*PlasmaMVPChain)
 		panic("commitment rate must be able to be parsed into a golang Duration type")
 	}
 
+	gasPrice, ok := new(big.Int).SetString(conf.GasPrice, 10)
+	if !ok || gasPrice.Sign() < 0 {
+		panic("gas price must be a non-negative integer in wei")
+	}
+
+	var gasPriceStrategy eth.GasPriceStrategy
+	switch conf.GasPriceStrategy {
+	case "suggested":
+		gasPriceStrategy = eth.SuggestedGasPrice(gasPrice)
+	case "fixed":
+		if gasPrice.Sign() == 0 {
+			panic("a fixed gas price must be greater than zero")
+		}
+		gasPriceStrategy = eth.FixedGasPrice(gasPrice)
+	default:
+		panic(fmt.Sprintf("unknown gas price strategy %q. use \"suggested\" or \"fixed\"", conf.GasPriceStrategy))
+	}
+
+	gasPriceBump, err := strconv.ParseUint(conf.GasPriceBump, 10, 64)
+	if err != nil {
+		errMsg := fmt.Sprintf("Could not parse gas price bump: %v", err)
+		panic(errMsg)
+	}
+
+	submissionTimeout, err := time.ParseDuration(conf.SubmissionTimeout)
+	if err != nil {
+		panic("submission timeout must be able to be parsed into a golang Duration type")
+	}
+
+	maxBlocksPerSubmission, err := strconv.Atoi(conf.MaxBlocksPerSubmission)
+	if err != nil || maxBlocksPerSubmission <= 0 {
+		panic("max blocks per submission must be a positive integer")
+	}
+
+	feePolicy, err := FeePolicyFromConfig(conf)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	maxSpendsPerAddress, err := strconv.ParseUint(conf.MaxSpendsPerAddress, 10, 16)
+	if err != nil {
+		panic(fmt.Sprintf("Could not parse max spends per address: %v", err))
+	}
+
+	feeFloorThreshold, err := strconv.ParseUint(conf.FeeFloorThreshold, 10, 16)
+	if err != nil {
+		panic(fmt.Sprintf("Could not parse fee floor threshold: %v", err))
+	}
+
+	feeFloor, ok := new(big.Int).SetString(conf.FeeFloor, 10)
+	if !ok || feeFloor.Sign() < 0 {
+		panic("fee floor must be a non-negative integer in wei")
+	}
+
+	feeSweepInterval, err := time.ParseDuration(conf.FeeSweepInterval)
+	if err != nil || feeSweepInterval < 0 {
+		panic("fee sweep interval must be able to be parsed into a non-negative golang Duration type")
+	}
+
+	feeSweepThreshold, err := strconv.ParseUint(conf.FeeSweepThreshold, 10, 16)
+	if err != nil || feeSweepThreshold < 2 {
+		panic("fee sweep threshold must be an integer of at least 2")
+	}
+
 	return func(pc *PlasmaMVPChain) {
 		pc.operatorPrivateKey = privateKey
 		pc.isOperator = conf.IsOperator
@@ -54,5 +132,82 @@ func SetPlasmaOptionsFromConfig(conf config.PlasmaConfig) func(*PlasmaMVPChain)
 		pc.blockCommitmentRate = dur
 		pc.nodeURL = conf.EthNodeURL
 		pc.blockFinality = blockFinality
+		pc.deploymentBlock = deploymentBlock
+		pc.timePegHeight = timePegHeight
+		pc.includeDeposits = conf.IncludeDeposits
+		pc.verifyHeaders = conf.VerifyHeaders
+		pc.submissionConfig = eth.SubmissionConfig{
+			GasPrice:     gasPriceStrategy,
+			GasPriceBump: gasPriceBump,
+			Timeout:      submissionTimeout,
+			MaxBlocks:    maxBlocksPerSubmission,
+		}
+		pc.metricsAddress = conf.PrometheusListenAddr
+		pc.feePolicy = feePolicy
+		pc.mempoolLimits = handlers.MempoolLimits{
+			MaxSpendsPerSigner: int(maxSpendsPerAddress),
+			FeeFloorThreshold:  int(feeFloorThreshold),
+			FeeFloor:           feeFloor,
+		}
+		pc.feeSweepInterval = feeSweepInterval
+		pc.feeSweepThreshold = int(feeSweepThreshold)
+	}
+}
+
+// FeePolicyFromConfig parses the fee policy set in plasma.toml. Used when the
+// node starts and when the policy is reloaded
+func FeePolicyFromConfig(conf config.PlasmaConfig) (handlers.FeePolicy, error) {
+	minFee, ok := new(big.Int).SetString(conf.MinFee, 10)
+	if !ok || minFee.Sign() < 0 {
+		return handlers.FeePolicy{}, fmt.Errorf("minimum fee must be a non-negative integer in wei")
+	}
+
+	feePerByte, ok := new(big.Int).SetString(conf.FeePerByte, 10)
+	if !ok || feePerByte.Sign() < 0 {
+		return handlers.FeePolicy{}, fmt.Errorf("fee per byte must be a non-negative integer in wei")
+	}
+
+	return handlers.FeePolicy{MinFee: minFee, FeePerByte: feePerByte}, nil
+}
+
+// SetTendermintRPCAddress sets the rpc endpoint of the local tendermint node.
+// The operator broadcasts include-deposit transactions through this endpoint.
+func SetTendermintRPCAddress(addr string) func(*PlasmaMVPChain) {
+	return func(pc *PlasmaMVPChain) {
+		pc.tendermintRPCAddress = addr
+	}
+}
+
+// SetGenesisFile sets the path of the genesis file of the chain. Chains started
+// by a release that did not persist the validator are migrated from it.
+func SetGenesisFile(path string) func(*PlasmaMVPChain) {
+	return func(pc *PlasmaMVPChain) {
+		pc.genesisFile = path
+	}
+}
+
+// SetStoreOnly builds the app over its stores alone, without connecting to the
+// rootchain or starting any background service, so that the state can be
+// read offline. The app cannot execute blocks.
+func SetStoreOnly() func(*PlasmaMVPChain) {
+	return func(pc *PlasmaMVPChain) {
+		pc.storeOnly = true
+	}
+}
+
+// SetHeaderVerifierDB persists the progress of the header verifier in the
+// database, so that verification resumes from the last verified block after a
+// restart.
+func SetHeaderVerifierDB(db dbm.DB) func(*PlasmaMVPChain) {
+	return func(pc *PlasmaMVPChain) {
+		pc.verifierDB = db
+	}
+}
+
+// SetConfirmSigMailbox enables the confirm signature mailbox backed by the
+// database. The mailbox is disabled if never set.
+func SetConfirmSigMailbox(db dbm.DB) func(*PlasmaMVPChain) {
+	return func(pc *PlasmaMVPChain) {
+		pc.mailboxDB = db
 	}
 }
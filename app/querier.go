@@ -0,0 +1,63 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/eth"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+const (
+	// OperatorQuerierRouteName to mount the operator querier
+	OperatorQuerierRouteName = "operator"
+
+	// QuerySubmission retrieves the status of the latest
+	// block submission to the rootchain
+	QuerySubmission = "submission"
+
+	// QueryVerification retrieves the status of the verification
+	// of the headers committed to the rootchain
+	QueryVerification = "verification"
+
+	// QueryReorgs retrieves the reorgs of the rootchain detected
+	// by this node and whether deposit inclusion is halted
+	QueryReorgs = "reorgs"
+)
+
+// newOperatorQuerier returns a querier of the state local to this node's
+// connection with the rootchain. Responses are not part of consensus.
+// `verifier` is nil if this node does not verify committed headers
+func newOperatorQuerier(plasma *eth.Plasma, verifier *eth.HeaderVerifier) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("path not specified")
+		}
+
+		switch path[0] {
+		case QuerySubmission:
+			data, err := json.Marshal(plasma.SubmissionStatus())
+			if err != nil {
+				return nil, sdk.ErrInternal(fmt.Sprintf("json: %s", err))
+			}
+			return data, nil
+		case QueryReorgs:
+			data, err := json.Marshal(plasma.ReorgStatus())
+			if err != nil {
+				return nil, sdk.ErrInternal(fmt.Sprintf("json: %s", err))
+			}
+			return data, nil
+		case QueryVerification:
+			if verifier == nil {
+				return nil, sdk.ErrUnknownRequest("header verification is disabled on this node")
+			}
+			data, err := json.Marshal(verifier.Status())
+			if err != nil {
+				return nil, sdk.ErrInternal(fmt.Sprintf("json: %s", err))
+			}
+			return data, nil
+		default:
+			return nil, sdk.ErrUnknownRequest("unregistered query path")
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	cosmosStore "github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	"sync"
+)
+
+// committedState is a read-only view of the latest committed state used by the
+// background services of the app. The check state of the BaseApp is only safe to
+// use from the abci connection, so the view is loaded separately from the
+// database. It is reloaded once a new block has been committed and never written to.
+type committedState struct {
+	cms    sdk.CommitMultiStore
+	height func() int64 // latest committed height
+	logger log.Logger
+
+	mtx    sync.Mutex
+	loaded bool
+}
+
+func newCommittedState(db dbm.DB, dataStoreKey *sdk.KVStoreKey, height func() int64, logger log.Logger) *committedState {
+	cms := cosmosStore.NewCommitMultiStore(db)
+	cms.MountStoreWithDB(dataStoreKey, sdk.StoreTypeIAVL, nil)
+
+	return &committedState{
+		cms:    cms,
+		height: height,
+		logger: logger,
+	}
+}
+
+// context returns a context over the latest committed state. Writes through the
+// context are discarded
+func (s *committedState) context() (sdk.Context, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.loaded || s.cms.LastCommitID().Version != s.height() {
+		if err := s.cms.LoadLatestVersion(); err != nil {
+			return sdk.Context{}, fmt.Errorf("loading the committed state: %s", err)
+		}
+		s.loaded = true
+	}
+
+	return sdk.NewContext(s.cms.CacheMultiStore(), abci.Header{}, true, s.logger), nil
+}
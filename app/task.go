@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"github.com/tendermint/tendermint/libs/log"
+	"sync"
+	"time"
+)
+
+// backgroundTask runs a task of a background service of the app every interval
+// in a separate goroutine until it is stopped
+type backgroundTask struct {
+	errMsg string
+	logger log.Logger
+
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newBackgroundTask returns a task whose errors are logged as `errMsg`
+func newBackgroundTask(errMsg string, logger log.Logger) *backgroundTask {
+	return &backgroundTask{
+		errMsg: errMsg,
+		logger: logger,
+		quit:   make(chan struct{}),
+	}
+}
+
+// start runs `task` every `interval` in a separate goroutine. A task that
+// returns an error is retried on the next tick
+func (t *backgroundTask) start(interval time.Duration, task func() error) {
+	t.done = make(chan struct{})
+	go func() {
+		defer close(t.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.quit:
+				return
+			case <-ticker.C:
+				if err := task(); err != nil {
+					t.logger.Error(fmt.Sprintf("%s: %s", t.errMsg, err))
+				}
+			}
+		}
+	}()
+}
+
+// stopping reports if the task has been asked to stop. Long running tasks
+// check it between steps
+func (t *backgroundTask) stopping() bool {
+	select {
+	case <-t.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop halts the task. A run in progress is completed before returning
+func (t *backgroundTask) stop() {
+	t.stopOnce.Do(func() {
+		close(t.quit)
+	})
+	if t.done != nil {
+		<-t.done
+	}
+}
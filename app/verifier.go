@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/eth"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/tendermint/tendermint/libs/log"
+	"time"
+)
+
+// interval at which the verifier checks for newly committed plasma headers
+const headerVerifyInterval = 15 * time.Second
+
+// headerVerifier checks the plasma headers committed to the rootchain by the
+// operator against the blocks agreed upon by the validators. Blocks are read
+// from the read-only view of the committed state.
+type headerVerifier struct {
+	verifier *eth.HeaderVerifier
+	ds       store.DataStore
+	state    *committedState
+	logger   log.Logger
+
+	task *backgroundTask
+}
+
+func newHeaderVerifier(state *committedState, ds store.DataStore, verifier *eth.HeaderVerifier, logger log.Logger) *headerVerifier {
+	return &headerVerifier{
+		verifier: verifier,
+		ds:       ds,
+		state:    state,
+		logger:   logger,
+		task:     newBackgroundTask("error verifying plasma headers", logger),
+	}
+}
+
+// start verifies headers every `interval` in a separate goroutine
+func (v *headerVerifier) start(interval time.Duration) {
+	v.task.start(interval, v.verify)
+}
+
+// stop halts the verifier. A verification in progress is completed before returning
+func (v *headerVerifier) stop() {
+	v.task.stop()
+}
+
+// verify compares the newly committed headers against the latest committed
+// state and raises an alert for every mismatch
+func (v *headerVerifier) verify() error {
+	ctx, err := v.state.context()
+	if err != nil {
+		return err
+	}
+
+	mismatches, err := v.verifier.Verify(ctx, v.ds)
+	for _, m := range mismatches {
+		v.logger.Error(fmt.Sprintf("ALERT: plasma block %s committed to the rootchain does not match the local block. "+
+			"Committed: header 0x%x, %s txs, %s fees. Local: header 0x%x, %s txs, %s fees",
+			m.BlockNumber, m.Committed.Header, m.Committed.NumTxns, m.Committed.FeeAmount,
+			m.Local.Header, m.Local.NumTxns, m.Local.FeeAmount))
+	}
+
+	return err
+}
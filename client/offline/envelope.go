@@ -0,0 +1,318 @@
+// Package offline implements the envelope used to construct and sign plasma
+// transactions across multiple parties before they are broadcast.
+//
+// An envelope is a JSON document. The `transaction` field holds the RLP
+// encoding of the `plasma.Transaction` and is the source of truth. Every other
+// field is derived from it, except for the owner and amount of each input which
+// are recorded when the envelope is built. Envelopes whose derived fields do
+// not match the encoded transaction are rejected.
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+)
+
+// Version of the envelope format
+const Version = 1
+
+// Envelope wraps a transaction that is being signed
+type Envelope struct {
+	Transaction plasma.Transaction
+	Inputs      []InputInfo
+}
+
+// InputInfo is what is known about an input apart from the transaction. A
+// zero owner or nil amount is unknown
+type InputInfo struct {
+	Owner  common.Address
+	Amount *big.Int
+}
+
+// envelopeJSON is the serialized form of the envelope
+type envelopeJSON struct {
+	Version     uint          `json:"version"`
+	Transaction hexutil.Bytes `json:"transaction"`
+	TxHash      hexutil.Bytes `json:"tx_hash"`
+	Inputs      []inputJSON   `json:"inputs"`
+	Outputs     []outputJSON  `json:"outputs"`
+	Fee         string        `json:"fee"`
+}
+
+type inputJSON struct {
+	Position          string          `json:"position"`
+	Owner             *common.Address `json:"owner,omitempty"`
+	Amount            string          `json:"amount,omitempty"`
+	Signature         hexutil.Bytes   `json:"signature,omitempty"`
+	ConfirmSignatures []hexutil.Bytes `json:"confirm_signatures"`
+}
+
+type outputJSON struct {
+	Owner  common.Address `json:"owner"`
+	Amount string         `json:"amount"`
+}
+
+// NewEnvelope wraps the transaction. The input information is given in the
+// order of the transaction inputs and may be nil if unknown
+func NewEnvelope(tx plasma.Transaction, inputs []InputInfo) (*Envelope, error) {
+	if len(tx.Inputs) == 0 || len(tx.Outputs) == 0 {
+		return nil, fmt.Errorf("transaction must have at least 1 input and output")
+	}
+	if inputs == nil {
+		inputs = make([]InputInfo, len(tx.Inputs))
+	}
+	if len(inputs) != len(tx.Inputs) {
+		return nil, fmt.Errorf("input information does not match the number of inputs")
+	}
+	if tx.Fee == nil {
+		tx.Fee = big.NewInt(0)
+	}
+
+	return &Envelope{
+		Transaction: tx,
+		Inputs:      inputs,
+	}, nil
+}
+
+// Decode parses an envelope and verifies its fields against the encoded transaction
+func Decode(bz []byte) (*Envelope, error) {
+	var e Envelope
+	if err := json.Unmarshal(bz, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Encode serializes the envelope into indented JSON
+func (e *Envelope) Encode() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// TxHash returns the hash the inputs sign over
+func (e *Envelope) TxHash() []byte {
+	return e.Transaction.TxHash()
+}
+
+// IsSigned returns true if the input at the index has a signature
+func (e *Envelope) IsSigned(index int) bool {
+	return e.Transaction.Inputs[index].Signature != [65]byte{}
+}
+
+// Signer returns the address that signed the input at the index
+func (e *Envelope) Signer(index int) (common.Address, error) {
+	if !e.IsSigned(index) {
+		return common.Address{}, fmt.Errorf("input %d is not signed", index)
+	}
+
+	sig := e.Transaction.Inputs[index].Signature
+	pubKey, err := crypto.SigToPub(utils.ToEthSignedMessageHash(e.TxHash()), sig[:])
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature for input %d: %s", index, err)
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// AddSignature sets the signature of the input at the index. The signature
+// must be over the transaction hash by the owner of the input if it is known
+func (e *Envelope) AddSignature(index int, sig []byte) error {
+	if index < 0 || index >= len(e.Transaction.Inputs) {
+		return fmt.Errorf("input %d does not exist", index)
+	}
+	if len(sig) != 65 {
+		return fmt.Errorf("signatures must be 65 bytes in length")
+	}
+
+	pubKey, err := crypto.SigToPub(utils.ToEthSignedMessageHash(e.TxHash()), sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	signer := crypto.PubkeyToAddress(*pubKey)
+	if owner := e.Inputs[index].Owner; !utils.IsZeroAddress(owner) && owner != signer {
+		return fmt.Errorf("input %d is owned by 0x%x, not the signer 0x%x", index, owner, signer)
+	}
+
+	copy(e.Transaction.Inputs[index].Signature[:], sig)
+	return nil
+}
+
+// AddConfirmSignatures sets the confirm signatures of the input at the index.
+// Confirm signatures are part of the signed transaction hash so they can only
+// be set before any input is signed
+func (e *Envelope) AddConfirmSignatures(index int, sigs [][65]byte) error {
+	if index < 0 || index >= len(e.Transaction.Inputs) {
+		return fmt.Errorf("input %d does not exist", index)
+	}
+	for i := range e.Transaction.Inputs {
+		if e.IsSigned(i) {
+			return fmt.Errorf("confirm signatures must be added before any input is signed")
+		}
+	}
+
+	input := e.Transaction.Inputs[index]
+	if input.IsDeposit() || input.IsFee() {
+		return fmt.Errorf("deposit and fee inputs do not have confirm signatures")
+	}
+	if len(sigs) == 0 || len(sigs) > plasma.MaxTxInputs {
+		return fmt.Errorf("between 1 and %d confirm signatures must be given", plasma.MaxTxInputs)
+	}
+
+	e.Transaction.Inputs[index].ConfirmSignatures = sigs
+	return nil
+}
+
+// Missing describes what is missing before the transaction can be broadcast
+func (e *Envelope) Missing() []string {
+	var missing []string
+	for i, input := range e.Transaction.Inputs {
+		if !input.IsDeposit() && !input.IsFee() && len(input.ConfirmSignatures) == 0 {
+			missing = append(missing, fmt.Sprintf("confirm signatures for input %d", i))
+		}
+		if !e.IsSigned(i) {
+			missing = append(missing, fmt.Sprintf("signature for input %d", i))
+		}
+	}
+	return missing
+}
+
+// SpendMsg returns the message to broadcast once the transaction is complete
+func (e *Envelope) SpendMsg() (msgs.SpendMsg, error) {
+	if missing := e.Missing(); len(missing) > 0 {
+		return msgs.SpendMsg{}, fmt.Errorf("transaction is missing %s", missing[0])
+	}
+
+	for i, info := range e.Inputs {
+		signer, err := e.Signer(i)
+		if err != nil {
+			return msgs.SpendMsg{}, err
+		}
+		if !utils.IsZeroAddress(info.Owner) && signer != info.Owner {
+			return msgs.SpendMsg{}, fmt.Errorf("input %d is owned by 0x%x, not the signer 0x%x", i, info.Owner, signer)
+		}
+	}
+
+	msg := msgs.SpendMsg{Transaction: e.Transaction}
+	if err := msg.ValidateBasic(); err != nil {
+		return msgs.SpendMsg{}, err
+	}
+	return msg, nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (e *Envelope) MarshalJSON() ([]byte, error) {
+	raw, err := e.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The derived fields must match
+// the encoded transaction
+func (e *Envelope) UnmarshalJSON(bz []byte) error {
+	var raw envelopeJSON
+	if err := json.Unmarshal(bz, &raw); err != nil {
+		return err
+	}
+	if raw.Version != Version {
+		return fmt.Errorf("unsupported envelope version %d", raw.Version)
+	}
+
+	var tx plasma.Transaction
+	if err := rlp.DecodeBytes(raw.Transaction, &tx); err != nil {
+		return fmt.Errorf("invalid transaction encoding: %s", err)
+	}
+	if len(raw.Inputs) != len(tx.Inputs) {
+		return fmt.Errorf("envelope inputs do not match the transaction")
+	}
+
+	inputs := make([]InputInfo, len(raw.Inputs))
+	for i, input := range raw.Inputs {
+		if input.ConfirmSignatures == nil {
+			raw.Inputs[i].ConfirmSignatures = []hexutil.Bytes{}
+		}
+		if input.Owner != nil {
+			inputs[i].Owner = *input.Owner
+		}
+		if input.Amount != "" {
+			amount, ok := new(big.Int).SetString(input.Amount, 10)
+			if !ok || amount.Sign() < 0 {
+				return fmt.Errorf("invalid amount for input %d", i)
+			}
+			inputs[i].Amount = amount
+		}
+	}
+
+	decoded, err := NewEnvelope(tx, inputs)
+	if err != nil {
+		return err
+	}
+
+	// every field is derived from the transaction and input information
+	expected, err := decoded.toJSON()
+	if err != nil {
+		return err
+	}
+	expectedBz, _ := json.Marshal(expected)
+	actualBz, _ := json.Marshal(raw)
+	if !bytes.Equal(expectedBz, actualBz) {
+		return fmt.Errorf("envelope fields do not match the encoded transaction")
+	}
+
+	*e = *decoded
+	return nil
+}
+
+func (e *Envelope) toJSON() (envelopeJSON, error) {
+	txBytes, err := rlp.EncodeToBytes(&e.Transaction)
+	if err != nil {
+		return envelopeJSON{}, err
+	}
+
+	raw := envelopeJSON{
+		Version:     Version,
+		Transaction: txBytes,
+		TxHash:      e.TxHash(),
+		Fee:         e.Transaction.Fee.String(),
+	}
+
+	for i, input := range e.Transaction.Inputs {
+		in := inputJSON{
+			Position:          input.Position.String(),
+			ConfirmSignatures: []hexutil.Bytes{},
+		}
+		if info := e.Inputs[i]; !utils.IsZeroAddress(info.Owner) {
+			owner := info.Owner
+			in.Owner = &owner
+		}
+		if amount := e.Inputs[i].Amount; amount != nil {
+			in.Amount = amount.String()
+		}
+		if e.IsSigned(i) {
+			sig := input.Signature
+			in.Signature = sig[:]
+		}
+		for j := range input.ConfirmSignatures {
+			sig := input.ConfirmSignatures[j]
+			in.ConfirmSignatures = append(in.ConfirmSignatures, sig[:])
+		}
+		raw.Inputs = append(raw.Inputs, in)
+	}
+
+	for _, output := range e.Transaction.Outputs {
+		raw.Outputs = append(raw.Outputs, outputJSON{
+			Owner:  output.Owner,
+			Amount: output.Amount.String(),
+		})
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,147 @@
+package offline
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func sign(t *testing.T, hash []byte, key *ecdsa.PrivateKey) []byte {
+	sig, err := crypto.Sign(utils.ToEthSignedMessageHash(hash), key)
+	require.NoError(t, err)
+	return sig
+}
+
+// two party transaction spending a deposit and a transaction output
+func setup(t *testing.T) (*Envelope, *ecdsa.PrivateKey, *ecdsa.PrivateKey) {
+	key0, _ := crypto.GenerateKey()
+	key1, _ := crypto.GenerateKey()
+
+	tx := plasma.Transaction{
+		Inputs: []plasma.Input{
+			plasma.NewInput(plasma.NewPosition(nil, 0, 0, big.NewInt(3)), [65]byte{}, nil),
+			plasma.NewInput(plasma.NewPosition(big.NewInt(2), 1, 0, nil), [65]byte{}, nil),
+		},
+		Outputs: []plasma.Output{
+			plasma.NewOutput(common.HexToAddress("0x5cae340fb2c2bb0a2f194a95cda8a1ffdc9d2f85"), big.NewInt(150)),
+		},
+		Fee: big.NewInt(50),
+	}
+	info := []InputInfo{
+		{crypto.PubkeyToAddress(key0.PublicKey), big.NewInt(100)},
+		{crypto.PubkeyToAddress(key1.PublicKey), big.NewInt(100)},
+	}
+
+	e, err := NewEnvelope(tx, info)
+	require.NoError(t, err)
+	return e, key0, key1
+}
+
+// encode and decode the envelope
+func roundTrip(t *testing.T, e *Envelope) *Envelope {
+	bz, err := e.Encode()
+	require.NoError(t, err)
+	decoded, err := Decode(bz)
+	require.NoError(t, err, "failed to decode %s", bz)
+	require.Equal(t, e.TxHash(), decoded.TxHash(), "tx hash changed on round trip")
+	require.Equal(t, e.Inputs, decoded.Inputs, "input information changed on round trip")
+	return decoded
+}
+
+func TestEnvelopeWorkflow(t *testing.T) {
+	e, key0, key1 := setup(t)
+	e = roundTrip(t, e)
+	require.Len(t, e.Missing(), 3, "deposit input needs a signature, transaction input needs confirm signatures and a signature")
+
+	_, err := e.SpendMsg()
+	require.Error(t, err, "incomplete transaction converted to a spend")
+
+	// confirm signatures
+	confirmSig := sign(t, crypto.Keccak256([]byte("confirm")), key1)
+	var sig [65]byte
+	copy(sig[:], confirmSig)
+	require.Error(t, e.AddConfirmSignatures(0, [][65]byte{sig}), "added confirm signatures to a deposit")
+	require.Error(t, e.AddConfirmSignatures(2, [][65]byte{sig}), "added confirm signatures to a missing input")
+	require.Error(t, e.AddConfirmSignatures(1, nil), "added no confirm signatures")
+	require.NoError(t, e.AddConfirmSignatures(1, [][65]byte{sig}))
+	e = roundTrip(t, e)
+
+	// each party signs their input
+	hash := e.TxHash()
+	require.Error(t, e.AddSignature(0, sign(t, hash, key1)), "input signed by a party that does not own it")
+	require.Error(t, e.AddSignature(0, hash), "added a signature of the wrong length")
+	require.NoError(t, e.AddSignature(0, sign(t, hash, key0)))
+	e = roundTrip(t, e)
+
+	require.Error(t, e.AddConfirmSignatures(1, [][65]byte{sig}), "confirm signatures changed after an input was signed")
+	require.Equal(t, []string{"signature for input 1"}, e.Missing())
+
+	require.NoError(t, e.AddSignature(1, sign(t, hash, key1)))
+	e = roundTrip(t, e)
+	require.Empty(t, e.Missing())
+
+	signer, err := e.Signer(1)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key1.PublicKey), signer)
+
+	msg, err := e.SpendMsg()
+	require.NoError(t, err, "complete transaction not converted to a spend")
+	signers := msg.GetSigners()
+	require.Len(t, signers, 2)
+	require.Equal(t, crypto.PubkeyToAddress(key0.PublicKey).Bytes(), []byte(signers[0]))
+	require.Equal(t, crypto.PubkeyToAddress(key1.PublicKey).Bytes(), []byte(signers[1]))
+}
+
+func TestEnvelopeUnknownOwners(t *testing.T) {
+	e, key0, _ := setup(t)
+	e.Inputs = make([]InputInfo, len(e.Inputs))
+	e = roundTrip(t, e)
+
+	// any signer is accepted for an input with an unknown owner
+	_, key1, _ := setup(t)
+	require.NoError(t, e.AddSignature(0, sign(t, e.TxHash(), key1)))
+	require.NoError(t, e.AddSignature(0, sign(t, e.TxHash(), key0)))
+
+	_, err := NewEnvelope(e.Transaction, e.Inputs[:1])
+	require.Error(t, err, "input information does not match the inputs")
+}
+
+func TestEnvelopeDecodeErrors(t *testing.T) {
+	e, _, _ := setup(t)
+	bz, err := e.Encode()
+	require.NoError(t, err)
+
+	cases := []func(map[string]interface{}){
+		func(m map[string]interface{}) { m["version"] = 2 },
+		func(m map[string]interface{}) { m["fee"] = "10" },
+		func(m map[string]interface{}) { m["tx_hash"] = "0x01" },
+		func(m map[string]interface{}) { m["transaction"] = "0x01" },
+		func(m map[string]interface{}) {
+			m["outputs"].([]interface{})[0].(map[string]interface{})["amount"] = "1000"
+		},
+		func(m map[string]interface{}) {
+			m["inputs"].([]interface{})[1].(map[string]interface{})["position"] = "(3.0.0.0)"
+		},
+		func(m map[string]interface{}) {
+			m["inputs"].([]interface{})[1].(map[string]interface{})["amount"] = "-1"
+		},
+		func(m map[string]interface{}) { m["inputs"] = m["inputs"].([]interface{})[:1] },
+	}
+
+	for i, modify := range cases {
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(bz, &m))
+		modify(m)
+		modified, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		_, err = Decode(modified)
+		require.Error(t, err, "case %d: decoded a modified envelope", i)
+	}
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/handlers"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/FourthState/plasma-mvp-sidechain/store"
 	"github.com/cosmos/cosmos-sdk/client/context"
@@ -44,6 +45,23 @@ func TxInput(ctx context.CLIContext, pos plasma.Position) (store.TxInput, error)
 	return input, nil
 }
 
+// TxProof retrieves the transaction at `pos` along with the proof of its inclusion in the plasma block
+func TxProof(ctx context.CLIContext, pos plasma.Position) (store.TxProof, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
+		store.QuerierRouteName, store.QueryTxProof, pos)
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return store.TxProof{}, err
+	}
+
+	var proof store.TxProof
+	if err := json.Unmarshal(data, &proof); err != nil {
+		return store.TxProof{}, fmt.Errorf("json: %s", err)
+	}
+
+	return proof, nil
+}
+
 // Tx locates a transaction and given it's hash
 // @param hash 32-byte hexadecimal string
 func Tx(ctx context.CLIContext, hash []byte) (store.Transaction, error) {
@@ -62,6 +80,99 @@ func Tx(ctx context.CLIContext, hash []byte) (store.Transaction, error) {
 	return tx, nil
 }
 
+// TxPosition retrieves the position of the transaction with the given hash
+func TxPosition(ctx context.CLIContext, hash []byte) (plasma.Position, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s/%x",
+		store.QuerierRouteName, store.QueryTxPosition, hash)
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return plasma.Position{}, err
+	}
+
+	var pos plasma.Position
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return plasma.Position{}, fmt.Errorf("json: %s", err)
+	}
+
+	return pos, nil
+}
+
+// Spender retrieves the transaction that spent the deposit, fee or output located at `pos`
+func Spender(ctx context.CLIContext, pos plasma.Position) (store.Transaction, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
+		store.QuerierRouteName, store.QuerySpender, pos)
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return store.Transaction{}, err
+	}
+
+	var tx store.Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return store.Transaction{}, fmt.Errorf("json: %s", err)
+	}
+
+	return tx, nil
+}
+
+// ConfirmSigs retrieves the confirm signatures posted to the mailbox of the node
+// for the transaction that created the output located at `pos`
+func ConfirmSigs(ctx context.CLIContext, pos plasma.Position) (store.ConfirmSigs, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
+		store.MailboxQuerierRouteName, store.QueryConfirmSigs, pos)
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return store.ConfirmSigs{}, err
+	}
+
+	var sigs store.ConfirmSigs
+	if err := json.Unmarshal(data, &sigs); err != nil {
+		return store.ConfirmSigs{}, fmt.Errorf("json: %s", err)
+	}
+
+	return sigs, nil
+}
+
+// PostConfirmSigs posts confirm signatures to the mailbox of the node for the
+// transaction that created the output located at `pos`. Signatures are ordered
+// by the inputs of the transaction and zero signatures are skipped
+func PostConfirmSigs(ctx context.CLIContext, pos plasma.Position, sigs [][65]byte) (store.ConfirmSigs, error) {
+	var data []byte
+	for _, sig := range sigs {
+		data = append(data, sig[:]...)
+	}
+
+	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
+		store.MailboxQuerierRouteName, store.QueryPostConfirmSigs, pos)
+	data, err := ctx.QueryWithData(queryRoute, data)
+	if err != nil {
+		return store.ConfirmSigs{}, err
+	}
+
+	var posted store.ConfirmSigs
+	if err := json.Unmarshal(data, &posted); err != nil {
+		return store.ConfirmSigs{}, fmt.Errorf("json: %s", err)
+	}
+
+	return posted, nil
+}
+
+// FeePolicy retrieves the minimum fee the node requires of spends entering its mempool
+func FeePolicy(ctx context.CLIContext) (handlers.FeePolicy, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s",
+		handlers.FeeQuerierRouteName, handlers.QueryFeePolicy)
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return handlers.FeePolicy{}, err
+	}
+
+	var policy handlers.FeePolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return handlers.FeePolicy{}, fmt.Errorf("json: %s", err)
+	}
+
+	return policy, nil
+}
+
 // Info retrieves the unspent utxo set of an owned address
 func Info(ctx context.CLIContext, addr ethcmn.Address) ([]store.TxOutput, error) {
 	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
@@ -79,6 +190,82 @@ func Info(ctx context.CLIContext, addr ethcmn.Address) ([]store.TxOutput, error)
 	return utxos, nil
 }
 
+// FeeOutputs retrieves the unspent fee outputs collected by an address along
+// with the output of its latest sweep
+func FeeOutputs(ctx context.CLIContext, addr ethcmn.Address) (store.FeeOutputs, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
+		store.QuerierRouteName, store.QueryFees, addr.Hex())
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return store.FeeOutputs{}, err
+	}
+
+	var fees store.FeeOutputs
+	if err := json.Unmarshal(data, &fees); err != nil {
+		return store.FeeOutputs{}, fmt.Errorf("json: %s", err)
+	}
+
+	return fees, nil
+}
+
+// FeeAddress retrieves the address fees are collected to along with the nonce
+// of the next fee address update
+func FeeAddress(ctx context.CLIContext) (store.FeeAddress, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s",
+		store.QuerierRouteName, store.QueryFeeAddress)
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return store.FeeAddress{}, err
+	}
+
+	var feeAddress store.FeeAddress
+	if err := json.Unmarshal(data, &feeAddress); err != nil {
+		return store.FeeAddress{}, fmt.Errorf("json: %s", err)
+	}
+
+	return feeAddress, nil
+}
+
+// Validators retrieves the validator set along with the nonce of the next
+// validator update
+func Validators(ctx context.CLIContext) (store.ValidatorSet, error) {
+	queryRoute := fmt.Sprintf("custom/%s/%s",
+		store.QuerierRouteName, store.QueryValidators)
+	data, err := ctx.Query(queryRoute, nil)
+	if err != nil {
+		return store.ValidatorSet{}, err
+	}
+
+	var set store.ValidatorSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return store.ValidatorSet{}, fmt.Errorf("json: %s", err)
+	}
+
+	return set, nil
+}
+
+// History retrieves a page of the outputs received and spent by an address filtered by `params`
+func History(ctx context.CLIContext, addr ethcmn.Address, params store.HistoryParams) (store.WalletHistory, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return store.WalletHistory{}, fmt.Errorf("json: %s", err)
+	}
+
+	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
+		store.QuerierRouteName, store.QueryHistory, addr.Hex())
+	data, err = ctx.QueryWithData(queryRoute, data)
+	if err != nil {
+		return store.WalletHistory{}, err
+	}
+
+	var history store.WalletHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return store.WalletHistory{}, fmt.Errorf("json: %s", err)
+	}
+
+	return history, nil
+}
+
 // Balance retrieves the aggregate value across unspent utxos of an address
 func Balance(ctx context.CLIContext, addr ethcmn.Address) (string, error) {
 	queryRoute := fmt.Sprintf("custom/%s/%s/%s",
@@ -126,6 +313,23 @@ func Block(ctx context.CLIContext, height *big.Int) (store.Block, error) {
 	return block, nil
 }
 
+// BlockAtTMHeight retrieves the plasma block committed at tendermint height `tmHeight`
+func BlockAtTMHeight(ctx context.CLIContext, tmHeight uint64) (store.Block, error) {
+	queryPath := fmt.Sprintf("custom/%s/%s/%d",
+		store.QuerierRouteName, store.QueryTMBlock, tmHeight)
+	data, err := ctx.Query(queryPath, nil)
+	if err != nil {
+		return store.Block{}, err
+	}
+
+	var block store.Block
+	if err := json.Unmarshal(data, &block); err != nil {
+		return block, fmt.Errorf("json: %s", err)
+	}
+
+	return block, nil
+}
+
 // Blocks retrieves 10 blocks from `startingHeight`. if `startingHeight == nil`, the latest 10 are retrieved
 func Blocks(ctx context.CLIContext, startingHeight *big.Int) ([]store.Block, error) {
 	if startingHeight != nil && startingHeight.Sign() <= 0 {
@@ -15,6 +15,8 @@ import (
 	"github.com/gorilla/mux"
 	"math/big"
 	"net/http"
+	"net/url"
+	"strconv"
 )
 
 func RegisterRoutes(ctx context.CLIContext, r *mux.Router) {
@@ -22,15 +24,29 @@ func RegisterRoutes(ctx context.CLIContext, r *mux.Router) {
 	r.HandleFunc("/height", heightHandler(ctx)).Methods("GET")
 	r.HandleFunc("/block/{height}", blockHandler(ctx)).Methods("GET")
 	r.HandleFunc("/blocks/{height}", blocksHandler(ctx)).Methods("GET")
+	r.HandleFunc("/tmblock/{height}", tmBlockHandler(ctx)).Methods("GET")
+	r.HandleFunc("/fees", feePolicyHandler(ctx)).Methods("GET")
+	r.HandleFunc("/feeaddress", feeAddressHandler(ctx)).Methods("GET")
+	r.HandleFunc("/validators", validatorsHandler(ctx)).Methods("GET")
 
 	r.HandleFunc("/info/{address}", infoHandler(ctx)).Methods("GET")
 	r.HandleFunc("/balance/{address}", balanceHandler(ctx)).Methods("GET")
+	r.HandleFunc("/history/{address}", historyHandler(ctx)).Methods("GET")
+	r.HandleFunc("/fees/{address}", feeOutputsHandler(ctx)).Methods("GET")
 
 	r.HandleFunc("/tx/{hash}", txHandler(ctx)).Methods("GET")
+	r.HandleFunc("/position/{hash}", txPositionHandler(ctx)).Methods("GET")
+	r.HandleFunc("/spender/{position}", spenderHandler(ctx)).Methods("GET")
 	r.HandleFunc("/output/{position}", outputHandler(ctx)).Methods("GET")
+	r.HandleFunc("/proof/{position}", proofHandler(ctx)).Methods("GET")
+	r.HandleFunc("/confirmsigs/{position}", confirmSigsHandler(ctx)).Methods("GET")
 
 	// Post
 	r.HandleFunc("/submit", submitHandler(ctx)).Methods("POST")
+	r.HandleFunc("/confirmsigs/{position}", postConfirmSigsHandler(ctx)).Methods("POST")
+
+	// Streaming
+	r.HandleFunc("/subscribe", streamHandler(ctx, newStreamHub(ctx))).Methods("GET")
 }
 
 func heightHandler(ctx context.CLIContext) http.HandlerFunc {
@@ -114,6 +130,61 @@ func blocksHandler(ctx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+func tmBlockHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tmHeight, err := strconv.ParseUint(mux.Vars(r)["height"], 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("tendermint block height must be in decimal format"))
+			return
+		}
+
+		block, err := BlockAtTMHeight(ctx, tmHeight)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, block)
+	}
+}
+
+func feePolicyHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policy, err := FeePolicy(ctx)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, policy)
+	}
+}
+
+func feeAddressHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		feeAddress, err := FeeAddress(ctx)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, feeAddress)
+	}
+}
+
+func validatorsHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		set, err := Validators(ctx)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, set)
+	}
+}
+
 func infoHandler(ctx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -155,6 +226,51 @@ func balanceHandler(ctx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+func feeOutputsHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr := mux.Vars(r)["address"]
+		if !ethcmn.IsHexAddress(addr) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("address must be an ethereum 20-byte hex string"))
+			return
+		}
+
+		fees, err := FeeOutputs(ctx, ethcmn.HexToAddress(addr))
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, fees)
+	}
+}
+
+func historyHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr := mux.Vars(r)["address"]
+		if !ethcmn.IsHexAddress(addr) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("address must be an ethereum 20-byte hex string"))
+			return
+		}
+
+		params, err := historyParams(r.URL.Query())
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		history, err := History(ctx, ethcmn.HexToAddress(addr), params)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, history)
+	}
+}
+
 func txHandler(ctx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		txHash := utils.RemoveHexPrefix(mux.Vars(r)["hash"])
@@ -181,6 +297,44 @@ func txHandler(ctx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+func txPositionHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hash, err := hex.DecodeString(utils.RemoveHexPrefix(mux.Vars(r)["hash"]))
+		if err != nil || len(hash) != 32 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("tx hash expected to be 32 bytes in hexadecimal format"))
+			return
+		}
+
+		pos, err := TxPosition(ctx, hash)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, pos)
+	}
+}
+
+func spenderHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pos, err := plasma.FromPositionString(mux.Vars(r)["position"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		tx, err := Spender(ctx, pos)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, tx)
+	}
+}
+
 func outputHandler(ctx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		pos, err := plasma.FromPositionString(mux.Vars(r)["position"])
@@ -199,6 +353,89 @@ func outputHandler(ctx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+func proofHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pos, err := plasma.FromPositionString(mux.Vars(r)["position"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		proof, err := TxProof(ctx, pos)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, proof)
+	}
+}
+
+func confirmSigsHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pos, err := plasma.FromPositionString(mux.Vars(r)["position"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		sigs, err := ConfirmSigs(ctx, pos)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, sigs)
+	}
+}
+
+func postConfirmSigsHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pos, err := plasma.FromPositionString(mux.Vars(r)["position"])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		type reqBody struct {
+			Signatures []string `json:"signatures"`
+		}
+
+		var body reqBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("unable to read request body: " + err.Error()))
+			return
+		}
+
+		// an empty string skips the signature of that input
+		sigs := make([][65]byte, len(body.Signatures))
+		for i, sigStr := range body.Signatures {
+			if sigStr == "" {
+				continue
+			}
+			sig, err := hex.DecodeString(utils.RemoveHexPrefix(sigStr))
+			if err != nil || len(sig) != 65 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("signature %d must be 65 bytes in hexadecimal format", i)))
+				return
+			}
+			copy(sigs[i][:], sig)
+		}
+
+		posted, err := PostConfirmSigs(ctx, pos, sigs)
+		if err != nil {
+			writeClientRetrievalErr(w, err)
+			return
+		}
+
+		writeJSONResponse(w, posted)
+	}
+}
+
 func submitHandler(ctx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		type reqBody struct {
@@ -271,6 +508,56 @@ func writeJSONResponse(w http.ResponseWriter, obj interface{}) {
 	w.Write(data)
 }
 
+// historyParams parses the `cursor`, `limit`, `direction`, `spent`, `from`
+// and `to` query parameters of a history request
+func historyParams(query url.Values) (store.HistoryParams, error) {
+	params := store.HistoryParams{
+		Cursor:    query.Get("cursor"),
+		Direction: query.Get("direction"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l <= 0 {
+			return params, fmt.Errorf("limit must be a positive number")
+		}
+		params.Limit = l
+	}
+
+	if spent := query.Get("spent"); spent != "" {
+		s, err := strconv.ParseBool(spent)
+		if err != nil {
+			return params, fmt.Errorf("spent must be true or false")
+		}
+		params.Spent = &s
+	}
+
+	var err error
+	if params.FromBlock, err = blockNumParam(query, "from"); err != nil {
+		return params, err
+	}
+	if params.ToBlock, err = blockNumParam(query, "to"); err != nil {
+		return params, err
+	}
+
+	return params, params.ValidateBasic()
+}
+
+// blockNumParam parses the optional block number query parameter `name`
+func blockNumParam(query url.Values, name string) (*big.Int, error) {
+	arg := query.Get(name)
+	if arg == "" {
+		return nil, nil
+	}
+
+	num, ok := new(big.Int).SetString(arg, 10)
+	if !ok || num.Sign() < 0 {
+		return nil, fmt.Errorf("%s must be a block number in decimal format", name)
+	}
+
+	return num, nil
+}
+
 func writeClientRetrievalErr(w http.ResponseWriter, err error) {
 	// If the client request (GET) could not be fulfilled for some
 	// other reason than the requested information not existing (DNE), the request
@@ -0,0 +1,469 @@
+package client
+
+import (
+	gocontext "context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/websocket"
+	abci "github.com/tendermint/tendermint/abci/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Subscription topics
+const (
+	TopicBlocks        = "blocks"        // every new plasma block
+	TopicDeposits      = "deposits"      // every deposit included into the sidechain
+	TopicAddress       = "address"       // outputs created or spent for an address
+	TopicConfirmations = "confirmations" // the confirmation hash of a transaction once it is committed
+)
+
+// Event types
+const (
+	EventBlock         = "block"
+	EventDeposit       = "deposit"
+	EventOutputCreated = "output_created"
+	EventOutputSpent   = "output_spent"
+	EventConfirmation  = "confirmation"
+	EventSubscribed    = "subscribed"
+	EventUnsubscribed  = "unsubscribed"
+	EventError         = "error"
+)
+
+const (
+	// number of events buffered for a subscriber. Subscribers that fall
+	// further behind are disconnected
+	subscriberBuffer = 256
+
+	// interval at which new blocks are checked for if no tendermint event is received
+	streamPollInterval = 10 * time.Second
+
+	streamWriteTimeout = 10 * time.Second
+)
+
+// SubscriptionRequest is sent by a client to change its subscriptions.
+// `Address` is required for the address topic and `TxHash` for the
+// confirmations topic
+type SubscriptionRequest struct {
+	Action  string `json:"action"` // "subscribe" or "unsubscribe"
+	Topic   string `json:"topic"`
+	Address string `json:"address,omitempty"`
+	TxHash  string `json:"tx_hash,omitempty"`
+}
+
+// Event is streamed to the subscribers of the matching topic. Only the fields
+// relevant to the event type are set
+type Event struct {
+	Type        string   `json:"type"`
+	BlockHeight *big.Int `json:"block_height,omitempty"` // plasma block the event occurred in
+
+	Block            *store.Block     `json:"block,omitempty"`
+	Address          *ethcmn.Address  `json:"address,omitempty"`
+	Position         *plasma.Position `json:"position,omitempty"`
+	Amount           *big.Int         `json:"amount,omitempty"`
+	TxHash           hexutil.Bytes    `json:"tx_hash,omitempty"`
+	ConfirmationHash hexutil.Bytes    `json:"confirmation_hash,omitempty"`
+
+	// responses to subscription requests
+	Request *SubscriptionRequest `json:"request,omitempty"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// subscriber is a single websocket connection and its subscriptions
+type subscriber struct {
+	events chan Event
+
+	mtx       sync.Mutex
+	blocks    bool
+	deposits  bool
+	addresses map[ethcmn.Address]bool
+	txHashes  map[string]bool
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{
+		events:    make(chan Event, subscriberBuffer),
+		addresses: make(map[ethcmn.Address]bool),
+		txHashes:  make(map[string]bool),
+	}
+}
+
+// update applies the subscription request. An error is returned if the request is malformed
+func (s *subscriber) update(req SubscriptionRequest) error {
+	var subscribe bool
+	switch req.Action {
+	case "subscribe":
+		subscribe = true
+	case "unsubscribe":
+	default:
+		return fmt.Errorf("action must be subscribe or unsubscribe")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	switch req.Topic {
+	case TopicBlocks:
+		s.blocks = subscribe
+	case TopicDeposits:
+		s.deposits = subscribe
+	case TopicAddress:
+		if !ethcmn.IsHexAddress(req.Address) {
+			return fmt.Errorf("address must be an ethereum 20-byte hex string")
+		}
+		if subscribe {
+			s.addresses[ethcmn.HexToAddress(req.Address)] = true
+		} else {
+			delete(s.addresses, ethcmn.HexToAddress(req.Address))
+		}
+	case TopicConfirmations:
+		hash, err := hex.DecodeString(utils.RemoveHexPrefix(req.TxHash))
+		if err != nil || len(hash) != 32 {
+			return fmt.Errorf("tx hash expected to be 32 bytes in hexadecimal format")
+		}
+		if subscribe {
+			s.txHashes[string(hash)] = true
+		} else {
+			delete(s.txHashes, string(hash))
+		}
+	default:
+		return fmt.Errorf("unknown topic %s", req.Topic)
+	}
+
+	return nil
+}
+
+// matches reports if the subscriber is subscribed to the event. A transaction
+// is confirmed once, so its confirmation subscription ends with the event
+func (s *subscriber) matches(event Event) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	switch event.Type {
+	case EventBlock:
+		return s.blocks
+	case EventDeposit:
+		return s.deposits || s.addresses[*event.Address]
+	case EventOutputCreated, EventOutputSpent:
+		return s.addresses[*event.Address]
+	case EventConfirmation:
+		if s.txHashes[string(event.TxHash)] {
+			delete(s.txHashes, string(event.TxHash))
+			return true
+		}
+	}
+
+	return false
+}
+
+// streamHub follows the plasma chain and fans the events of every new block
+// out to the subscribers. New blocks are detected through tendermint's block
+// header events, with periodic polling as a fallback
+type streamHub struct {
+	ctx context.CLIContext
+
+	startOnce sync.Once
+
+	mtx         sync.Mutex
+	subscribers map[*subscriber]bool
+	// last plasma block whose events have been streamed
+	lastHeight *big.Int
+}
+
+func newStreamHub(ctx context.CLIContext) *streamHub {
+	return &streamHub{
+		ctx:         ctx,
+		subscribers: make(map[*subscriber]bool),
+	}
+}
+
+// start follows the chain in a separate goroutine. Streaming begins at the latest plasma block
+func (hub *streamHub) start() {
+	hub.startOnce.Do(func() {
+		if height, err := Height(hub.ctx); err == nil {
+			hub.lastHeight, _ = new(big.Int).SetString(height, 10)
+		}
+		if hub.lastHeight == nil {
+			hub.lastHeight = big.NewInt(0)
+		}
+
+		// a missing tendermint subscription only delays events until the next poll
+		headers := make(chan interface{}, 10)
+		if hub.ctx.NodeURI != "" {
+			tmClient := rpcclient.NewHTTP(hub.ctx.NodeURI, "/websocket")
+			if err := tmClient.Start(); err == nil {
+				tmClient.Subscribe(gocontext.Background(), "plasma-rest-server", tmtypes.EventQueryNewBlockHeader, headers)
+			}
+		}
+
+		go func() {
+			ticker := time.NewTicker(streamPollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-headers:
+				case <-ticker.C:
+				}
+
+				hub.sync()
+			}
+		}()
+	})
+}
+
+func (hub *streamHub) add(s *subscriber) {
+	hub.mtx.Lock()
+	defer hub.mtx.Unlock()
+
+	hub.subscribers[s] = true
+}
+
+func (hub *streamHub) remove(s *subscriber) {
+	hub.mtx.Lock()
+	defer hub.mtx.Unlock()
+
+	if hub.subscribers[s] {
+		delete(hub.subscribers, s)
+		close(s.events)
+	}
+}
+
+// sync streams the events of every plasma block created since the last sync
+func (hub *streamHub) sync() {
+	height, err := Height(hub.ctx)
+	if err != nil {
+		return
+	}
+	latest, ok := new(big.Int).SetString(height, 10)
+	if !ok {
+		return
+	}
+
+	for latest.Cmp(hub.lastHeight) > 0 {
+		next := new(big.Int).Add(hub.lastHeight, utils.Big1)
+		events, err := BlockEvents(hub.ctx, next)
+		if err != nil {
+			// retried on the next sync
+			return
+		}
+
+		for _, event := range events {
+			hub.broadcast(event)
+		}
+		hub.lastHeight = next
+	}
+}
+
+// broadcast sends the event to every matching subscriber. Subscribers
+// that cannot keep up are disconnected
+func (hub *streamHub) broadcast(event Event) {
+	hub.mtx.Lock()
+	defer hub.mtx.Unlock()
+
+	for s := range hub.subscribers {
+		if !s.matches(event) {
+			continue
+		}
+
+		select {
+		case s.events <- event:
+		default:
+			delete(hub.subscribers, s)
+			close(s.events)
+		}
+	}
+}
+
+// BlockEvents retrieves the plasma block at `height` and derives the events of
+// the deposits, outputs and confirmation hashes of its committed transactions
+func BlockEvents(ctx context.CLIContext, height *big.Int) ([]Event, error) {
+	block, err := Block(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	events := []Event{{Type: EventBlock, BlockHeight: height, Block: &block}}
+
+	tmHeight := int64(block.TMBlockHeight)
+	tmBlock, err := ctx.Client.Block(&tmHeight)
+	if err != nil {
+		return nil, err
+	}
+	results, err := ctx.Client.BlockResults(&tmHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, txBytes := range deliveredTxs(tmBlock.Block.Data.Txs, results.Results.DeliverTx) {
+		tx, err := msgs.TxDecoder(txBytes)
+		if err != nil {
+			continue
+		}
+
+		switch msg := tx.(type) {
+		case msgs.IncludeDepositMsg:
+			pos := plasma.NewPosition(nil, 0, 0, msg.DepositNonce)
+			output, err := TxOutput(ctx, pos)
+			if err != nil {
+				continue
+			}
+
+			owner := output.Output.Owner
+			events = append(events, Event{Type: EventDeposit, BlockHeight: height, Address: &owner, Position: &pos, Amount: output.Output.Amount})
+
+		case msgs.SpendMsg:
+			committed, err := Tx(ctx, msg.TxHash())
+			if err != nil || committed.Position.BlockNum.Cmp(height) != 0 {
+				continue
+			}
+			txHash := msg.TxHash()
+
+			events = append(events, Event{Type: EventConfirmation, BlockHeight: height, TxHash: txHash, ConfirmationHash: committed.ConfirmationHash})
+
+			for _, input := range msg.Inputs {
+				spent, err := TxOutput(ctx, input.Position)
+				if err != nil {
+					continue
+				}
+
+				owner, pos := spent.Output.Owner, input.Position
+				events = append(events, Event{Type: EventOutputSpent, BlockHeight: height, Address: &owner, Position: &pos, Amount: spent.Output.Amount, TxHash: txHash})
+			}
+
+			for i, output := range msg.Outputs {
+				owner := output.Owner
+				pos := plasma.NewPosition(height, committed.Position.TxIndex, uint8(i), nil)
+				events = append(events, Event{Type: EventOutputCreated, BlockHeight: height, Address: &owner, Position: &pos, Amount: output.Amount, TxHash: txHash})
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// deliveredTxs returns the transactions of a block whose DeliverTx result is OK.
+// Failed transactions, such as the inclusion of a deposit that has already been
+// included, are part of the block as well
+func deliveredTxs(txs tmtypes.Txs, results []*abci.ResponseDeliverTx) []tmtypes.Tx {
+	var delivered []tmtypes.Tx
+	for i, tx := range txs {
+		if i < len(results) && results[i].IsOK() {
+			delivered = append(delivered, tx)
+		}
+	}
+	return delivered
+}
+
+var upgrader = websocket.Upgrader{
+	// the rest server sets its own CORS policy
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// streamHandler upgrades the request to a websocket over which the client sends
+// SubscriptionRequests and receives the Events of its subscriptions
+func streamHandler(ctx context.CLIContext, hub *streamHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// the upgrader has already replied with an error
+			return
+		}
+		defer conn.Close()
+
+		hub.start()
+		s := newSubscriber()
+		hub.add(s)
+		defer hub.remove(s)
+
+		// writer
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for event := range s.events {
+				conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+				if err := conn.WriteJSON(event); err != nil {
+					conn.Close()
+					return
+				}
+			}
+
+			// disconnected by the hub
+			conn.Close()
+		}()
+
+		// reader
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				break
+			}
+
+			var req SubscriptionRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				hub.reply(s, Event{Type: EventError, Error: "malformed subscription request"})
+				continue
+			}
+
+			if err := s.update(req); err != nil {
+				hub.reply(s, Event{Type: EventError, Request: &req, Error: err.Error()})
+				continue
+			}
+
+			response := Event{Type: EventSubscribed, Request: &req}
+			if req.Action == "unsubscribe" {
+				response.Type = EventUnsubscribed
+			}
+			hub.reply(s, response)
+
+			// the transaction may have been committed before the subscription
+			if req.Action == "subscribe" && req.Topic == TopicConfirmations {
+				hash, _ := hex.DecodeString(utils.RemoveHexPrefix(req.TxHash))
+				if tx, err := Tx(ctx, hash); err == nil {
+					if event := confirmationEvent(hash, tx); s.matches(event) {
+						hub.reply(s, event)
+					}
+				}
+			}
+		}
+
+		hub.remove(s)
+		<-done
+	}
+}
+
+// reply sends an event to a single subscriber if it is still connected
+func (hub *streamHub) reply(s *subscriber, event Event) {
+	hub.mtx.Lock()
+	defer hub.mtx.Unlock()
+
+	if !hub.subscribers[s] {
+		return
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		delete(hub.subscribers, s)
+		close(s.events)
+	}
+}
+
+func confirmationEvent(hash []byte, tx store.Transaction) Event {
+	return Event{
+		Type:             EventConfirmation,
+		BlockHeight:      tx.Position.BlockNum,
+		TxHash:           hash,
+		ConfirmationHash: tx.ConfirmationHash,
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"math/big"
+	"testing"
+)
+
+func TestSubscriptions(t *testing.T) {
+	s := newSubscriber()
+	addr := ethcmn.HexToAddress("0x5475b99e01ac3bb08b24fd754e2868dbb829bc3a")
+	other := ethcmn.HexToAddress("0xec36ead9c897b609a4ffa5820e1b2b137d454343")
+	txHash := make([]byte, 32)
+	txHash[31] = 1
+	pos := plasma.NewPosition(big.NewInt(1), 0, 0, nil)
+
+	block := Event{Type: EventBlock, BlockHeight: big.NewInt(1)}
+	deposit := Event{Type: EventDeposit, Address: &other, Position: &pos}
+	created := Event{Type: EventOutputCreated, Address: &addr, Position: &pos}
+	spent := Event{Type: EventOutputSpent, Address: &other, Position: &pos}
+	confirmation := Event{Type: EventConfirmation, TxHash: txHash}
+
+	for _, event := range []Event{block, deposit, created, spent, confirmation} {
+		require.False(t, s.matches(event), "matched %s without a subscription", event.Type)
+	}
+
+	// malformed requests
+	invalid := []SubscriptionRequest{
+		{Action: "watch", Topic: TopicBlocks},
+		{Action: "subscribe", Topic: "fees"},
+		{Action: "subscribe", Topic: TopicAddress, Address: "0x1234"},
+		{Action: "subscribe", Topic: TopicConfirmations, TxHash: "0x1234"},
+	}
+	for i, req := range invalid {
+		require.Error(t, s.update(req), "case %d: accepted a malformed request", i)
+	}
+
+	require.NoError(t, s.update(SubscriptionRequest{Action: "subscribe", Topic: TopicBlocks}))
+	require.NoError(t, s.update(SubscriptionRequest{Action: "subscribe", Topic: TopicAddress, Address: addr.Hex()}))
+	require.NoError(t, s.update(SubscriptionRequest{Action: "subscribe", Topic: TopicConfirmations, TxHash: "0x0000000000000000000000000000000000000000000000000000000000000001"}))
+
+	require.True(t, s.matches(block))
+	require.True(t, s.matches(created))
+	require.False(t, s.matches(spent), "matched an output of another address")
+	require.False(t, s.matches(deposit), "matched a deposit of another address")
+
+	// a transaction is only confirmed once
+	require.True(t, s.matches(confirmation))
+	require.False(t, s.matches(confirmation), "matched a confirmation twice")
+
+	// deposits of every address
+	require.NoError(t, s.update(SubscriptionRequest{Action: "subscribe", Topic: TopicDeposits}))
+	require.True(t, s.matches(deposit))
+
+	require.NoError(t, s.update(SubscriptionRequest{Action: "unsubscribe", Topic: TopicBlocks}))
+	require.NoError(t, s.update(SubscriptionRequest{Action: "unsubscribe", Topic: TopicAddress, Address: addr.Hex()}))
+	require.False(t, s.matches(block), "matched a block after unsubscribing")
+	require.False(t, s.matches(created), "matched an output after unsubscribing")
+}
+
+// only transactions delivered successfully produce events
+func TestDeliveredTxs(t *testing.T) {
+	txs := tmtypes.Txs{tmtypes.Tx("included deposit"), tmtypes.Tx("duplicate deposit"), tmtypes.Tx("spend")}
+	results := []*abci.ResponseDeliverTx{{}, {Code: 1, Log: "deposit already included"}, {}}
+
+	require.Equal(t, []tmtypes.Tx{txs[0], txs[2]}, deliveredTxs(txs, results))
+	require.Empty(t, deliveredTxs(txs, nil), "transactions without results reported as delivered")
+}
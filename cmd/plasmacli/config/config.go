@@ -32,7 +32,14 @@ node = "{{ .PlasmadNodeURL }}"
 trust_node = {{ .PlasmadTrustNode }}
 
 # Chain identifier. Must be set if trust-node == false
-chain_id = "{{ .PlasmadChainID }}"`
+chain_id = "{{ .PlasmadChainID }}"
+
+
+##### signer configuration #####
+
+# Signer used for accounts. "keystore" signs with the local keystore.
+# Otherwise the http, ws or ipc endpoint of a clef compatible external signer
+signer = "{{ .Signer }}"`
 
 // Config is the struct representation of the toml file. Must match the
 // above defaultConfigTemplate
@@ -45,6 +52,9 @@ type Config struct {
 	PlasmadNodeURL   string `mapstructure:"node"`
 	PlasmadTrustNode bool   `mapstructure:"trust_node"`
 	PlasmadChainID   string `mapstructure:"chain_id"`
+
+	// Signer config
+	Signer string `mapstructure:"signer"`
 }
 
 var configTemplate *template.Template
@@ -65,6 +75,7 @@ func DefaultConfig() Config {
 		PlasmadNodeURL:        "tcp://localhost:26657",
 		PlasmadTrustNode:      false,
 		PlasmadChainID:        "",
+		Signer:                "keystore",
 	}
 }
 
@@ -25,31 +25,53 @@ func GetContractConn() (*eth.Plasma, error) {
 	return plasmaContract, nil
 }
 
-func setupContractConn() (*eth.Plasma, error) {
+// GetContractAddress returns the address of the plasma contract in the config
+// without connecting to the ethereum node
+func GetContractAddress() (ethcmn.Address, error) {
 	conf, err := ParseConfigFromViper()
 	if err != nil {
-		return nil, err
+		return ethcmn.Address{}, err
 	}
 
-	dir := viper.GetString(flags.Home)
-	if dir[len(dir)-1] != '/' {
-		dir = dir + "/"
+	if conf.EthPlasmaContractAddr == "" || !ethcmn.IsHexAddress(conf.EthPlasmaContractAddr) {
+		return ethcmn.Address{}, fmt.Errorf("please specify a valid contract address in %sconfig.toml", configDir())
+	}
+
+	return ethcmn.HexToAddress(conf.EthPlasmaContractAddr), nil
+}
+
+func setupContractConn() (*eth.Plasma, error) {
+	conf, err := ParseConfigFromViper()
+	if err != nil {
+		return nil, err
 	}
 
 	if conf.EthNodeURL == "" {
-		return nil, fmt.Errorf("please specify a node url for eth connection in %sconfig.toml", dir)
-	} else if conf.EthPlasmaContractAddr == "" || !ethcmn.IsHexAddress(conf.EthPlasmaContractAddr) {
-		return nil, fmt.Errorf("please specify a valid contract address in %sconfig.toml", dir)
+		return nil, fmt.Errorf("please specify a node url for eth connection in %sconfig.toml", configDir())
+	}
+	contractAddr, err := GetContractAddress()
+	if err != nil {
+		return nil, err
 	}
 
 	ethClient, err := eth.InitEthConn(conf.EthNodeURL)
 	if err != nil {
 		return nil, err
 	}
-	plasma, err := eth.InitPlasma(ethcmn.HexToAddress(conf.EthPlasmaContractAddr), ethClient, 0)
+	plasma, err := eth.InitPlasma(contractAddr, ethClient, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	return plasma, nil
 }
+
+// configDir returns the home directory with a trailing slash
+func configDir() string {
+	dir := viper.GetString(flags.Home)
+	if dir[len(dir)-1] != '/' {
+		dir = dir + "/"
+	}
+
+	return dir
+}
@@ -88,6 +88,27 @@ func AddAccount(name string) (ethcmn.Address, error) {
 	return acc.Address, nil
 }
 
+// AddExternalAccount names an address whose key is held by an external signer.
+func AddExternalAccount(name string, addr ethcmn.Address) error {
+	dir := getDir(accountsDir)
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return fmt.Errorf("leveldb: %s", err)
+	}
+	defer db.Close()
+
+	key := []byte(name)
+	if _, err = db.Get(key, nil); err == nil {
+		return fmt.Errorf("you are trying to override an existing account name. Please delete it first")
+	}
+
+	if err = db.Put(key, addr.Bytes(), nil); err != nil {
+		return fmt.Errorf("leveldb: %s", err)
+	}
+
+	return nil
+}
+
 // GetAccount retrieves the address of an account.
 func GetAccount(name string) (ethcmn.Address, error) {
 	dir := getDir(accountsDir)
@@ -123,16 +144,24 @@ func DeleteAccount(name string) error {
 		return fmt.Errorf("leveldb: %s", err)
 	}
 
+	acc := accounts.Account{
+		Address: ethcmn.BytesToAddress(addr),
+	}
+
+	// accounts of an external signer only have a name to remove
+	if !ks.HasAddress(acc.Address) {
+		if err = db.Delete([]byte(name), nil); err != nil {
+			return fmt.Errorf("leveldb: %s", err)
+		}
+		return nil
+	}
+
 	buf := cosmoscli.BufferStdin()
 	password, err := cosmoscli.GetPassword(PassphrasePrompt, buf)
 	if err != nil {
 		return err
 	}
 
-	acc := accounts.Account{
-		Address: ethcmn.BytesToAddress(addr),
-	}
-
 	if err = ks.Delete(acc, password); err != nil {
 		return fmt.Errorf("keystore: %s", err)
 	}
@@ -287,52 +316,12 @@ func Export(name string) (keyJSON []byte, err error) {
 	return accJSON, nil
 }
 
-// SignHashWithPassphrase will sign over the provided hash if the the passphrase
-// provided through user interaction is correct.
-func SignHashWithPassphrase(signer string, hash []byte) ([]byte, error) {
-	addr, err := GetAccount(signer)
-	if err != nil {
-		return nil, err
-	}
-
-	acc := accounts.Account{
-		Address: addr,
-	}
-
-	buf := cosmoscli.BufferStdin()
-	password, err := cosmoscli.GetPassword(PassphrasePrompt, buf)
-	if err != nil {
-		return nil, err
-	}
-
-	var sig []byte
-	sig, err = ks.SignHashWithPassphrase(acc, password, hash)
-	if err != nil {
-		return nil, fmt.Errorf("keystore: %s", err)
-	}
-
-	return sig, nil
-}
-
-// GetKey returns the private key mapped to the provided key name.
-func GetKey(name string) (*ecdsa.PrivateKey, error) {
-	dir := getDir(accountsDir)
-	db, err := leveldb.OpenFile(dir, nil)
-	if err != nil {
-		return nil, fmt.Errorf("leveldb: %s", err)
-	}
-	defer db.Close()
-
-	addr, err := db.Get([]byte(name), nil)
-	if err == leveldb.ErrNotFound {
-		return nil, fmt.Errorf("account does not exist")
-	} else if err != nil {
-		return nil, fmt.Errorf("leveldb: %s", err)
-	}
-
+// getKeyByAddress returns the decrypted private key of the keystore account
+// with the given address.
+func getKeyByAddress(addr ethcmn.Address) (*ecdsa.PrivateKey, error) {
 	acc, err := ks.Find(
 		accounts.Account{
-			Address: ethcmn.BytesToAddress(addr),
+			Address: addr,
 		},
 	)
 	if err != nil {
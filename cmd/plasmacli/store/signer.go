@@ -0,0 +1,273 @@
+package store
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"strings"
+	"time"
+)
+
+const (
+	// KeystoreSigner is the signer setting for the local keystore
+	KeystoreSigner = "keystore"
+
+	// timeout for a single request to an external signer. Requests may wait
+	// on manual approval so this is generous
+	externalSignerTimeout = 5 * time.Minute
+)
+
+// signer backend set with SetSigner. The local keystore is used when empty
+var signerBackend string
+
+// Signer signs plasma and rootchain transactions on behalf of a single account
+type Signer interface {
+	// Address returns the address of the account
+	Address() ethcmn.Address
+
+	// Unlock prepares the signer for use without any further user interaction
+	Unlock() error
+
+	// SignHash signs over the ethereum signed message hash of the 32 byte
+	// hash. The recovery id of the returned signature is 0 or 1
+	SignHash(hash []byte) ([]byte, error)
+
+	// SignTx signs a rootchain transaction
+	SignTx(tx *types.Transaction) (*types.Transaction, error)
+}
+
+// SetSigner sets the backend used by GetSigner. Either KeystoreSigner or the
+// endpoint of a clef compatible external signer
+func SetSigner(backend string) {
+	signerBackend = strings.TrimSpace(backend)
+}
+
+// GetSigner returns the signer for the account with the given name. The
+// account may also be given as a hex address
+func GetSigner(account string) (Signer, error) {
+	addr, err := ResolveAccount(account)
+	if err != nil {
+		return nil, err
+	}
+
+	if signerBackend == "" || signerBackend == KeystoreSigner {
+		return &keystoreSigner{address: addr}, nil
+	}
+
+	return NewExternalSigner(signerBackend, addr)
+}
+
+// TransactOpts returns rootchain transact options signed for by the signer
+func TransactOpts(signer Signer, gasLimit uint64) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(addr ethcmn.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(tx)
+		},
+		GasLimit: gasLimit,
+	}
+}
+
+// ResolveAccount maps an account name, or a hex address that is not the name
+// of an account, to its address
+func ResolveAccount(account string) (ethcmn.Address, error) {
+	addr, err := GetAccount(account)
+	if err == nil {
+		return addr, nil
+	}
+	if ethcmn.IsHexAddress(account) {
+		return ethcmn.HexToAddress(account), nil
+	}
+	return ethcmn.Address{}, err
+}
+
+// keystoreSigner signs with a key from the local keystore. The passphrase is
+// prompted for once, on first use
+type keystoreSigner struct {
+	address ethcmn.Address
+	key     *ecdsa.PrivateKey
+}
+
+func (s *keystoreSigner) Address() ethcmn.Address {
+	return s.address
+}
+
+func (s *keystoreSigner) Unlock() error {
+	if s.key != nil {
+		return nil
+	}
+
+	key, err := getKeyByAddress(s.address)
+	if err != nil {
+		return err
+	}
+	s.key = key
+	return nil
+}
+
+func (s *keystoreSigner) SignHash(hash []byte) ([]byte, error) {
+	if err := s.Unlock(); err != nil {
+		return nil, err
+	}
+
+	sig, err := crypto.Sign(utils.ToEthSignedMessageHash(hash), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: %s", err)
+	}
+	return sig, nil
+}
+
+func (s *keystoreSigner) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	if err := s.Unlock(); err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, types.HomesteadSigner{}, s.key)
+}
+
+// ExternalSigner signs through a clef compatible external signer using the
+// account_* rpc api. Every request is subject to the approval rules of the
+// external signer
+type ExternalSigner struct {
+	client  *rpc.Client
+	address ethcmn.Address
+}
+
+// NewExternalSigner connects to the external signer at the http, ws or ipc
+// endpoint and returns a signer for the given address
+func NewExternalSigner(endpoint string, address ethcmn.Address) (*ExternalSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("external signer: %s", err)
+	}
+
+	signer := &ExternalSigner{
+		client:  client,
+		address: address,
+	}
+
+	var version string
+	if err := signer.call(&version, "account_version"); err != nil {
+		return nil, fmt.Errorf("external signer: %s", err)
+	}
+
+	return signer, nil
+}
+
+// Address implements Signer
+func (s *ExternalSigner) Address() ethcmn.Address {
+	return s.address
+}
+
+// Unlock checks that the account is managed by the external signer
+func (s *ExternalSigner) Unlock() error {
+	var addrs []ethcmn.Address
+	if err := s.call(&addrs, "account_list"); err != nil {
+		return fmt.Errorf("external signer: %s", err)
+	}
+
+	for _, addr := range addrs {
+		if addr == s.address {
+			return nil
+		}
+	}
+	return fmt.Errorf("external signer: account 0x%x is not available", s.address)
+}
+
+// SignHash implements Signer. The hash is signed as text/plain data which
+// the external signer prefixes with the ethereum signed message header
+func (s *ExternalSigner) SignHash(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("hash must be 32 bytes in length")
+	}
+
+	var sig hexutil.Bytes
+	err := s.call(&sig, "account_signData", "text/plain", s.address, hexutil.Encode(hash))
+	if err != nil {
+		return nil, fmt.Errorf("external signer: %s", err)
+	} else if len(sig) != 65 {
+		return nil, fmt.Errorf("external signer: invalid signature length %d", len(sig))
+	}
+
+	// the external signer returns a recovery id of 27 or 28
+	if sig[64] == 27 || sig[64] == 28 {
+		sig[64] -= 27
+	}
+
+	if addr, err := recoverAddress(hash, sig); err != nil || addr != s.address {
+		return nil, fmt.Errorf("external signer: signature does not match account 0x%x", s.address)
+	}
+	return sig, nil
+}
+
+// SignTx implements Signer. The external signer decides on the chain id used
+// to sign the transaction
+func (s *ExternalSigner) SignTx(tx *types.Transaction) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := externalTxArgs{
+		From:     s.address,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     &data,
+	}
+
+	var res externalTxResult
+	if err := s.call(&res, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("external signer: %s", err)
+	}
+
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(res.Raw, signed); err != nil {
+		return nil, fmt.Errorf("external signer: invalid signed transaction: %s", err)
+	}
+	return signed, nil
+}
+
+// Close closes the connection to the external signer
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
+
+func (s *ExternalSigner) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), externalSignerTimeout)
+	defer cancel()
+	return s.client.CallContext(ctx, result, method, args...)
+}
+
+// externalTxArgs are the transaction arguments of account_signTransaction
+type externalTxArgs struct {
+	From     ethcmn.Address  `json:"from"`
+	To       *ethcmn.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     *hexutil.Bytes  `json:"data"`
+}
+
+// externalTxResult is the result of account_signTransaction
+type externalTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// returns the address that signed over the ethereum signed message hash of hash
+func recoverAddress(hash, sig []byte) (ethcmn.Address, error) {
+	pubKey, err := crypto.SigToPub(utils.ToEthSignedMessageHash(hash), sig)
+	if err != nil {
+		return ethcmn.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
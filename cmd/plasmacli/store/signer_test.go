@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// stand-in for clef serving the account_* api with a single key
+type standInSigner struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+func (s *standInSigner) Version() string {
+	return "6.0.0"
+}
+
+func (s *standInSigner) List() []ethcmn.Address {
+	return []ethcmn.Address{crypto.PubkeyToAddress(s.key.PublicKey)}
+}
+
+func (s *standInSigner) SignData(ctx context.Context, contentType string, addr ethcmn.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != "text/plain" {
+		return nil, fmt.Errorf("unsupported content type %s", contentType)
+	} else if addr.Address() != crypto.PubkeyToAddress(s.key.PublicKey) {
+		return nil, fmt.Errorf("unknown account")
+	}
+
+	sig, err := crypto.Sign(utils.ToEthSignedMessageHash(data), s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+func (s *standInSigner) SignTransaction(ctx context.Context, args externalTxArgs) (*externalTxResult, error) {
+	if args.From != crypto.PubkeyToAddress(s.key.PublicKey) {
+		return nil, fmt.Errorf("unknown account")
+	}
+
+	tx := types.NewTransaction(uint64(args.Nonce), *args.To, (*big.Int)(&args.Value), uint64(args.Gas), (*big.Int)(&args.GasPrice), *args.Data)
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(s.chainID), s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &externalTxResult{Raw: raw}, nil
+}
+
+// returns the endpoint of the stand-in and a function to shut it down
+func startStandInSigner(t *testing.T, key *ecdsa.PrivateKey) (string, func()) {
+	server := rpc.NewServer()
+	err := server.RegisterName("account", &standInSigner{key, big.NewInt(1)})
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(server)
+	return httpServer.URL, func() {
+		httpServer.Close()
+		server.Stop()
+	}
+}
+
+func TestExternalSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	endpoint, stop := startStandInSigner(t, key)
+	defer stop()
+
+	signer, err := NewExternalSigner(endpoint, addr)
+	require.NoError(t, err, "failed to connect to the external signer")
+	defer signer.Close()
+
+	require.NoError(t, signer.Unlock(), "account of the external signer not found")
+	require.Equal(t, addr, signer.Address())
+
+	// signatures must match the keystore format
+	hash := crypto.Keccak256([]byte("plasma"))
+	sig, err := signer.SignHash(hash)
+	require.NoError(t, err, "failed to sign hash")
+	require.Len(t, sig, 65)
+	require.Contains(t, []byte{0, 1}, sig[64], "recovery id not normalized")
+
+	expected, err := crypto.Sign(utils.ToEthSignedMessageHash(hash), key)
+	require.NoError(t, err)
+	require.Equal(t, expected, sig, "signature does not match a keystore signature")
+
+	_, err = signer.SignHash(hash[:31])
+	require.Error(t, err, "signed a hash of the wrong length")
+
+	// rootchain transactions are signed through the transact opts
+	contract := ethcmn.HexToAddress("0x5cae340fb2c2bb0a2f194a95cda8a1ffdc9d2f85")
+	tx := types.NewTransaction(3, contract, big.NewInt(100), 300000, big.NewInt(10), []byte("deposit"))
+	opts := TransactOpts(signer, 300000)
+	require.Equal(t, addr, opts.From)
+	require.Equal(t, uint64(300000), opts.GasLimit)
+
+	signed, err := opts.Signer(addr, tx)
+	require.NoError(t, err, "failed to sign transaction")
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(1)), signed)
+	require.NoError(t, err)
+	require.Equal(t, addr, sender, "transaction not signed by the account")
+	require.Equal(t, tx.Nonce(), signed.Nonce())
+	require.Equal(t, tx.Data(), signed.Data())
+	require.Equal(t, tx.Value(), signed.Value())
+
+	_, err = opts.Signer(contract, tx)
+	require.Error(t, err, "signed for a different address")
+
+	// accounts not held by the external signer
+	other, err := NewExternalSigner(endpoint, contract)
+	require.NoError(t, err)
+	defer other.Close()
+	require.Error(t, other.Unlock(), "unlocked an unknown account")
+	_, err = other.SignHash(hash)
+	require.Error(t, err, "signed for an unknown account")
+}
+
+func TestGetSigner(t *testing.T) {
+	os.Mkdir("testing", os.ModePerm)
+	InitKeystore("./testing")
+	defer os.RemoveAll("testing")
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	endpoint, stop := startStandInSigner(t, key)
+	defer stop()
+
+	// keystore is the default
+	signer, err := GetSigner(addr.Hex())
+	require.NoError(t, err)
+	require.IsType(t, &keystoreSigner{}, signer)
+	require.Equal(t, addr, signer.Address())
+
+	_, err = GetSigner("unknown")
+	require.Error(t, err, "retrieved a signer for an unknown account")
+
+	SetSigner(endpoint)
+	defer SetSigner(KeystoreSigner)
+
+	// external accounts are resolved by name or address
+	require.NoError(t, AddExternalAccount("clef", addr))
+	require.Error(t, AddExternalAccount("clef", addr), "overwrote an existing account name")
+	for _, account := range []string{"clef", addr.Hex()} {
+		signer, err := GetSigner(account)
+		require.NoError(t, err, "failed to retrieve signer for %s", account)
+		require.IsType(t, &ExternalSigner{}, signer)
+		require.Equal(t, addr, signer.Address())
+		require.NoError(t, signer.Unlock())
+	}
+
+	// names of external accounts are removed without a passphrase
+	require.NoError(t, DeleteAccount("clef"))
+	_, err = GetAccount("clef")
+	require.Error(t, err, "external account name not deleted")
+}
@@ -1,43 +1,118 @@
 package store
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"math/big"
+	"os"
+	"unicode/utf8"
 )
 
 const (
-	signatureDir = "data/signatures.ldb"
+	signatureDir = "data/confirmsigs.ldb"
+
+	// signatures stored by earlier versions were keyed without the output
+	// index and deposit nonce
+	legacySignatureDir = "data/signatures.ldb"
+
+	// block number (32) | tx index (2) | output index (1) | deposit nonce (32)
+	sigKeyLength = 67
 )
 
-// SaveSig saves the confirmation signatures. If prepend is set to true, sig
-// will be prepended to the currently stored signatures. Otherwise it will be appended
-// This ordering should be determined by input order in the transaction. If
-// the length of the currently stored signatures is 130 an error is returned.
-func SaveSig(position plasma.Position, sig []byte, prepend bool) error {
-	if len(sig) != 65 {
-		return fmt.Errorf("signature must have a length of 65 bytes")
-	}
+var (
+	sigDB    *leveldb.DB
+	sigDBDir string
+)
 
-	signatures, err := GetSig(position)
-	if len(signatures) == 130 {
-		return fmt.Errorf("two signatures already exist for the given position")
-	}
+// StoredSigs are the confirm signatures stored for the output at Position.
+// Signatures are ordered by the inputs of the transaction that created the
+// output and are empty if they have not been saved
+type StoredSigs struct {
+	Position   plasma.Position
+	Signatures [][]byte
+}
 
-	if prepend {
-		signatures = append(sig, signatures...)
-	} else {
-		signatures = append(signatures, sig...)
+// Complete returns true if the signature of every input has been saved
+func (s StoredSigs) Complete() bool {
+	for _, sig := range s.Signatures {
+		if len(sig) == 0 {
+			return false
+		}
 	}
+	return true
+}
 
+// signatures database is opened once per process and reopened if the home
+// directory changes
+func openSigDB() (*leveldb.DB, error) {
 	dir := getDir(signatureDir)
+	if sigDB != nil && sigDBDir == dir {
+		return sigDB, nil
+	}
+	if err := CloseSigDB(); err != nil {
+		return nil, err
+	}
+
 	db, err := leveldb.OpenFile(dir, nil)
 	if err != nil {
-		return fmt.Errorf("failed to open db for signatures: %s", err)
+		return nil, fmt.Errorf("failed to open db for signatures: %s", err)
+	}
+	sigDB, sigDBDir = db, dir
+	return sigDB, nil
+}
+
+// CloseSigDB closes the signature database. It is reopened when next used
+func CloseSigDB() error {
+	if sigDB == nil {
+		return nil
+	}
+	err := sigDB.Close()
+	sigDB, sigDBDir = nil, ""
+	return err
+}
+
+// SaveSig saves the confirmation signature generated by the owner of the input
+// at `inputIndex` of a transaction spending `numInputs` inputs. Signatures are
+// stored in input order so that they can be used directly as the confirm
+// signatures of a spend. An error is returned if the signature for the input
+// already exists.
+func SaveSig(position plasma.Position, sig []byte, inputIndex, numInputs int) error {
+	if len(sig) != 65 {
+		return fmt.Errorf("signature must have a length of 65 bytes")
+	}
+	if inputIndex < 0 || inputIndex >= numInputs || numInputs > plasma.MaxTxInputs {
+		return fmt.Errorf("input index out of range")
+	}
+
+	db, err := openSigDB()
+	if err != nil {
+		return err
 	}
-	defer db.Close()
 
 	k := getSigKey(position)
+	signatures, err := db.Get(k, nil)
+	if err != nil && err != leveldb.ErrNotFound {
+		return fmt.Errorf("failed to get signature: %s", err)
+	}
+	if len(signatures) < 65*numInputs {
+		signatures = append(signatures, make([]byte, 65*numInputs-len(signatures))...)
+	}
+
+	slot := signatures[65*inputIndex : 65*(inputIndex+1)]
+	if !bytes.Equal(slot, make([]byte, 65)) {
+		return fmt.Errorf("signature already exists for input %d of the given position", inputIndex)
+	}
+	copy(slot, sig)
+
 	if err := db.Put(k, signatures, nil); err != nil {
 		return fmt.Errorf("failed to save confirmation signature: %s", err)
 	}
@@ -45,14 +120,13 @@ func SaveSig(position plasma.Position, sig []byte, prepend bool) error {
 	return nil
 }
 
-// GetSig retrieves the confirmation signatures.
+// GetSig retrieves the concatenated confirmation signatures stored for the
+// output at the position.
 func GetSig(position plasma.Position) ([]byte, error) {
-	dir := getDir(signatureDir)
-	db, err := leveldb.OpenFile(dir, nil)
+	db, err := openSigDB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open db for signature: %s", err)
+		return nil, err
 	}
-	defer db.Close()
 
 	k := getSigKey(position)
 	if sig, err := db.Get(k, nil); err != nil {
@@ -62,7 +136,263 @@ func GetSig(position plasma.Position) ([]byte, error) {
 	}
 }
 
-// returns the key used for confirm signature mapping
+// DeleteSig removes the confirmation signatures stored for the output at the
+// position.
+func DeleteSig(position plasma.Position) error {
+	db, err := openSigDB()
+	if err != nil {
+		return err
+	}
+
+	if err := db.Delete(getSigKey(position), nil); err != nil {
+		return fmt.Errorf("failed to delete signature: %s", err)
+	}
+	return nil
+}
+
+// ListSigs returns the confirmation signatures of every stored position
+// ordered by position.
+func ListSigs() ([]StoredSigs, error) {
+	db, err := openSigDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []StoredSigs
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		pos, err := sigKeyPosition(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		value := iter.Value()
+		sigs := make([][]byte, len(value)/65)
+		for i := range sigs {
+			if sig := value[65*i : 65*(i+1)]; !bytes.Equal(sig, make([]byte, 65)) {
+				sigs[i] = append([]byte{}, sig...)
+			}
+		}
+		list = append(list, StoredSigs{pos, sigs})
+	}
+
+	return list, iter.Error()
+}
+
+type sigsJSON struct {
+	Position   string   `json:"position"`
+	Signatures []string `json:"signatures"`
+}
+
+// ExportSigs returns the json encoding of every stored confirmation
+// signature. Signatures that have not been saved are empty strings.
+func ExportSigs() ([]byte, error) {
+	list, err := ListSigs()
+	if err != nil {
+		return nil, err
+	}
+
+	export := make([]sigsJSON, len(list))
+	for i, stored := range list {
+		export[i].Position = stored.Position.String()
+		export[i].Signatures = make([]string, len(stored.Signatures))
+		for j, sig := range stored.Signatures {
+			if len(sig) != 0 {
+				export[i].Signatures[j] = fmt.Sprintf("0x%x", sig)
+			}
+		}
+	}
+
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// ImportSigs saves the confirmation signatures of json encoded by ExportSigs.
+// Signatures that are already stored are skipped and a conflicting signature
+// for an input returns an error. The number of signatures saved is returned
+// along with any error.
+func ImportSigs(bz []byte) (int, error) {
+	var imported []sigsJSON
+	if err := json.Unmarshal(bz, &imported); err != nil {
+		return 0, fmt.Errorf("json: %s", err)
+	}
+
+	count := 0
+	for _, entry := range imported {
+		pos, err := plasma.FromPositionString(entry.Position)
+		if err != nil {
+			return count, err
+		}
+
+		sigs := make([][]byte, len(entry.Signatures))
+		for i, sigStr := range entry.Signatures {
+			if sigStr == "" {
+				continue
+			}
+			sig, err := hex.DecodeString(utils.RemoveHexPrefix(sigStr))
+			if err != nil {
+				return count, fmt.Errorf("signature %d of %s is not in hexadecimal format", i, pos)
+			}
+			sigs[i] = sig
+		}
+
+		saved, err := SaveSigs(pos, sigs)
+		count += saved
+		if err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+// SaveSigs saves the confirmation signatures of the output at the position in
+// the order of the inputs of the transaction that created it. Empty signatures
+// and signatures that are already stored are skipped and a conflicting
+// signature for an input returns an error. The number of signatures saved is
+// returned along with any error.
+func SaveSigs(position plasma.Position, sigs [][]byte) (int, error) {
+	existing, _ := GetSig(position)
+
+	count := 0
+	for i, sig := range sigs {
+		if len(sig) == 0 {
+			continue
+		}
+		if len(existing) >= 65*(i+1) && bytes.Equal(existing[65*i:65*(i+1)], sig) {
+			continue
+		}
+		if err := SaveSig(position, sig, i, len(sigs)); err != nil {
+			return count, fmt.Errorf("failed to import signature %d of %s: %s", i, position, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// LegacySigs are the confirmation signatures stored by earlier versions under
+// a key made of the block number followed by the utf8 encoding of the tx index.
+// Keys do not always map back to a single transaction, so every transaction
+// the key may refer to is a candidate.
+type LegacySigs struct {
+	Key        []byte
+	Candidates []plasma.Position // position of the first output of each candidate transaction
+	Signatures [][]byte          // in the order they were stored, which is not necessarily input order
+}
+
+// ListLegacySigs returns every entry stored by earlier versions in
+// data/signatures.ldb. No entries are returned if the database does not exist.
+func ListLegacySigs() ([]LegacySigs, error) {
+	dir := getDir(legacySignatureDir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	db, err := leveldb.OpenFile(dir, &opt.Options{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open db for legacy signatures: %s", err)
+	}
+	defer db.Close()
+
+	var list []LegacySigs
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		value := iter.Value()
+		sigs := make([][]byte, len(value)/65)
+		for i := range sigs {
+			sigs[i] = append([]byte{}, value[65*i:65*(i+1)]...)
+		}
+
+		key := append([]byte{}, iter.Key()...)
+		list = append(list, LegacySigs{key, legacySigKeyPositions(key), sigs})
+	}
+
+	return list, iter.Error()
+}
+
+// Match orders the signatures by the inputs of the transaction with the given
+// confirmation hash and input owners. False is returned if any signature was
+// not generated over the confirmation hash by an owner of an input.
+func (l LegacySigs) Match(confirmationHash []byte, owners []ethcmn.Address) ([][]byte, bool) {
+	if len(l.Signatures) == 0 || len(l.Signatures) > len(owners) {
+		return nil, false
+	}
+
+	hash := utils.ToEthSignedMessageHash(confirmationHash)
+	ordered := make([][]byte, len(owners))
+	for _, sig := range l.Signatures {
+		pubKey, err := crypto.SigToPub(hash, sig)
+		if err != nil {
+			return nil, false
+		}
+
+		signer := crypto.PubkeyToAddress(*pubKey)
+		matched := false
+		for i, owner := range owners {
+			if ordered[i] == nil && bytes.Equal(signer[:], owner[:]) {
+				ordered[i], matched = sig, true
+				break
+			}
+		}
+		if !matched {
+			return nil, false
+		}
+	}
+
+	return ordered, true
+}
+
+// legacySigKeyPositions returns the positions of the transactions the legacy key
+// may refer to. The tx index was utf8 encoded, which maps every surrogate tx
+// index to the encoding of the replacement character. Deposits, which were all
+// stored under the same key, are not candidates.
+func legacySigKeyPositions(key []byte) []plasma.Position {
+	r, size := utf8.DecodeLastRune(key)
+	if r == utf8.RuneError && size != 3 {
+		return nil
+	}
+	blockNum := new(big.Int).SetBytes(key[:len(key)-size])
+	if blockNum.Sign() == 0 {
+		return nil
+	}
+
+	txIndices := []rune{r}
+	if r == utf8.RuneError {
+		for surrogate := rune(0xd800); surrogate <= 0xdfff; surrogate++ {
+			txIndices = append(txIndices, surrogate)
+		}
+	}
+
+	var positions []plasma.Position
+	for _, txIndex := range txIndices {
+		positions = append(positions, plasma.NewPosition(blockNum, uint16(txIndex), 0, big.NewInt(0)))
+	}
+	return positions
+}
+
+// returns the key used for confirm signature mapping. Every field of the
+// position is fixed width so that keys are unique and iterate in order
 func getSigKey(pos plasma.Position) []byte {
-	return append(pos.BlockNum.Bytes(), []byte(string(pos.TxIndex))...)
+	key := make([]byte, sigKeyLength)
+	copy(key[:32], ethcmn.LeftPadBytes(pos.BlockNum.Bytes(), 32))
+	binary.BigEndian.PutUint16(key[32:34], pos.TxIndex)
+	key[34] = pos.OutputIndex
+	copy(key[35:], ethcmn.LeftPadBytes(pos.DepositNonce.Bytes(), 32))
+	return key
+}
+
+// inverse of getSigKey
+func sigKeyPosition(key []byte) (plasma.Position, error) {
+	if len(key) != sigKeyLength {
+		return plasma.Position{}, fmt.Errorf("corrupted signature db: key of length %d", len(key))
+	}
+
+	return plasma.NewPosition(
+		new(big.Int).SetBytes(key[:32]),
+		binary.BigEndian.Uint16(key[32:34]),
+		key[34],
+		new(big.Int).SetBytes(key[35:]),
+	), nil
 }
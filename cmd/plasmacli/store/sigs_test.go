@@ -1,11 +1,12 @@
 package store
 
 import (
-	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/flags"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
 	"math/big"
 	"os"
 	"testing"
@@ -14,13 +15,12 @@ import (
 func TestSavSig(t *testing.T) {
 	// setup testing env
 	os.Mkdir("testing", os.ModePerm)
-	viper.Set(flags.Home, "./testing")
+	InitKeystore("./testing")
 
 	// cleanup
 	defer func() {
-		viper.Reset()
+		CloseSigDB()
 		os.RemoveAll("testing")
-
 	}()
 
 	cases := [][]int64{
@@ -44,7 +44,7 @@ func TestSavSig(t *testing.T) {
 		_, err = GetSig(pos)
 		require.Errorf(t, err, "case %d: did not error when getting non existent signature for position %s", i, pos)
 
-		err = SaveSig(pos, expected, true)
+		err = SaveSig(pos, expected, 0, 1)
 		require.NoError(t, err, "case %d: failed to save signature for position %s", i, pos)
 
 		actual, err := GetSig(pos)
@@ -52,13 +52,11 @@ func TestSavSig(t *testing.T) {
 		require.Equal(t, expected, actual, "case %d: actual signature was not equal to expected signature for position %s", i, pos)
 
 		if !pos.IsDeposit() {
-			// changing output index should not effect
-			// retrieval of signature
+			// signatures are stored for the output
+			// at the full position
 			pos.OutputIndex = uint8(1)
-			actual, err = GetSig(pos)
-
-			require.NoError(t, err, "case %d: failed when getting signature for position %s", i, pos)
-			require.Equal(t, expected, actual, "case %d: actual signature was not equal to expected signature for position %s", i, pos)
+			_, err = GetSig(pos)
+			require.Errorf(t, err, "case %d: retrieved signature for a different output index %s", i, pos)
 		}
 	}
 }
@@ -67,13 +65,12 @@ func TestSavSig(t *testing.T) {
 func TestBadSigs(t *testing.T) {
 	// setup testing env
 	os.Mkdir("testing", os.ModePerm)
-	viper.Set(flags.Home, "./testing")
+	InitKeystore("./testing")
 
 	// cleanup
 	defer func() {
-		viper.Reset()
+		CloseSigDB()
 		os.RemoveAll("testing")
-
 	}()
 
 	key, _ := crypto.GenerateKey()
@@ -82,7 +79,7 @@ func TestBadSigs(t *testing.T) {
 	sig, _ := crypto.Sign(txHash, key)
 	pos := plasma.NewPosition(big.NewInt(10), uint16(5), uint8(0), big.NewInt(0))
 
-	err := SaveSig(pos, sig[:60], true)
+	err := SaveSig(pos, sig[:60], 0, 1)
 	require.Error(t, err, "did not reject a signature with a length not equal to 65")
 }
 
@@ -90,13 +87,12 @@ func TestBadSigs(t *testing.T) {
 func TestMultiConfirmSig(t *testing.T) {
 	// setup testing env
 	os.Mkdir("testing", os.ModePerm)
-	viper.Set(flags.Home, "./testing")
+	InitKeystore("./testing")
 
 	// cleanup
 	defer func() {
-		viper.Reset()
+		CloseSigDB()
 		os.RemoveAll("testing")
-
 	}()
 
 	key, _ := crypto.GenerateKey()
@@ -109,14 +105,193 @@ func TestMultiConfirmSig(t *testing.T) {
 	pos := plasma.NewPosition(big.NewInt(1000), uint16(256), uint8(0), big.NewInt(0))
 
 	// save sig1 first
-	err := SaveSig(pos, sig1, false)
+	err := SaveSig(pos, sig1, 1, 2)
 	require.NoError(t, err, "failed to save first confirm signature")
 
-	// prepend sig0
-	err = SaveSig(pos, sig0, true)
+	// sig0 is placed before sig1
+	err = SaveSig(pos, sig0, 0, 2)
 	require.NoError(t, err, "failed to save second confirm signature")
 
 	sigs, err := GetSig(pos)
 	require.NoError(t, err, "failed to retrieve confirm signatures")
 	require.Equal(t, append(sig0, sig1...), sigs, "retrieved signatures do not match expected signatures")
+
+	err = SaveSig(pos, sig0, 0, 2)
+	require.Error(t, err, "overwrote an existing confirm signature")
+}
+
+// positions that collided under the previous key format
+func TestSigKeyCollisions(t *testing.T) {
+	// setup testing env
+	os.Mkdir("testing", os.ModePerm)
+	InitKeystore("./testing")
+
+	// cleanup
+	defer func() {
+		CloseSigDB()
+		os.RemoveAll("testing")
+	}()
+
+	positions := []plasma.Position{
+		plasma.NewPosition(big.NewInt(1), 0, 0, nil),
+		plasma.NewPosition(big.NewInt(1), 0, 1, nil),
+		plasma.NewPosition(big.NewInt(1), 1, 0, nil),
+		plasma.NewPosition(big.NewInt(0x101), 0, 0, nil),
+		plasma.NewPosition(big.NewInt(1), 0x100, 0, nil),
+		plasma.NewPosition(nil, 0, 0, big.NewInt(1)),
+		plasma.NewPosition(nil, 0, 0, big.NewInt(2)),
+		plasma.NewPosition(big.NewInt(1), 1<<16-1, 0, nil),
+	}
+
+	key, _ := crypto.GenerateKey()
+	for i, pos := range positions {
+		require.Len(t, getSigKey(pos), sigKeyLength)
+		decoded, err := sigKeyPosition(getSigKey(pos))
+		require.NoError(t, err)
+		require.Equal(t, pos.String(), decoded.String(), "case %d: key did not decode to the position", i)
+
+		sig, _ := crypto.Sign(crypto.Keccak256([]byte(pos.String())), key)
+		require.NoError(t, SaveSig(pos, sig, 0, 1), "case %d: signature collided with another position", i)
+	}
+
+	list, err := ListSigs()
+	require.NoError(t, err)
+	require.Len(t, list, len(positions))
+	for _, stored := range list {
+		sig, _ := crypto.Sign(crypto.Keccak256([]byte(stored.Position.String())), key)
+		require.Equal(t, [][]byte{sig}, stored.Signatures, "signature of %s overwritten", stored.Position)
+		require.True(t, stored.Complete())
+	}
+
+	require.NoError(t, DeleteSig(positions[0]))
+	_, err = GetSig(positions[0])
+	require.Error(t, err, "retrieved a deleted signature")
+	_, err = GetSig(positions[1])
+	require.NoError(t, err, "deleted the signature of a different output")
+}
+
+// export the signatures of one home and import them into another
+func TestExportImportSigs(t *testing.T) {
+	// setup testing env
+	os.Mkdir("testing", os.ModePerm)
+	InitKeystore("./testing")
+
+	// cleanup
+	defer func() {
+		CloseSigDB()
+		os.RemoveAll("testing")
+		os.RemoveAll("testing2")
+	}()
+
+	key, _ := crypto.GenerateKey()
+	sig0, _ := crypto.Sign(crypto.Keccak256([]byte("hash one")), key)
+	sig1, _ := crypto.Sign(crypto.Keccak256([]byte("hash two")), key)
+	pos0 := plasma.NewPosition(big.NewInt(5), 2, 1, nil)
+	pos1 := plasma.NewPosition(big.NewInt(7), 0, 0, nil)
+
+	require.NoError(t, SaveSig(pos0, sig0, 0, 2))
+	require.NoError(t, SaveSig(pos0, sig1, 1, 2))
+	require.NoError(t, SaveSig(pos1, sig1, 1, 2))
+
+	bz, err := ExportSigs()
+	require.NoError(t, err)
+
+	// importing into the same store skips existing signatures
+	count, err := ImportSigs(bz)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	os.Mkdir("testing2", os.ModePerm)
+	home = "./testing2"
+	list, err := ListSigs()
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	count, err = ImportSigs(bz)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	list, err = ListSigs()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, pos0.String(), list[0].Position.String())
+	require.Equal(t, [][]byte{sig0, sig1}, list[0].Signatures)
+	require.Equal(t, pos1.String(), list[1].Position.String())
+	require.Equal(t, [][]byte{nil, sig1}, list[1].Signatures)
+	require.False(t, list[1].Complete())
+
+	// conflicting signatures are not overwritten
+	require.NoError(t, DeleteSig(pos1))
+	require.NoError(t, SaveSig(pos1, sig0, 1, 2))
+	_, err = ImportSigs(bz)
+	require.Error(t, err, "overwrote a conflicting signature")
+
+	_, err = ImportSigs([]byte(`[{"position": "(1.0.0.0)", "signatures": ["0xzz"]}]`))
+	require.Error(t, err, "imported a malformed signature")
+}
+
+// signatures stored by earlier versions are matched to the transactions their keys may refer to
+func TestLegacySigs(t *testing.T) {
+	// setup testing env
+	os.Mkdir("testing", os.ModePerm)
+	InitKeystore("./testing")
+
+	// cleanup
+	defer func() {
+		CloseSigDB()
+		os.RemoveAll("testing")
+	}()
+
+	list, err := ListLegacySigs()
+	require.NoError(t, err, "failed without a legacy database")
+	require.Empty(t, list)
+
+	key0, _ := crypto.GenerateKey()
+	key1, _ := crypto.GenerateKey()
+	owners := []ethcmn.Address{crypto.PubkeyToAddress(key0.PublicKey), crypto.PubkeyToAddress(key1.PublicKey)}
+	confirmationHash := crypto.Keccak256([]byte("confirmation hash"))
+	sig0, _ := crypto.Sign(utils.ToEthSignedMessageHash(confirmationHash), key0)
+	sig1, _ := crypto.Sign(utils.ToEthSignedMessageHash(confirmationHash), key1)
+
+	// the signatures of the second input were stored first. Deposits and
+	// surrogate tx indices did not have a key of their own
+	db, err := leveldb.OpenFile(getDir(legacySignatureDir), nil)
+	require.NoError(t, err)
+	legacyKey := append(big.NewInt(1).Bytes(), []byte(string(rune(256)))...)
+	surrogateKey := append(big.NewInt(2).Bytes(), []byte(string(rune(0xd800)))...)
+	require.NoError(t, db.Put(legacyKey, append(append([]byte{}, sig1...), sig0...), nil))
+	require.NoError(t, db.Put(surrogateKey, sig0, nil))
+	require.NoError(t, db.Put([]byte{0}, sig0, nil))
+	require.NoError(t, db.Close())
+
+	list, err = ListLegacySigs()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	require.Empty(t, list[0].Candidates, "deposit key mapped to a transaction")
+	require.Equal(t, []plasma.Position{plasma.NewPosition(big.NewInt(1), 256, 0, nil)}, list[1].Candidates)
+	require.Len(t, list[2].Candidates, 0x801, "every surrogate tx index is not a candidate")
+	require.Equal(t, plasma.NewPosition(big.NewInt(2), 0xfffd, 0, nil), list[2].Candidates[0])
+	require.Equal(t, plasma.NewPosition(big.NewInt(2), 0xd800, 0, nil), list[2].Candidates[1])
+	list = list[1:]
+
+	sigs, ok := list[0].Match(confirmationHash, owners)
+	require.True(t, ok, "signatures not matched to the owners of the inputs")
+	require.Equal(t, [][]byte{sig0, sig1}, sigs, "signatures not ordered by input")
+
+	_, ok = list[0].Match(crypto.Keccak256([]byte("another hash")), owners)
+	require.False(t, ok, "matched signatures over a different confirmation hash")
+	_, ok = list[0].Match(confirmationHash, owners[:1])
+	require.False(t, ok, "matched more signatures than inputs")
+
+	pos := list[0].Candidates[0]
+	count, err := SaveSigs(pos, sigs)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	stored, err := GetSig(pos)
+	require.NoError(t, err)
+	require.Equal(t, append(append([]byte{}, sig0...), sig1...), stored)
+
+	count, err = SaveSigs(pos, sigs)
+	require.NoError(t, err, "failed on signatures that are already stored")
+	require.Equal(t, 0, count)
 }
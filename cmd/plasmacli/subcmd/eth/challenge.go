@@ -6,10 +6,8 @@ import (
 	ks "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/cosmos/cosmos-sdk/client/context"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	tm "github.com/tendermint/tendermint/rpc/core/types"
 	"math/big"
 	"strconv"
 )
@@ -56,36 +54,27 @@ Usage:
 			return fmt.Errorf("failed to parse gas limit: %s", err)
 		}
 
-		key, err := ks.GetKey(args[2])
+		signer, err := ks.GetSigner(args[2])
 		if err != nil {
-			return fmt.Errorf("failed to retrieve account key: %s", err)
+			return fmt.Errorf("failed to retrieve account: %s", err)
+		}
+		if err := signer.Unlock(); err != nil {
+			return fmt.Errorf("failed to unlock account: %s", err)
 		}
 
 		cmd.SilenceUsage = true
 
-		// bind key
-		auth := bind.NewKeyedTransactor(key)
-		transactOpts := &bind.TransactOpts{
-			From:     auth.From,
-			Signer:   auth.Signer,
-			GasLimit: gasLimit,
-		}
+		transactOpts := ks.TransactOpts(signer, gasLimit)
 
 		var txBytes, proof, confirmSignatures []byte
 		if viper.GetBool(useNodeF) {
-			var result *tm.ResultTx
 			ctx := context.NewCLIContext()
-			result, confirmSignatures, err = getProof(ctx, challengingPos)
+			result, sigs, err := getProof(ctx, challengingPos)
 			if err != nil {
 				return fmt.Errorf("failed to retrieve exit information: %s", err)
 			}
 
-			txBytes = result.Tx
-
-			// flatten proof
-			for _, aunt := range result.Proof.Proof.Aunts {
-				proof = append(proof, aunt...)
-			}
+			txBytes, proof, confirmSignatures = result.TxBytes, result.Proof, sigs
 		}
 
 		if len(confirmSignatures) == 0 {
@@ -3,8 +3,6 @@ package eth
 import (
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"math/big"
@@ -28,10 +26,13 @@ Usage:
 	RunE: func(cmd *cobra.Command, args []string) error {
 		viper.BindPFlags(cmd.Flags())
 
-		key, err := store.GetKey(args[1])
+		signer, err := store.GetSigner(args[1])
 		if err != nil {
 			return fmt.Errorf("failed to retrieve account: %s", err)
 		}
+		if err := signer.Unlock(); err != nil {
+			return fmt.Errorf("failed to unlock account: %s", err)
+		}
 
 		amt, err := strconv.ParseInt(args[0], 10, 64)
 		if err != nil {
@@ -45,16 +46,10 @@ Usage:
 
 		cmd.SilenceUsage = true
 
-		// bind key, generate transact opts
-		auth := bind.NewKeyedTransactor(key)
-		transactOpts := &bind.TransactOpts{
-			From:     auth.From,
-			Signer:   auth.Signer,
-			GasLimit: gasLimit,
-			Value:    big.NewInt(amt),
-		}
+		transactOpts := store.TransactOpts(signer, gasLimit)
+		transactOpts.Value = big.NewInt(amt)
 
-		tx, err := plasmaContract.Deposit(transactOpts, crypto.PubkeyToAddress(key.PublicKey))
+		tx, err := plasmaContract.Deposit(transactOpts, signer.Address())
 		if err != nil {
 			return fmt.Errorf("failed to deposit: %s", err)
 		}
@@ -6,12 +6,10 @@ import (
 	ks "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/cosmos/cosmos-sdk/client/context"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	eth "github.com/ethereum/go-ethereum/core/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	tm "github.com/tendermint/tendermint/rpc/core/types"
 	"math/big"
 	"strconv"
 )
@@ -65,21 +63,18 @@ Transaction Exit Usage:
 		}
 
 		// retrieve account key
-		key, err := ks.GetKey(args[0])
+		signer, err := ks.GetSigner(args[0])
 		if err != nil {
-			return fmt.Errorf("failed to retrieve account key: %s", err)
+			return fmt.Errorf("failed to retrieve account: %s", err)
+		}
+		if err := signer.Unlock(); err != nil {
+			return fmt.Errorf("failed to unlock account: %s", err)
 		}
 
 		cmd.SilenceUsage = true
 
-		// bind key, generate transact opts
-		auth := bind.NewKeyedTransactor(key)
-		transactOpts := &bind.TransactOpts{
-			From:     auth.From,
-			Signer:   auth.Signer,
-			GasLimit: gasLimit,
-			Value:    big.NewInt(minExitBond), // minExitBond
-		}
+		transactOpts := ks.TransactOpts(signer, gasLimit)
+		transactOpts.Value = big.NewInt(minExitBond)
 
 		// send fee exit
 		if position.IsFee() {
@@ -104,19 +99,13 @@ Transaction Exit Usage:
 		// retrieve information necessary for transaction exit
 		var txBytes, proof, confirmSignatures []byte
 		if viper.GetBool(useNodeF) { // query full node
-			var result *tm.ResultTx
 			ctx := context.NewCLIContext()
-			result, confirmSignatures, err = getProof(ctx, position)
+			result, sigs, err := getProof(ctx, position)
 			if err != nil {
 				return fmt.Errorf("failed to retrieve exit information: %s", err)
 			}
 
-			txBytes = result.Tx
-
-			// flatten proof
-			for _, aunt := range result.Proof.Proof.Aunts {
-				proof = append(proof, aunt...)
-			}
+			txBytes, proof, confirmSignatures = result.TxBytes, result.Proof, sigs
 		}
 
 		if len(confirmSignatures) == 0 {
@@ -159,7 +148,7 @@ func parseProof(txBytes, proof, confirmSignatures []byte) ([]byte, []byte, []byt
 
 	// return error if information is missing
 	if len(txBytes) != 811 {
-		return txBytes, proof, confirmSignatures, fmt.Errorf("please provide txBytes with a length of 811 bytes. Current length: %d", len(txBytes))
+		return txBytes, proof, confirmSignatures, fmt.Errorf("please provide txBytes with a length of 811 bytes. Only transactions with at most 2 inputs and 2 outputs can be exited. Current length: %d", len(txBytes))
 	}
 
 	if len(proof)%32 != 0 {
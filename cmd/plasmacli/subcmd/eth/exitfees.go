@@ -0,0 +1,103 @@
+package eth
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
+	ks "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// ExitFeesCmd returns the eth exit-fees command
+func ExitFeesCmd() *cobra.Command {
+	config.AddPersistentTMFlags(exitFeesCmd)
+	exitFeesCmd.Flags().String(feeF, "0", "fee committed in an unfinalized spend of each fee output")
+	exitFeesCmd.Flags().StringP(gasLimitF, "g", "300000", "gas limit for each ethereum transaction")
+	exitFeesCmd.Flags().Int(limitF, 0, "maximum number of fee outputs to exit. 0 exits all of them")
+	return exitFeesCmd
+}
+
+var exitFeesCmd = &cobra.Command{
+	Use:   "exit-fees <account>",
+	Short: "Start exits for the unspent fee outputs of the account",
+	Long: `Starts a fee exit for each unspent fee output owned by the account, retrieved from the connected full node.
+Fee outputs that have already been exited are skipped. Each exit is a separate rootchain transaction posting the exit bond.
+The output of a fee sweep is not a fee output and is not exited by this command. It is exited as a transaction output with
+"plasmacli eth exit <account> <position> --trust-node" once the confirm signatures of the sweep have been created with
+"plasmacli tx sign <account> --owner <account> --position <position>".
+
+Usage:
+	plasmacli eth exit-fees <account>
+	plasmacli eth exit-fees <account> --limit 10 --gas-limit 200000`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+		ctx := context.NewCLIContext()
+
+		fee, err := strconv.ParseInt(viper.GetString(feeF), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse fee: %s", err)
+		}
+
+		gasLimit, err := strconv.ParseUint(viper.GetString(gasLimitF), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse gas limit: %s", err)
+		}
+
+		limit := viper.GetInt(limitF)
+		if limit < 0 {
+			return fmt.Errorf("limit must be non-negative")
+		}
+
+		// retrieve account key
+		signer, err := ks.GetSigner(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to retrieve account: %s", err)
+		}
+		if err := signer.Unlock(); err != nil {
+			return fmt.Errorf("failed to unlock account: %s", err)
+		}
+
+		cmd.SilenceUsage = true
+
+		fees, err := client.FeeOutputs(ctx, signer.Address())
+		if err != nil {
+			return err
+		}
+
+		exits := 0
+		for _, output := range fees.Fees {
+			if limit != 0 && exits == limit {
+				break
+			}
+
+			exited, err := plasmaContract.HasTxExited(nil, time.Time{}, output.Position)
+			if err != nil {
+				return fmt.Errorf("failed to check the exit of %s: %s", output.Position, err)
+			}
+			if exited {
+				continue
+			}
+
+			transactOpts := ks.TransactOpts(signer, gasLimit)
+			transactOpts.Value = big.NewInt(minExitBond)
+			tx, err := plasmaContract.StartFeeExit(transactOpts, output.Position.BlockNum, big.NewInt(fee))
+			if err != nil {
+				return fmt.Errorf("failed to start fee exit for %s: %s", output.Position, err)
+			}
+			fmt.Printf("Sent fee exit transaction for %s\nTransaction Hash: 0x%x\n", output.Position, tx.Hash())
+			exits++
+		}
+
+		if exits == 0 {
+			fmt.Println("no fee outputs to exit")
+		}
+
+		return nil
+	},
+}
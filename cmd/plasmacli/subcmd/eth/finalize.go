@@ -3,7 +3,6 @@ package eth
 import (
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	eth "github.com/ethereum/go-ethereum/core/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -29,10 +28,13 @@ Usage:
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		viper.BindPFlags(cmd.Flags())
 
-		key, err := store.GetKey(args[0])
+		signer, err := store.GetSigner(args[0])
 		if err != nil {
 			return fmt.Errorf("failed to retrieve account: %s", err)
 		}
+		if err := signer.Unlock(); err != nil {
+			return fmt.Errorf("failed to unlock account: %s", err)
+		}
 
 		gasLimit, err := strconv.ParseUint(viper.GetString(gasLimitF), 10, 64)
 		if err != nil {
@@ -41,13 +43,7 @@ Usage:
 
 		cmd.SilenceUsage = true
 
-		// bind key, generate transact opts
-		auth := bind.NewKeyedTransactor(key)
-		transactOpts := &bind.TransactOpts{
-			From:     auth.From,
-			Signer:   auth.Signer,
-			GasLimit: gasLimit,
-		}
+		transactOpts := store.TransactOpts(signer, gasLimit)
 
 		var tx *eth.Transaction
 		if viper.GetBool(depositsF) {
@@ -1,15 +1,15 @@
 package eth
 
 import (
-	"encoding/json"
+	"encoding/hex"
 	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
 	"github.com/cosmos/cosmos-sdk/client/context"
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/spf13/cobra"
-	tm "github.com/tendermint/tendermint/rpc/core/types"
 )
 
 // ProveCmd returns the eth prove command
@@ -20,16 +20,24 @@ func ProveCmd() *cobra.Command {
 
 var proveCmd = &cobra.Command{
 	Use:   "prove <position>",
-	Short: "Prove transaction inclusion: prove <account> <position>",
+	Short: "Prove transaction inclusion: prove <account> <position/txhash>",
 	Args:  cobra.ExactArgs(2),
-	Long:  "Returns proof for transaction inclusion. Use to exit transactions in the smart contract",
+	Long:  "Returns proof for transaction inclusion. Use to exit transactions in the smart contract. The transaction is identified by the position of one of its outputs or by its hash",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.NewCLIContext()
 
-		// parse position
+		// parse position or look up the position of the tx hash
 		position, err := plasma.FromPositionString(args[1])
 		if err != nil {
-			return err
+			hash, hashErr := hex.DecodeString(utils.RemoveHexPrefix(args[1]))
+			if hashErr != nil || len(hash) != 32 {
+				return fmt.Errorf("expected a position or a 32-byte tx hash in hexadecimal format: %s", err)
+			}
+
+			cmd.SilenceUsage = true
+			if position, err = client.TxPosition(ctx, hash); err != nil {
+				return err
+			}
 		}
 
 		cmd.SilenceUsage = true
@@ -40,10 +48,10 @@ var proveCmd = &cobra.Command{
 		}
 
 		// print meta data
-		fmt.Printf("Roothash: 0x%x\n", result.Proof.RootHash)
-		fmt.Printf("Total: %d\n", result.Proof.Proof.Total)
-		fmt.Printf("LeafHash: 0x%x\n", result.Proof.Proof.LeafHash)
-		fmt.Printf("TxBytes: 0x%x\n", []byte(result.Tx))
+		fmt.Printf("Roothash: 0x%x\n", result.Block.Header)
+		fmt.Printf("Total: %d\n", result.Total)
+		fmt.Printf("Index: %d\n", result.Index)
+		fmt.Printf("TxBytes: 0x%x\n", result.TxBytes)
 
 		switch len(sigs) {
 		case 65:
@@ -52,62 +60,41 @@ var proveCmd = &cobra.Command{
 			fmt.Printf("Confirmation Signatures: 0x%x, 0x%x\n", sigs[:65], sigs[65:])
 		}
 
-		// flatten aunts
-		var proof []byte
-		for _, aunt := range result.Proof.Proof.Aunts {
-			proof = append(proof, aunt...)
-		}
-
-		if len(proof) == 0 {
-			if result.Proof.Proof.Total == 1 {
+		if len(result.Proof) == 0 {
+			if result.Total == 1 {
 				fmt.Println("No proof required since this was the only transaction in the block")
 			} else {
 				fmt.Printf("Proof: nil\n")
 			}
 		} else {
-			fmt.Printf("Proof: 0x%x\n", proof)
+			fmt.Printf("Proof: 0x%x\n", result.Proof)
 		}
 
 		return nil
 	},
 }
 
-// Returns transaction results for given position
+// Returns the transaction and its inclusion proof for the given position
+// along with the confirmation signatures of the spending transaction.
 // Trusts connected full node
-func getProof(ctx context.CLIContext, position plasma.Position) (*tm.ResultTx, []byte, error) {
-	key := store.GetOutputKey(position)
-	hash, err := ctx.QueryStore(key, store.DataStoreName)
+func getProof(ctx context.CLIContext, position plasma.Position) (store.TxProof, []byte, error) {
+	proof, err := client.TxProof(ctx, position)
 	if err != nil {
-		return &tm.ResultTx{}, nil, err
+		return store.TxProof{}, nil, err
 	}
 
-	txKey := store.GetTxKey(hash)
-	txBytes, err := ctx.QueryStore(txKey, store.DataStoreName)
-
-	var tx store.Transaction
-	if err := rlp.DecodeBytes(txBytes, &tx); err != nil {
-		return &tm.ResultTx{}, nil, fmt.Errorf("transaction decoding failed: %s", err.Error())
-	}
-
-	// query tm node for information about this tx
-	result, err := ctx.Client.Tx(tx.Transaction.MerkleHash(), true)
+	output, err := client.TxOutput(ctx, position)
 	if err != nil {
-		return &tm.ResultTx{}, nil, err
+		return store.TxProof{}, nil, err
 	}
 
 	// Look for confirmation signatures
-	// Ignore error if no confirm sig currently exists in store
+	// Ignore if the output has not been spent yet
 	var sigs []byte
-	if len(tx.SpenderTxs[position.OutputIndex]) > 0 {
-		queryPath := fmt.Sprintf("custom/data/tx/%s", tx.SpenderTxs[position.OutputIndex])
-		data, err := ctx.Query(queryPath, nil)
+	if len(output.SpenderTx) > 0 {
+		spenderTx, err := client.Tx(ctx, output.SpenderTx)
 		if err != nil {
-			return &tm.ResultTx{}, nil, err
-		}
-
-		var spenderTx store.Transaction
-		if err := json.Unmarshal(data, &spenderTx); err != nil {
-			return &tm.ResultTx{}, nil, fmt.Errorf("unmarshaling json query response: %s", err)
+			return store.TxProof{}, nil, err
 		}
 		for _, input := range spenderTx.Transaction.Inputs {
 			if input.Position.String() == position.String() {
@@ -118,5 +105,5 @@ func getProof(ctx context.CLIContext, position plasma.Position) (*tm.ResultTx, [
 		}
 	}
 
-	return result, sigs, nil
+	return proof, sigs, nil
 }
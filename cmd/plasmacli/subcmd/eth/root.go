@@ -8,6 +8,7 @@ import (
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/spf13/cobra"
+	"time"
 )
 
 const (
@@ -37,6 +38,7 @@ func RootCmd() *cobra.Command {
 		ProveCmd(),
 		ChallengeCmd(),
 		ExitCmd(),
+		ExitFeesCmd(),
 		FinalizeCmd(),
 		DepositCmd(),
 		WithdrawCmd(),
@@ -71,5 +73,5 @@ func HasTxExited(pos plasma.Position) (bool, error) {
 		return false, err
 	}
 
-	return conn.HasTxExited(nil, pos)
+	return conn.HasTxExited(nil, time.Time{}, pos)
 }
@@ -3,7 +3,6 @@ package eth
 import (
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"strconv"
@@ -26,10 +25,13 @@ Usage:
 	RunE: func(cmd *cobra.Command, args []string) error {
 		viper.BindPFlags(cmd.Flags())
 
-		key, err := store.GetKey(args[0])
+		signer, err := store.GetSigner(args[0])
 		if err != nil {
 			return fmt.Errorf("failed to retrieve account: %s", err)
 		}
+		if err := signer.Unlock(); err != nil {
+			return fmt.Errorf("failed to unlock account: %s", err)
+		}
 
 		gasLimit, err := strconv.ParseUint(viper.GetString(gasLimitF), 10, 64)
 		if err != nil {
@@ -38,13 +40,7 @@ Usage:
 
 		cmd.SilenceUsage = true
 
-		// bind key, generate transact opts
-		auth := bind.NewKeyedTransactor(key)
-		transactOpts := &bind.TransactOpts{
-			From:     auth.From,
-			Signer:   auth.Signer,
-			GasLimit: gasLimit,
-		}
+		transactOpts := store.TransactOpts(signer, gasLimit)
 
 		tx, err := plasmaContract.Withdraw(transactOpts)
 		if err != nil {
@@ -3,22 +3,49 @@ package keys
 import (
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	externalF = "external"
 )
 
 // AddCmd returns the keys add command
 func AddCmd() *cobra.Command {
+	addCmd.Flags().String(externalF, "", "name an address held by the external signer instead of creating a key")
 	return addCmd
 }
 
 var addCmd = &cobra.Command{
 	Use:   "add <name>",
 	Short: "Create a new account",
-	Long:  `Add an encrypted account to your local keystore.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Add an encrypted account to your local keystore.
+Accounts held by an external signer are added by address and no key is created.
+
+Usage:
+	plasmacli keys add <name>
+	plasmacli keys add <name> --external <address>`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
 		name := args[0]
 
+		if external := viper.GetString(externalF); external != "" {
+			if !ethcmn.IsHexAddress(external) {
+				return fmt.Errorf("invalid address provided. please use hex format")
+			}
+
+			address := ethcmn.HexToAddress(external)
+			if err := store.AddExternalAccount(name, address); err != nil {
+				return err
+			}
+
+			fmt.Printf("NAME: %s\tADDRESS: 0x%x\n", name, address)
+			return nil
+		}
+
 		address, err := store.AddAccount(name)
 		if err != nil {
 			return err
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	ks "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+const saveF = "save"
+
+// ConfirmSigsCmd returns the query confirm signatures command
+func ConfirmSigsCmd() *cobra.Command {
+	confirmSigsCmd.Flags().Bool(saveF, false, "save the posted confirm signatures locally")
+	return confirmSigsCmd
+}
+
+var confirmSigsCmd = &cobra.Command{
+	Use:   "confirmsigs <position>",
+	Short: "Query the confirm signatures posted for an output",
+	Long: `Query the confirm signatures the senders of a transaction posted to the mailbox of the full node.
+The full node must have the confirm signature mailbox enabled. With the save flag, the signatures
+are stored locally and included when the output is spent.
+
+Usage:
+	plasmacli query confirmsigs "(blknum.txindex.oindex.depositnonce)"
+	plasmacli query confirmsigs "(blknum.txindex.oindex.depositnonce)" --save`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.NewCLIContext()
+		viper.BindPFlags(cmd.Flags())
+
+		pos, err := plasma.FromPositionString(strings.TrimSpace(args[0]))
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		sigs, err := client.ConfirmSigs(ctx, pos)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Position: %s\nTxHash: 0x%x\n", sigs.Position, sigs.TxHash)
+		for i, sig := range sigs.Signatures {
+			if len(sig) == 0 {
+				fmt.Printf("Input %d: not posted\n", i)
+				continue
+			}
+			fmt.Printf("Input %d: 0x%x\n", i, sig)
+		}
+		fmt.Printf("Complete: %t\n", sigs.Complete)
+
+		if !viper.GetBool(saveF) {
+			return nil
+		}
+
+		local, _ := ks.GetSig(pos)
+		for i, sig := range sigs.Signatures {
+			// skip signatures that were not posted or are already saved
+			if len(sig) == 0 || len(local) >= 65*(i+1) && string(local[65*i:65*(i+1)]) == string(sig) {
+				continue
+			}
+			if err := ks.SaveSig(pos, sig, i, len(sigs.Signatures)); err != nil {
+				return fmt.Errorf("failed to save confirm signature for input %d: %s", i, err)
+			}
+		}
+		fmt.Println("Confirm signatures saved")
+		return nil
+	},
+}
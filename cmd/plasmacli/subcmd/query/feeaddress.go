@@ -0,0 +1,34 @@
+package query
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+)
+
+// FeeAddressCmd returns the query fee address command
+func FeeAddressCmd() *cobra.Command {
+	return feeAddressCmd
+}
+
+var feeAddressCmd = &cobra.Command{
+	Use:   "fee-address",
+	Short: "Address fees are collected to",
+	Long: `Returns the address the fee outputs of each block are owned by along with the nonce of the next update.
+Fees are collected to the operator of the rootchain contract if no fee address was set in genesis.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.NewCLIContext()
+		cmd.SilenceUsage = true
+
+		feeAddress, err := client.FeeAddress(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Fee Address: %s\n", feeAddress.Address.Hex())
+		fmt.Printf("Nonce: %d\n", feeAddress.Nonce)
+		return nil
+	},
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	ks "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// FeesCmd returns the query fees command
+func FeesCmd() *cobra.Command {
+	return feesCmd
+}
+
+var feesCmd = &cobra.Command{
+	Use:   "fees <account/address>",
+	Short: "Unspent fee outputs collected by the operator",
+	Long: `List the unspent fee outputs owned by the address along with the output of its latest fee sweep.
+Fee outputs are consolidated with "plasmacli tx sweep-fees" and exited with "plasmacli eth exit-fees".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.NewCLIContext()
+		cmd.SilenceUsage = true
+
+		var (
+			addr ethcmn.Address
+			err  error
+		)
+
+		if !ethcmn.IsHexAddress(args[0]) {
+			if addr, err = ks.GetAccount(args[0]); err != nil {
+				return fmt.Errorf("failed local account retrieval: %s", err)
+			}
+		} else {
+			addr = ethcmn.HexToAddress(args[0])
+		}
+
+		fees, err := client.FeeOutputs(ctx, addr)
+		if err != nil {
+			return err
+		}
+
+		for _, fee := range fees.Fees {
+			fmt.Printf("Position: %s, Amount: %s\n", fee.Position, fee.Output.Amount)
+		}
+		if fees.Sweep != nil {
+			fmt.Printf("Sweep Position: %s, Amount: %s\n", fees.Sweep.Position, fees.Sweep.Output.Amount)
+		}
+
+		fmt.Printf("Fee Outputs: %d\n", len(fees.Fees))
+		fmt.Printf("Total: %s\n", fees.Total)
+		return nil
+	},
+}
@@ -0,0 +1,120 @@
+package query
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	ks "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"math/big"
+	"strconv"
+)
+
+const (
+	// flags
+	cursorF    = "cursor"
+	limitF     = "limit"
+	directionF = "direction"
+	spentF     = "spent"
+	fromBlockF = "from"
+	toBlockF   = "to"
+)
+
+// HistoryCmd returns the query history command
+func HistoryCmd() *cobra.Command {
+	historyCmd.Flags().String(cursorF, "", "cursor returned with the previous page")
+	historyCmd.Flags().Int(limitF, store.DefaultHistoryLimit, fmt.Sprintf("number of outputs in the page, at most %d", store.MaxHistoryLimit))
+	historyCmd.Flags().String(directionF, "", "only outputs that were received or sent")
+	historyCmd.Flags().String(spentF, "", "only spent (true) or unspent (false) outputs")
+	historyCmd.Flags().String(fromBlockF, "", "first plasma block of the history")
+	historyCmd.Flags().String(toBlockF, "", "last plasma block of the history")
+	return historyCmd
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history <account/address>",
+	Short: "Query the outputs received and sent by an address",
+	Long: `Query a page of the outputs received and sent by an address, most recent first.
+The cursor printed after the page retrieves the next page.
+
+Usage:
+	plasmacli query history <account> --limit 10
+	plasmacli query history <account> --direction sent --from 100 --to 200
+	plasmacli query history <account> --spent false --cursor 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.NewCLIContext()
+		viper.BindPFlags(cmd.Flags())
+		var (
+			addr ethcmn.Address
+			err  error
+		)
+
+		if !ethcmn.IsHexAddress(args[0]) {
+			if addr, err = ks.GetAccount(args[0]); err != nil {
+				return fmt.Errorf("failed local account retrieval: %s", err)
+			}
+		} else {
+			addr = ethcmn.HexToAddress(args[0])
+		}
+
+		params := store.HistoryParams{
+			Cursor:    viper.GetString(cursorF),
+			Limit:     viper.GetInt(limitF),
+			Direction: viper.GetString(directionF),
+		}
+		if spent := viper.GetString(spentF); spent != "" {
+			s, err := strconv.ParseBool(spent)
+			if err != nil {
+				return fmt.Errorf("spent must be true or false")
+			}
+			params.Spent = &s
+		}
+		if from := viper.GetString(fromBlockF); from != "" {
+			num, ok := new(big.Int).SetString(from, 10)
+			if !ok {
+				return fmt.Errorf("block number must be in decimal format")
+			}
+			params.FromBlock = num
+		}
+		if to := viper.GetString(toBlockF); to != "" {
+			num, ok := new(big.Int).SetString(to, 10)
+			if !ok {
+				return fmt.Errorf("block number must be in decimal format")
+			}
+			params.ToBlock = num
+		}
+		if err := params.ValidateBasic(); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		history, err := client.History(ctx, addr, params)
+		if err != nil {
+			return err
+		}
+
+		for _, output := range history.Outputs {
+			fmt.Printf("Position: %s, Amount: %s, Received In Block: %s\n", output.Position, output.Amount, output.CreatedAt)
+			if len(output.TxHash) > 0 {
+				fmt.Printf("Transaction Hash: 0x%x\n", output.TxHash)
+			}
+			if output.Spent {
+				fmt.Printf("Spent In Block: %s, Spender Hash: 0x%x\n", output.SpentAt, output.SpenderTx)
+			}
+			fmt.Println()
+		}
+
+		if len(history.Outputs) == 0 {
+			fmt.Println("no outputs on this page")
+		}
+		if history.NextCursor != "" {
+			fmt.Printf("Next Cursor: %s\n", history.NextCursor)
+		}
+
+		return nil
+	},
+}
@@ -12,6 +12,11 @@ func RootCmd() *cobra.Command {
 		BalanceCmd(),
 		BlockCmd(),
 		BlocksCmd(),
+		ConfirmSigsCmd(),
+		FeesCmd(),
+		FeeAddressCmd(),
+		ValidatorsCmd(),
+		HistoryCmd(),
 		InfoCmd(),
 		HeightCmd(),
 	)
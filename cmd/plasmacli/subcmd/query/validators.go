@@ -0,0 +1,49 @@
+package query
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+)
+
+// ValidatorsCmd returns the query validators command
+func ValidatorsCmd() *cobra.Command {
+	return validatorsCmd
+}
+
+var validatorsCmd = &cobra.Command{
+	Use:   "validators",
+	Short: "Validator set of the sidechain",
+	Long:  `Returns the consensus public key and voting power of each validator along with the nonce of the next validator update.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.NewCLIContext()
+		cmd.SilenceUsage = true
+
+		set, err := client.Validators(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i, validator := range set.Validators {
+			pubKey, err := cryptoAmino.PubKeyFromBytes(validator.ConsPubKey)
+			if err != nil {
+				return fmt.Errorf("amino: %s", err)
+			}
+
+			fmt.Printf("Validator %d:\n", i)
+			fmt.Printf("Address: %s\n", pubKey.Address())
+			if key, ok := pubKey.(ed25519.PubKeyEd25519); ok {
+				fmt.Printf("PubKey: %x\n", key[:])
+			} else {
+				fmt.Printf("PubKey: %x\n", pubKey.Bytes())
+			}
+			fmt.Printf("Power: %d\n", validator.Power)
+		}
+		fmt.Printf("Nonce: %d\n", set.Nonce)
+		return nil
+	},
+}
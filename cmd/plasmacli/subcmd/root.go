@@ -8,6 +8,7 @@ import (
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/subcmd/eth"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/subcmd/keys"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/subcmd/query"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/subcmd/sigs"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/subcmd/tx"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/spf13/cobra"
@@ -35,9 +36,11 @@ func RootCmd() *cobra.Command {
 		client.LineBreak,
 
 		RestServerCmd(),
+		WatchCmd(),
 		client.LineBreak,
 
 		keys.RootCmd(),
+		sigs.RootCmd(),
 		client.LineBreak,
 
 		VersionCmd(),
@@ -70,6 +73,7 @@ var rootCmd = &cobra.Command{
 		if err := viper.MergeInConfig(); err != nil {
 			return err
 		}
+		store.SetSigner(viper.GetString("signer"))
 
 		return nil
 	},
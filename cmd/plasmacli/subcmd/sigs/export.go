@@ -0,0 +1,39 @@
+package sigs
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"os"
+)
+
+// ExportCmd returns the sigs export command
+func ExportCmd() *cobra.Command {
+	return exportCmd
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <location>",
+	Short: "Export the stored confirmation signatures",
+	Long: `Exports every stored confirmation signature to a json file that can be imported on another machine.
+
+Usage:
+	plasmacli sigs export <location>
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		bz, err := store.ExportSigs()
+		if err != nil {
+			return fmt.Errorf("error exporting signatures: %s", err)
+		}
+
+		if err := ioutil.WriteFile(args[0], append(bz, '\n'), os.FileMode(0644)); err != nil {
+			return fmt.Errorf("error writing to file: %s", err)
+		}
+
+		fmt.Printf("Successfully exported confirmation signatures to %s\n", args[0])
+		return nil
+	},
+}
@@ -0,0 +1,112 @@
+package sigs
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io/ioutil"
+)
+
+// ImportCmd returns the sigs import command
+func ImportCmd() *cobra.Command {
+	config.AddPersistentTMFlags(importCmd)
+	importCmd.Flags().Bool(legacyF, false, "import the signatures stored by earlier versions in data/signatures.ldb. Requires the full node")
+	return importCmd
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [location]",
+	Short: "Import confirmation signatures",
+	Long: `Imports confirmation signatures from a file created by the export command.
+Signatures that are already stored are skipped. Importing stops at the first signature
+that conflicts with a stored signature.
+
+With --legacy, the signatures stored by earlier versions are imported instead. Their keys
+did not identify a single position, so each entry is matched against the transactions of
+the node by recovering the owners of the inputs from the signatures. Entries that match no
+transaction, or more than one, are listed and skipped.
+
+Usage:
+	plasmacli sigs import <location>
+	plasmacli sigs import --legacy
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+		if viper.GetBool(legacyF) {
+			if len(args) != 0 {
+				return fmt.Errorf("a location cannot be imported along with the legacy signatures")
+			}
+			cmd.SilenceUsage = true
+			return importLegacySigs(context.NewCLIContext())
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("the location to import from must be specified")
+		}
+
+		bz, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		count, err := store.ImportSigs(bz)
+		fmt.Printf("Imported %d confirmation signatures\n", count)
+		return err
+	},
+}
+
+// importLegacySigs saves the signatures of every legacy entry matching a
+// single transaction for each output of that transaction
+func importLegacySigs(ctx context.CLIContext) error {
+	list, err := store.ListLegacySigs()
+	if err != nil {
+		return err
+	}
+
+	count, skipped := 0, 0
+	for _, entry := range list {
+		var matched []plasma.Position
+		var matchedSigs [][]byte
+		var numOutputs int
+		for _, pos := range entry.Candidates {
+			input, err := client.TxInput(ctx, pos)
+			if err != nil {
+				continue
+			}
+			tx, err := client.Tx(ctx, input.TxHash)
+			if err != nil {
+				return err
+			}
+
+			if sigs, ok := entry.Match(tx.ConfirmationHash, input.InputAddresses); ok {
+				matched = append(matched, pos)
+				matchedSigs, numOutputs = sigs, len(tx.Transaction.Outputs)
+			}
+		}
+
+		if len(matched) != 1 {
+			fmt.Printf("Skipping entry 0x%x: matches %d transactions\n", entry.Key, len(matched))
+			skipped++
+			continue
+		}
+
+		for i := 0; i < numOutputs; i++ {
+			pos := plasma.NewPosition(matched[0].BlockNum, matched[0].TxIndex, uint8(i), nil)
+			saved, err := store.SaveSigs(pos, matchedSigs)
+			count += saved
+			if err != nil {
+				fmt.Printf("Imported %d confirmation signatures\n", count)
+				return err
+			}
+		}
+	}
+
+	fmt.Printf("Imported %d confirmation signatures from %d legacy entries. Skipped %d entries\n", count, len(list)-skipped, skipped)
+	return nil
+}
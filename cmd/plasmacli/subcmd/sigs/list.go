@@ -0,0 +1,45 @@
+package sigs
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/spf13/cobra"
+	"os"
+	"text/tabwriter"
+)
+
+// ListCmd returns the sigs list command
+func ListCmd() *cobra.Command {
+	return listCmd
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored confirmation signatures",
+	Long:  "Return the positions with stored confirmation signatures and the inputs that have signed",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		list, err := store.ListSigs()
+		if err != nil {
+			return err
+		}
+
+		w := new(tabwriter.Writer)
+		// Sets tab width to 8 characters
+		w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+		fmt.Fprintln(w, "POSITION:\tSIGNED:\tCOMPLETE:\t")
+		for _, stored := range list {
+			signed := 0
+			for _, sig := range stored.Signatures {
+				if len(sig) != 0 {
+					signed++
+				}
+			}
+			fmt.Fprintln(w, fmt.Sprintf("%s\t%d/%d\t%t\t", stored.Position, signed, len(stored.Signatures), stored.Complete()))
+		}
+		fmt.Fprintln(w)
+		w.Flush()
+
+		return nil
+	},
+}
@@ -0,0 +1,105 @@
+package sigs
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"math/big"
+	"strings"
+)
+
+// PruneCmd returns the sigs prune command
+func PruneCmd() *cobra.Command {
+	config.AddPersistentTMFlags(pruneCmd)
+	pruneCmd.Flags().String(beforeF, "", "remove signatures of outputs created before this plasma block")
+	pruneCmd.Flags().Bool(spentF, false, "remove signatures of outputs spent on the sidechain. Requires the full node")
+	pruneCmd.Flags().String(positionF, "", "remove the signatures of a single position")
+	pruneCmd.Flags().Bool(dryRunF, false, "print the positions that would be removed")
+	return pruneCmd
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove confirmation signatures that are no longer needed",
+	Long: `Removes stored confirmation signatures. Signatures may still be needed to exit or challenge
+with an output, so export them before pruning if in doubt. When more than one filter is set,
+signatures matching every filter are removed.
+
+Usage:
+	plasmacli sigs prune --spent
+	plasmacli sigs prune --before 1000 --dry-run
+	plasmacli sigs prune --position "(blknum.txindex.oindex.depositnonce)"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+
+		var before *big.Int
+		if beforeStr := viper.GetString(beforeF); beforeStr != "" {
+			var ok bool
+			if before, ok = new(big.Int).SetString(beforeStr, 10); !ok || before.Sign() <= 0 {
+				return fmt.Errorf("before must be a plasma block number")
+			}
+		}
+
+		var position *plasma.Position
+		if posStr := viper.GetString(positionF); posStr != "" {
+			pos, err := plasma.FromPositionString(strings.TrimSpace(posStr))
+			if err != nil {
+				return err
+			}
+			position = &pos
+		}
+
+		spent := viper.GetBool(spentF)
+		if before == nil && position == nil && !spent {
+			return fmt.Errorf("at least one of the before, spent or position flags must be set")
+		}
+
+		cmd.SilenceUsage = true
+		list, err := store.ListSigs()
+		if err != nil {
+			return err
+		}
+
+		ctx := context.NewCLIContext()
+		pruned := 0
+		for _, stored := range list {
+			pos := stored.Position
+			if position != nil && pos.String() != position.String() {
+				continue
+			}
+			if before != nil && (pos.IsDeposit() || pos.BlockNum.Cmp(before) >= 0) {
+				continue
+			}
+			if spent {
+				output, err := client.TxOutput(ctx, pos)
+				if err != nil {
+					fmt.Printf("Skipping %s: %s\n", pos, err)
+					continue
+				}
+				if !output.Spent {
+					continue
+				}
+			}
+
+			if viper.GetBool(dryRunF) {
+				fmt.Println(pos)
+			} else if err := store.DeleteSig(pos); err != nil {
+				return err
+			}
+			pruned++
+		}
+
+		if viper.GetBool(dryRunF) {
+			fmt.Printf("%d positions would be pruned\n", pruned)
+		} else {
+			fmt.Printf("Pruned the confirmation signatures of %d positions\n", pruned)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,33 @@
+package sigs
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// flags
+const (
+	beforeF   = "before"
+	dryRunF   = "dry-run"
+	legacyF   = "legacy"
+	positionF = "position"
+	spentF    = "spent"
+)
+
+// RootCmd returns the sigs command
+func RootCmd() *cobra.Command {
+	sigsCmd.AddCommand(
+		ListCmd(),
+		ExportCmd(),
+		ImportCmd(),
+		PruneCmd(),
+	)
+
+	return sigsCmd
+}
+
+var sigsCmd = &cobra.Command{
+	Use:   "sigs",
+	Short: "Manage local confirmation signatures",
+	Long: `Sigs allows you to manage the confirmation signatures stored locally.
+Signatures are stored by the full position of the output they confirm.`,
+}
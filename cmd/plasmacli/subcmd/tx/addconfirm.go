@@ -0,0 +1,98 @@
+package tx
+
+import (
+	"fmt"
+	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"strconv"
+	"strings"
+)
+
+// AddConfirmSigsCmd returns the tx add-confirm-sigs command
+func AddConfirmSigsCmd() *cobra.Command {
+	addConfirmSigsCmd.Flags().StringP(outF, "o", "", "file to write the transaction to instead of overwriting it")
+	return addConfirmSigsCmd
+}
+
+var addConfirmSigsCmd = &cobra.Command{
+	Use:   "add-confirm-sigs <file> <input index> [signatures]",
+	Short: "Add confirm signatures for an input to a transaction file",
+	Long: `Set the confirm signatures of an input in a transaction file. Signatures are hex encoded and separated by commas.
+If no signatures are given, the confirm signatures stored locally for the input position are used.
+Confirm signatures must be added before any input is signed.
+
+Usage:
+	plasmacli tx add-confirm-sigs tx.json 1 <signature>,<signature>
+	plasmacli tx add-confirm-sigs tx.json 0`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+
+		index, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to parse input index: %s", err)
+		}
+
+		e, err := readEnvelope(args[0])
+		if err != nil {
+			return err
+		}
+		if index < 0 || index >= len(e.Transaction.Inputs) {
+			return fmt.Errorf("input %d does not exist", index)
+		}
+
+		var sigs [][65]byte
+		if len(args) == 3 {
+			for _, token := range strings.Split(strings.TrimSpace(args[2]), ",") {
+				sig := ethcmn.FromHex(strings.TrimSpace(token))
+				if len(sig) != 65 {
+					return fmt.Errorf("signatures must be of length 65 bytes")
+				}
+				var signature [65]byte
+				copy(signature[:], sig)
+				sigs = append(sigs, signature)
+			}
+		} else {
+			position := e.Transaction.Inputs[index].Position
+			sig, err := clistore.GetSig(position)
+			if err != nil {
+				return fmt.Errorf("no confirm signatures stored for %s", position)
+			}
+			sigs = splitSigs(sig)
+		}
+
+		cmd.SilenceUsage = true
+
+		if err := e.AddConfirmSignatures(index, sigs); err != nil {
+			return err
+		}
+
+		return writeEnvelope(e, outPath(args[0]))
+	},
+}
+
+// returns the path set by the out flag or the given path
+func outPath(path string) string {
+	if out := viper.GetString(outF); out != "" {
+		return out
+	}
+	return path
+}
+
+// parses the comma separated input indexes
+func parseInputIndexes(indexes string, numInputs int) ([]int, error) {
+	var res []int
+	for _, token := range strings.Split(strings.TrimSpace(indexes), ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(token))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse input index: %s", err)
+		}
+		if index < 0 || index >= numInputs {
+			return nil, fmt.Errorf("input %d does not exist", index)
+		}
+		res = append(res, index)
+	}
+	return res, nil
+}
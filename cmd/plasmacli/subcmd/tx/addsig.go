@@ -0,0 +1,80 @@
+package tx
+
+import (
+	"fmt"
+	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AddSigCmd returns the tx add-sig command
+func AddSigCmd() *cobra.Command {
+	addSigCmd.Flags().String(inputF, "", "indexes of the inputs to sign, separated by commas. Required for inputs with an unknown owner")
+	addSigCmd.Flags().StringP(outF, "o", "", "file to write the transaction to instead of overwriting it")
+	return addSigCmd
+}
+
+var addSigCmd = &cobra.Command{
+	Use:   "add-sig <file> <account>",
+	Short: "Sign the inputs of a transaction file",
+	Long: `Sign every unsigned input of a transaction file that is owned by the account.
+Inputs whose owner was not recorded when the transaction was built must be selected with the input flag.
+Review the transaction with the inspect command before signing it.
+
+Usage:
+	plasmacli tx add-sig tx.json <account>
+	plasmacli tx add-sig tx.json <account> --input 0,1`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+
+		e, err := readEnvelope(args[0])
+		if err != nil {
+			return err
+		}
+
+		signer, err := clistore.GetSigner(args[1])
+		if err != nil {
+			return err
+		}
+
+		var indexes []int
+		if viper.GetString(inputF) != "" {
+			indexes, err = parseInputIndexes(viper.GetString(inputF), len(e.Transaction.Inputs))
+			if err != nil {
+				return err
+			}
+		} else {
+			for i, info := range e.Inputs {
+				if info.Owner == signer.Address() && !e.IsSigned(i) {
+					indexes = append(indexes, i)
+				}
+			}
+		}
+		if len(indexes) == 0 {
+			return fmt.Errorf("no unsigned inputs owned by 0x%x", signer.Address())
+		}
+
+		// confirm signatures cannot be added once an input is signed
+		for i, input := range e.Transaction.Inputs {
+			if !input.IsDeposit() && !input.IsFee() && len(input.ConfirmSignatures) == 0 {
+				return fmt.Errorf("input %d is missing confirm signatures. Add them with add-confirm-sigs before signing", i)
+			}
+		}
+
+		cmd.SilenceUsage = true
+
+		sig, err := signer.SignHash(e.TxHash())
+		if err != nil {
+			return err
+		}
+		for _, i := range indexes {
+			if err := e.AddSignature(i, sig); err != nil {
+				return err
+			}
+			fmt.Printf("Signed input %d: %s\n", i, e.Transaction.Inputs[i].Position)
+		}
+
+		return writeEnvelope(e, outPath(args[0]))
+	},
+}
@@ -0,0 +1,63 @@
+package tx
+
+import (
+	"fmt"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// BroadcastCmd returns the tx broadcast command
+func BroadcastCmd() *cobra.Command {
+	broadcastCmd.Flags().Bool(asyncF, false, "broadcast transactions asynchronously")
+	return broadcastCmd
+}
+
+var broadcastCmd = &cobra.Command{
+	Use:   "broadcast <file>",
+	Short: "Broadcast a signed transaction file",
+	Long: `Broadcast a transaction file once every input is signed and has its confirm signatures.
+The signer of every input must match the owner recorded when the transaction was built.
+
+Usage:
+	plasmacli tx broadcast tx.json
+	plasmacli tx broadcast tx.json --async`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+		ctx := context.NewCLIContext()
+
+		e, err := readEnvelope(args[0])
+		if err != nil {
+			return err
+		}
+
+		msg, err := e.SpendMsg()
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		txBytes, err := rlp.EncodeToBytes(&msg)
+		if err != nil {
+			return err
+		}
+
+		// broadcast to the node
+		if viper.GetBool(asyncF) {
+			if _, err := ctx.BroadcastTxAsync(txBytes); err != nil {
+				return err
+			}
+		} else {
+			res, err := ctx.BroadcastTxAndAwaitCommit(txBytes)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash 0x%x\n", res.Height, res.TxHash)
+		}
+
+		return nil
+	},
+}
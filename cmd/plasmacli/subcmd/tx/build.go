@@ -0,0 +1,134 @@
+package tx
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/FourthState/plasma-mvp-sidechain/client/offline"
+	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// BuildCmd returns the tx build command
+func BuildCmd() *cobra.Command {
+	buildCmd.Flags().String(positionF, "", "UTXO Positions to be spent, format: (blknum0.txindex0.oindex0.depositnonce0)::(blknum1.txindex1.oindex1.depositnonce1)::...")
+	buildCmd.Flags().StringP(confirmSigs0F, "0", "", "Input Confirmation Signatures for first input to be spent (separated by commas)")
+	buildCmd.Flags().StringP(confirmSigs1F, "1", "", "Input Confirmation Signatures for second input to be spent (separated by commas)")
+	buildCmd.Flags().String(feeF, "", "Fee to be spent. Defaults to the minimum fee required by the full node")
+	buildCmd.Flags().Bool(offlineF, false, "do not query the full node for the owners and amounts of the inputs")
+	buildCmd.Flags().StringP(outF, "o", "", "file to write the transaction to instead of stdout")
+	return buildCmd
+}
+
+var buildCmd = &cobra.Command{
+	Use:   "build <from> <amount> <to>",
+	Short: "Build an unsigned transaction",
+	Long: `Build a transaction without signing it and write it to a transaction file. The file is passed
+between the owners of the inputs to add confirm signatures and input signatures before it is broadcast.
+<from> are account names or addresses and select the inputs the same way as the spend command.
+Inputs owned by other parties must be provided with the position flag.
+Confirm signatures found locally or passed through flags are included. Confirm signatures must be added
+before any input is signed since they are part of the signed transaction hash.
+
+Usage:
+	plasmacli tx build <from> <amount> <to> --out tx.json
+	plasmacli tx build <from,address> <amount> <to> --position <position::position> --fee <fee> -o tx.json
+	plasmacli tx build <address> <amount> <to> --position <position> --fee <fee> --offline`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+		ctx := context.NewCLIContext()
+
+		accTokens := strings.Split(strings.TrimSpace(args[0]), ",")
+		if len(accTokens) > plasma.MaxTxInputs {
+			return fmt.Errorf("between 1 and %d accounts must be specified", plasma.MaxTxInputs)
+		}
+		var owners []ethcmn.Address
+		for _, token := range accTokens {
+			addr, err := clistore.ResolveAccount(strings.TrimSpace(token))
+			if err != nil {
+				return fmt.Errorf("failed to retrieve account %s: %s", token, err)
+			}
+			owners = append(owners, addr)
+		}
+
+		toAddrs, err := parseToAddresses(args[2])
+		if err != nil {
+			return err
+		}
+
+		amounts, fee, total, err := parseAmounts(args[1], toAddrs)
+		if err != nil {
+			return err
+		}
+
+		if fee == nil && viper.GetBool(offlineF) {
+			return fmt.Errorf("the fee must be set when building offline")
+		}
+
+		cmd.SilenceUsage = true
+
+		tx, err := buildTransactionWithFee(ctx, owners, toAddrs, amounts, fee, total)
+		if err != nil {
+			return err
+		}
+
+		// record the owner and amount of each input so that signers know what they sign
+		var info []offline.InputInfo
+		if !viper.GetBool(offlineF) {
+			for _, input := range tx.Inputs {
+				output, err := client.TxOutput(ctx, input.Position)
+				if err != nil {
+					return fmt.Errorf("failed to retrieve input %s. Use the offline flag to build without the full node: %s", input.Position, err)
+				}
+				info = append(info, offline.InputInfo{Owner: output.Output.Owner, Amount: output.Output.Amount})
+			}
+		}
+
+		e, err := offline.NewEnvelope(tx, info)
+		if err != nil {
+			return err
+		}
+
+		return writeEnvelope(e, viper.GetString(outF))
+	},
+}
+
+// reads the transaction file at the path
+func readEnvelope(path string) (*offline.Envelope, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := offline.Decode(bz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction file %s: %s", path, err)
+	}
+	return e, nil
+}
+
+// writes the transaction file to the path or stdout if the path is empty
+func writeEnvelope(e *offline.Envelope, path string) error {
+	bz, err := e.Encode()
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		fmt.Println(string(bz))
+		return nil
+	}
+
+	if err := ioutil.WriteFile(path, append(bz, '\n'), os.FileMode(0644)); err != nil {
+		return err
+	}
+	fmt.Printf("Transaction 0x%x written to %s\n", e.TxHash(), path)
+	return nil
+}
@@ -0,0 +1,90 @@
+package tx
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
+	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// UpdateFeeAddressCmd returns the update fee address command
+func UpdateFeeAddressCmd() *cobra.Command {
+	updateFeeAddressCmd.Flags().Bool(asyncF, false, "broadcast transactions asynchronously")
+	return updateFeeAddressCmd
+}
+
+var updateFeeAddressCmd = &cobra.Command{
+	Use:   "update-fee-address <account> <address>",
+	Short: "Change the address fees are collected to",
+	Long: `Send a transaction changing the address the fee outputs of each block are owned by, starting with the block the transaction is included in.
+The account must be the current fee address. Fees cannot be collected to the zero address.
+
+Usage:
+	plasmacli tx update-fee-address <account> <address>`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+		ctx := context.NewCLIContext()
+
+		if !ethcmn.IsHexAddress(args[1]) {
+			return fmt.Errorf("invalid address provided. please use hex format")
+		}
+
+		signer, err := clistore.GetSigner(args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		current, err := client.FeeAddress(ctx)
+		if err != nil {
+			return err
+		}
+
+		contractAddr, err := config.GetContractAddress()
+		if err != nil {
+			return err
+		}
+
+		msg := msgs.UpdateFeeAddressMsg{
+			FeeAddress:     ethcmn.HexToAddress(args[1]),
+			Nonce:          current.Nonce,
+			PlasmaContract: contractAddr,
+		}
+		sig, err := signer.SignHash(msg.GetSignBytes())
+		if err != nil {
+			return err
+		}
+		copy(msg.Signature[:], sig)
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+
+		txBytes, err := rlp.EncodeToBytes(&msg)
+		if err != nil {
+			return err
+		}
+
+		// broadcast to the node
+		if viper.GetBool(asyncF) {
+			if _, err := ctx.BroadcastTxAsync(txBytes); err != nil {
+				return err
+			}
+		} else {
+			res, err := ctx.BroadcastTxAndAwaitCommit(txBytes)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash 0x%x\n", res.Height, res.TxHash)
+		}
+
+		return nil
+	},
+}
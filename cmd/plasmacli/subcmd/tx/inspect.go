@@ -0,0 +1,69 @@
+package tx
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/spf13/cobra"
+)
+
+// InspectCmd returns the tx inspect command
+func InspectCmd() *cobra.Command {
+	return inspectCmd
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <file>",
+	Short: "Display a transaction file",
+	Long: `Display the inputs, outputs and fee of a transaction file along with the signatures
+that are still missing before it can be broadcast.
+
+Usage:
+	plasmacli tx inspect tx.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		e, err := readEnvelope(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Transaction Hash: 0x%x\n", e.TxHash())
+		for i, input := range e.Transaction.Inputs {
+			fmt.Printf("\nInput %d\nPosition: %s\n", i, input.Position)
+
+			info := e.Inputs[i]
+			if utils.IsZeroAddress(info.Owner) {
+				fmt.Println("Owner: unknown")
+			} else {
+				fmt.Printf("Owner: 0x%x\n", info.Owner)
+			}
+			if info.Amount == nil {
+				fmt.Println("Amount: unknown")
+			} else {
+				fmt.Printf("Amount: %s\n", info.Amount)
+			}
+
+			fmt.Printf("Confirm Signatures: %d\n", len(input.ConfirmSignatures))
+			if signer, err := e.Signer(i); err != nil {
+				fmt.Println("Signed: no")
+			} else {
+				fmt.Printf("Signed: 0x%x\n", signer)
+			}
+		}
+
+		for i, output := range e.Transaction.Outputs {
+			fmt.Printf("\nOutput %d\nOwner: 0x%x\nAmount: %s\n", i, output.Owner, output.Amount)
+		}
+		fmt.Printf("\nFee: %s\n", e.Transaction.Fee)
+
+		if missing := e.Missing(); len(missing) > 0 {
+			fmt.Println("\nMissing:")
+			for _, m := range missing {
+				fmt.Printf("  %s\n", m)
+			}
+		} else {
+			fmt.Println("\nReady to broadcast")
+		}
+
+		return nil
+	},
+}
@@ -2,6 +2,7 @@ package tx
 
 import (
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
+	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/spf13/cobra"
 )
 
@@ -12,9 +13,13 @@ const (
 	confirmSigs0F = "Input0ConfirmSigs"
 	confirmSigs1F = "Input1ConfirmSigs"
 	feeF          = "fee"
+	inputF        = "input"
 	inputsF       = "inputValues"
+	offlineF      = "offline"
+	outF          = "out"
 	ownerF        = "owner"
 	positionF     = "position"
+	postF         = "post"
 	replayF       = "replay"
 )
 
@@ -25,6 +30,16 @@ func RootCmd() *cobra.Command {
 		IncludeCmd(),
 		SpendCmd(),
 		SignCmd(),
+		SweepFeesCmd(),
+		UpdateFeeAddressCmd(),
+		UpdateValidatorCmd(),
+		client.LineBreak,
+
+		BuildCmd(),
+		AddConfirmSigsCmd(),
+		AddSigCmd(),
+		InspectCmd(),
+		BroadcastCmd(),
 	)
 
 	return txCmd
@@ -5,10 +5,8 @@ import (
 	"github.com/FourthState/plasma-mvp-sidechain/client"
 	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
-	"github.com/FourthState/plasma-mvp-sidechain/utils"
 	cosmoscli "github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/context"
-	ethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"strings"
@@ -21,6 +19,7 @@ const (
 func SignCmd() *cobra.Command {
 	signCmd.Flags().String(ownerF, "", "Owner of the output (required with position flag)")
 	signCmd.Flags().String(positionF, "", "Position of transaction to finalize (required with owner flag)")
+	signCmd.Flags().Bool(postF, false, "post the confirmation signatures to the mailbox of the full node")
 	return signCmd
 }
 
@@ -29,10 +28,12 @@ var signCmd = &cobra.Command{
 	Short: "Sign confirmation signatures for pending transactions",
 	Long: `Iterate over all unfinalized transaction corresponding to the provided account. 
 Prompt the user for confirmation to finailze the pending transactions. Owner and Position flags can be used to finalize a specific transaction.
+With the post flag, the signatures are posted to the mailbox of the full node for the recipients to retrieve.
 
 Usage:
 	plasmacli sign <account>
-	plasmacli sign <account> --position "(blknum.txindex.oindex.depositnonce)"`,
+	plasmacli sign <account> --position "(blknum.txindex.oindex.depositnonce)"
+	plasmacli sign <account> --post`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		viper.BindPFlags(cmd.Flags())
@@ -40,10 +41,11 @@ Usage:
 
 		name := args[0]
 
-		signerAddr, err := clistore.GetAccount(name)
+		signer, err := clistore.GetSigner(name)
 		if err != nil {
 			return err
 		}
+		signerAddr := signer.Address()
 
 		positionS := viper.GetString(positionF)
 		if positionS != "" {
@@ -52,7 +54,7 @@ Usage:
 				return err
 			}
 
-			err = signSingleConfirmSig(ctx, position, signerAddr, name)
+			err = signSingleConfirmSig(ctx, position, signer)
 			return err
 		}
 
@@ -72,7 +74,7 @@ Usage:
 				}
 
 				for _, pos := range tx.Transaction.InputPositions() {
-					err = signSingleConfirmSig(ctx, pos, signerAddr, name)
+					err = signSingleConfirmSig(ctx, pos, signer)
 					if err != nil {
 						fmt.Println(err)
 					}
@@ -87,7 +89,7 @@ Usage:
 // generate confirmation signature for specified position and verify that
 // the inputs provided are correct. Signing address should match one of
 // the input addresses. Generate confirmation signature for given output.
-func signSingleConfirmSig(ctx context.CLIContext, position plasma.Position, signerAddr ethcmn.Address, name string) error {
+func signSingleConfirmSig(ctx context.CLIContext, position plasma.Position, signer clistore.Signer) error {
 	// query for output for the specified position
 	output, err := client.TxOutput(ctx, position)
 	if err != nil {
@@ -100,13 +102,14 @@ func signSingleConfirmSig(ctx context.CLIContext, position plasma.Position, sign
 
 	sig, _ := clistore.GetSig(output.Position)
 	inputAddrs := inputInfo.InputAddresses
-
-	if len(sig) == 130 || (len(sig) == 65 && len(inputAddrs) == 1) {
-		return nil
-	}
+	confirmSigs := splitSigs(sig)
 
 	for i, input := range inputAddrs {
-		if input != signerAddr {
+		if input != signer.Address() {
+			continue
+		}
+		// already signed
+		if i < len(confirmSigs) && confirmSigs[i] != ([65]byte{}) {
 			continue
 		}
 		// get confirmation to generate signature
@@ -120,19 +123,27 @@ func signSingleConfirmSig(ctx context.CLIContext, position plasma.Position, sign
 			return nil
 		}
 
-		hash := utils.ToEthSignedMessageHash(output.ConfirmationHash)
-		sig, err := clistore.SignHashWithPassphrase(name, hash)
+		sig, err := signer.SignHash(output.ConfirmationHash)
 		if err != nil {
 			return fmt.Errorf("failed to generate confirmation signature: %s", err)
 		}
 
-		if err := clistore.SaveSig(output.Position, sig, i == 0); err != nil {
+		if err := clistore.SaveSig(output.Position, sig, i, len(inputAddrs)); err != nil {
 			return err
 		}
 
 		// print the results
 		fmt.Printf("Confirmation Signature for output with position: %s\n", output.Position)
 		fmt.Printf("0x%x\n", sig)
+
+		if viper.GetBool(postF) {
+			posted := make([][65]byte, len(inputAddrs))
+			copy(posted[i][:], sig)
+			if _, err := client.PostConfirmSigs(ctx, output.Position, posted); err != nil {
+				return fmt.Errorf("failed to post confirmation signature: %s", err)
+			}
+			fmt.Println("Posted to the mailbox")
+		}
 	}
 	return nil
 }
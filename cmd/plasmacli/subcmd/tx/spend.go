@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
 	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/subcmd/eth"
 	"github.com/FourthState/plasma-mvp-sidechain/msgs"
@@ -16,14 +17,15 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"math/big"
+	"sort"
 	"strings"
 )
 
 func SpendCmd() *cobra.Command {
-	spendCmd.Flags().String(positionF, "", "UTXO Positions to be spent, format: (blknum0.txindex0.oindex0.depositnonce0)::(blknum1.txindex1.oindex1.depositnonce1)")
+	spendCmd.Flags().String(positionF, "", "UTXO Positions to be spent, format: (blknum0.txindex0.oindex0.depositnonce0)::(blknum1.txindex1.oindex1.depositnonce1)::...")
 	spendCmd.Flags().StringP(confirmSigs0F, "0", "", "Input Confirmation Signatures for first input to be spent (separated by commas)")
 	spendCmd.Flags().StringP(confirmSigs1F, "1", "", "Input Confirmation Signatures for second input to be spent (separated by commas)")
-	spendCmd.Flags().String(feeF, "0", "Fee to be spent")
+	spendCmd.Flags().String(feeF, "", "Fee to be spent. Defaults to the minimum fee required by the full node")
 	spendCmd.Flags().Bool(asyncF, false, "broadcast transactions asynchronously")
 	return spendCmd
 }
@@ -31,14 +33,18 @@ func SpendCmd() *cobra.Command {
 var spendCmd = &cobra.Command{
 	Use:   "spend <from> <amount> <to>",
 	Short: "Send a transaction spending utxos",
-	Long: `Send a transaction spending from the specified account. If sending to multiple addresses, the account specified must contain exact utxo values.
-If a single spending account is specified, leftover value from spending the utxo will be sent back to the account. 
-In the case that the spending account does not have a large enough single utxo, two input utxos will be used. User can override retireved data with position and confirm signature flags.
+	Long: `Send a transaction spending from the specified account. Leftover value from spending the utxos will be sent back to the first account.
+Inputs are selected from the utxos of the spending account. Exact matches using one or two utxos are preferred, followed by the pairing that leaves the least change.
+User can override retireved data with position and confirm signature flags.
+Spends are limited to 2 inputs and 2 outputs, including the change output, since the rootchain contract only decodes transactions of this size.
+Without the fee flag, the transaction pays the minimum fee required by the fee policy of the full node.
+When multiple accounts are specified, the positions must be provided and each account signs the input at the same index.
 <to> in the following usage is the address being sent the utxo amounts.
 
 Usage:
 	plasmacli <from> <amount> <to>
-	plasmacli <from,from> <amount,amount> <to,to> --fee <fee>
+	plasmacli <from> <amount,amount,amount> <to,to,to> --fee <fee>
+	plasmacli <from,from> <amount> <to> --position <position::position>
 	plasmacli <from> <amount> <to> --confirmSigs0 <signature> --confirmSig1 <signature>`,
 	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -50,13 +56,26 @@ Usage:
 		names := args[0]
 
 		accTokens := strings.Split(strings.TrimSpace(names), ",")
-		if len(accTokens) == 0 || len(accTokens) > 2 {
-			return fmt.Errorf("1 or 2 accounts must be specified")
+		if len(accTokens) == 0 || len(accTokens) > plasma.MaxTxInputs {
+			return fmt.Errorf("between 1 and %d accounts must be specified", plasma.MaxTxInputs)
 		}
 		for _, token := range accTokens {
 			accs = append(accs, strings.TrimSpace(token))
 		}
 
+		// each account signs once, through the configured signer
+		signers := make(map[string]clistore.Signer)
+		for _, acc := range accs {
+			if _, ok := signers[acc]; ok {
+				continue
+			}
+			signer, err := clistore.GetSigner(acc)
+			if err != nil {
+				return err
+			}
+			signers[acc] = signer
+		}
+
 		toAddrs, err := parseToAddresses(args[2])
 		if err != nil {
 			return err
@@ -69,82 +88,35 @@ Usage:
 
 		cmd.SilenceUsage = true
 
-		inputs, err := parseInputs()
-		if err != nil {
-			return err
+		owners := make([]ethcmn.Address, len(accs))
+		for i, acc := range accs {
+			owners[i] = signers[acc].Address()
 		}
 
-		change := new(big.Int)
-		if len(inputs) == 0 {
-			inputs, change, err = retrieveInputs(ctx, accs, total)
-			if err != nil {
-				return err
-			}
-
-			if len(inputs) == 0 {
-				return fmt.Errorf("failed to generate a valid transaction. Please provide the inputs")
-			}
-		}
-
-		// get confirmation signatures from local storage
-		confirmSignatures := getConfirmSignatures(inputs)
-
-		// override retireved signatures if provided through flags
-		confirmSignatures, err = parseConfirmSignatures(confirmSignatures)
+		tx, err := buildTransactionWithFee(ctx, owners, toAddrs, amounts, fee, total)
 		if err != nil {
 			return err
 		}
 
-		// build transaction
-		// create the inputs without signatures
-		tx := plasma.Transaction{}
-		tx.Inputs = append(tx.Inputs, plasma.NewInput(inputs[0], [65]byte{}, confirmSignatures[0]))
-		if len(inputs) > 1 {
-			tx.Inputs = append(tx.Inputs, plasma.NewInput(inputs[1], [65]byte{}, confirmSignatures[1]))
-		} else {
-			tx.Inputs = append(tx.Inputs, plasma.NewInput(plasma.NewPosition(nil, 0, 0, nil), [65]byte{}, nil))
-		}
-
-		// generate outputs
-		// use change to determine outcome of second output
-		tx.Outputs = append(tx.Outputs, plasma.NewOutput(toAddrs[0], amounts[0]))
-		if len(toAddrs) > 1 {
-			if change.Sign() == 0 {
-				tx.Outputs = append(tx.Outputs, plasma.NewOutput(toAddrs[1], amounts[1]))
-			} else {
-				return fmt.Errorf("cannot spend to two addresses since exact utxo inputs could not be found")
+		// create and fill in the signatures. each account signs once
+		txHash := tx.TxHash()
+		signatures := make(map[string][65]byte)
+		for i := range tx.Inputs {
+			signer := accs[0]
+			if len(accs) > 1 {
+				signer = accs[i]
 			}
-		} else if change.Sign() == 1 {
-			addr, err := clistore.GetAccount(accs[0])
-			if err != nil {
-				return err
-			}
-			tx.Outputs = append(tx.Outputs, plasma.NewOutput(addr, change))
-		} else {
-			tx.Outputs = append(tx.Outputs, plasma.NewOutput(ethcmn.Address{}, nil))
-		}
-		tx.Fee = fee
 
-		// create and fill in the signatures
-		signer := accs[0]
-		txHash := utils.ToEthSignedMessageHash(tx.TxHash())
-		var signature [65]byte
-		sig, err := clistore.SignHashWithPassphrase(signer, txHash)
-		if err != nil {
-			return err
-		}
-		copy(signature[:], sig)
-		tx.Inputs[0].Signature = signature
-		if len(inputs) > 1 {
-			if len(accs) > 2 {
-				signer = accs[1]
+			signature, ok := signatures[signer]
+			if !ok {
+				sig, err := signers[signer].SignHash(txHash)
+				if err != nil {
+					return err
+				}
+				copy(signature[:], sig)
+				signatures[signer] = signature
 			}
-			sig, err := clistore.SignHashWithPassphrase(signer, txHash)
-			if err != nil {
-				return err
-			}
-			copy(signature[:], sig)
-			tx.Inputs[1].Signature = signature
+			tx.Inputs[i].Signature = signature
 		}
 
 		// create SpendMsg and txBytes
@@ -177,37 +149,124 @@ Usage:
 	},
 }
 
+// builds the transaction with `fee`. If the fee is nil, the minimum fee required
+// by the fee policy of the full node is paid. The required fee depends on the size
+// of the transaction, which depends on the inputs selected to cover the fee, so the
+// transaction is rebuilt until it pays the fee required for its own size
+func buildTransactionWithFee(ctx context.CLIContext, owners, toAddrs []ethcmn.Address, amounts []*big.Int, fee, total *big.Int) (plasma.Transaction, error) {
+	if fee != nil {
+		return buildTransaction(ctx, owners, toAddrs, amounts, fee, total)
+	}
+
+	policy, err := client.FeePolicy(ctx)
+	if err != nil {
+		return plasma.Transaction{}, fmt.Errorf("failed to retrieve the fee policy of the full node. Use the fee flag to set the fee: %s", err)
+	}
+
+	fee = policy.RequiredFee(0)
+	for attempt := 0; attempt < 3; attempt++ {
+		tx, err := buildTransaction(ctx, owners, toAddrs, amounts, fee, new(big.Int).Add(total, fee))
+		if err != nil {
+			return tx, err
+		}
+
+		// input signatures are fixed width so the unsigned size matches the signed size
+		txBytes, err := rlp.EncodeToBytes(&msgs.SpendMsg{Transaction: tx})
+		if err != nil {
+			return tx, err
+		}
+		required := policy.RequiredFee(len(txBytes))
+		if tx.Fee.Cmp(required) >= 0 {
+			return tx, nil
+		}
+		fee = required
+	}
+
+	return plasma.Transaction{}, fmt.Errorf("failed to build a transaction paying the required fee. Use the fee flag to set the fee")
+}
+
+// builds a transaction without input signatures spending from the owners.
+// Inputs are retrieved for the first owner unless provided through flags.
+// Leftover value is sent back to the first owner
+func buildTransaction(ctx context.CLIContext, owners, toAddrs []ethcmn.Address, amounts []*big.Int, fee, total *big.Int) (tx plasma.Transaction, err error) {
+	inputs, err := parseInputs()
+	if err != nil {
+		return tx, err
+	}
+
+	change := new(big.Int)
+	if len(inputs) == 0 {
+		inputs, change, err = retrieveInputs(ctx, owners[0], len(owners), total)
+		if err != nil {
+			return tx, err
+		}
+
+		if len(inputs) == 0 {
+			return tx, fmt.Errorf("failed to generate a valid transaction. Please provide the inputs")
+		}
+	}
+	if len(owners) > 1 && len(owners) != len(inputs) {
+		return tx, fmt.Errorf("number of accounts must equal the number of inputs when spending from multiple accounts")
+	}
+
+	// get confirmation signatures from local storage
+	confirmSignatures := getConfirmSignatures(inputs)
+
+	// override retireved signatures if provided through flags
+	confirmSignatures, err = parseConfirmSignatures(confirmSignatures)
+	if err != nil {
+		return tx, err
+	}
+
+	// build transaction
+	// create the inputs without signatures
+	for i, input := range inputs {
+		tx.Inputs = append(tx.Inputs, plasma.NewInput(input, [65]byte{}, confirmSignatures[i]))
+	}
+
+	// generate outputs. leftover value is sent back to the first account
+	for i, addr := range toAddrs {
+		tx.Outputs = append(tx.Outputs, plasma.NewOutput(addr, amounts[i]))
+	}
+	if change.Sign() == 1 {
+		if len(tx.Outputs) == plasma.MaxTxOutputs {
+			return tx, fmt.Errorf("cannot create a change output since exact utxo inputs could not be found")
+		}
+		tx.Outputs = append(tx.Outputs, plasma.NewOutput(owners[0], change))
+	}
+	tx.Fee = fee
+
+	return tx, nil
+}
+
 // Retrieve confirmation signatures from local storage if they exist
-func getConfirmSignatures(inputs []plasma.Position) (confirmSignatures [2][][65]byte) {
+func getConfirmSignatures(inputs []plasma.Position) (confirmSignatures [][][65]byte) {
+	confirmSignatures = make([][][65]byte, len(inputs))
 	for i, input := range inputs {
 		sig, _ := clistore.GetSig(input)
-		if sig != nil {
-			var sigs [][65]byte
-			switch len(sig) {
-			case 65:
-				var s [65]byte
-				copy(s[:], sig[:])
-				sigs = append(sigs, s)
-			case 130:
-				var s [65]byte
-				copy(s[:], sig[:65])
-				sigs = append(sigs, s)
-				copy(s[:], sig[65:])
-				sigs = append(sigs, s)
-			}
-			confirmSignatures[i] = sigs
-		}
+		confirmSignatures[i] = splitSigs(sig)
 	}
 	return confirmSignatures
 }
 
+// splits concatenated confirm signatures into 65 byte signatures
+func splitSigs(sig []byte) [][65]byte {
+	var sigs [][65]byte
+	for i := 0; i+65 <= len(sig); i += 65 {
+		var s [65]byte
+		copy(s[:], sig[i:i+65])
+		sigs = append(sigs, s)
+	}
+	return sigs
+}
+
 // parses input amounts and fee
-// amounts - [amount0, amount1]
+// amounts - [amount0, amount1, ...]
 func parseAmounts(amtArgs string, toAddrs []ethcmn.Address) (amounts []*big.Int, fee, total *big.Int, err error) {
 	total = new(big.Int)
 	amountTokens := strings.Split(strings.TrimSpace(amtArgs), ",")
-	if len(amountTokens) != 1 && len(amountTokens) != 2 {
-		return amounts, fee, total, fmt.Errorf("1 or 2 output amounts must be specified")
+	if len(amountTokens) == 0 || len(amountTokens) > plasma.MaxTxOutputs {
+		return amounts, fee, total, fmt.Errorf("between 1 and %d output amounts must be specified", plasma.MaxTxOutputs)
 	}
 
 	if len(amountTokens) != len(toAddrs) {
@@ -224,19 +283,26 @@ func parseAmounts(amtArgs string, toAddrs []ethcmn.Address) (amounts []*big.Int,
 		amounts = append(amounts, num)
 	}
 
+	// the fee is left nil when it is set from the fee policy of the full node
+	feeStr := strings.TrimSpace(viper.GetString(feeF))
+	if feeStr == "" {
+		return amounts, nil, total, nil
+	}
+
 	var ok bool
-	fee, ok = new(big.Int).SetString(strings.TrimSpace(viper.GetString(feeF)), 10)
-	if !ok {
-		return amounts, fee, total, fmt.Errorf("failed to parse fee: %s", fee)
+	fee, ok = new(big.Int).SetString(feeStr, 10)
+	if !ok || fee.Sign() < 0 {
+		return amounts, fee, total, fmt.Errorf("failed to parse fee: %s", feeStr)
 	}
 	total.Add(total, fee)
 
 	return amounts, fee, total, nil
 }
 
-// parse confirmation signatures passed in through flags
-func parseConfirmSignatures(confirmSignatures [2][][65]byte) ([2][][65]byte, error) {
-	for i := 0; i < 2; i++ {
+// parse confirmation signatures passed in through flags. The flags
+// override the signatures of the first two inputs
+func parseConfirmSignatures(confirmSignatures [][][65]byte) ([][][65]byte, error) {
+	for i := 0; i < 2 && i < len(confirmSignatures); i++ {
 		var flag string
 		if i == 0 {
 			flag = confirmSigs0F
@@ -248,8 +314,8 @@ func parseConfirmSignatures(confirmSignatures [2][][65]byte) ([2][][65]byte, err
 		// empty confirmsig
 		if len(confirmSigTokens) == 1 && confirmSigTokens[0] == "" {
 			continue
-		} else if len(confirmSigTokens) > 2 {
-			return confirmSignatures, fmt.Errorf("only pass in 0, 1 or 2, confirm signatures")
+		} else if len(confirmSigTokens) > plasma.MaxTxInputs {
+			return confirmSignatures, fmt.Errorf("only pass in up to %d confirm signatures", plasma.MaxTxInputs)
 		}
 
 		var confirmSignature [][65]byte
@@ -281,8 +347,8 @@ func parseInputs() (inputs []plasma.Position, err error) {
 		return inputs, err
 	}
 
-	if len(positions) > 2 {
-		return inputs, fmt.Errorf("only pass in 1 or 2 positions")
+	if len(positions) > plasma.MaxTxInputs {
+		return inputs, fmt.Errorf("only pass in up to %d positions", plasma.MaxTxInputs)
 	}
 
 	for _, token := range positions {
@@ -300,8 +366,8 @@ func parseInputs() (inputs []plasma.Position, err error) {
 // parse the passed in addresses that will be sent to
 func parseToAddresses(addresses string) (toAddrs []ethcmn.Address, err error) {
 	toAddrTokens := strings.Split(strings.TrimSpace(addresses), ",")
-	if len(toAddrTokens) == 0 || len(toAddrTokens) > 2 {
-		return toAddrs, fmt.Errorf("1 or 2 outputs must be specified")
+	if len(toAddrTokens) == 0 || len(toAddrTokens) > plasma.MaxTxOutputs {
+		return toAddrs, fmt.Errorf("between 1 and %d outputs must be specified", plasma.MaxTxOutputs)
 	}
 
 	for _, token := range toAddrTokens {
@@ -322,18 +388,13 @@ func parseToAddresses(addresses string) (toAddrs []ethcmn.Address, err error) {
 
 // attempt to retrieve inputs to generate a valid spend transaction
 // returns inputs and sum(inputs) - total
-func retrieveInputs(ctx context.CLIContext, accs []string, total *big.Int) (inputs []plasma.Position, change *big.Int, err error) {
+func retrieveInputs(ctx context.CLIContext, addr ethcmn.Address, numAccs int, total *big.Int) (inputs []plasma.Position, change *big.Int, err error) {
 	change = total
 	// must specifiy inputs if using two accounts
-	if len(accs) > 1 {
+	if numAccs > 1 {
 		return inputs, change, nil
 	}
 
-	addr, err := clistore.GetAccount(accs[0])
-	if err != nil {
-		return inputs, change, err
-	}
-
 	queryPath := fmt.Sprintf("custom/data/info/%s", addr)
 	res, err := ctx.Query(queryPath, nil)
 	if err != nil {
@@ -345,66 +406,60 @@ func retrieveInputs(ctx context.CLIContext, accs []string, total *big.Int) (inpu
 		return inputs, change, err
 	}
 
-	var optimalChange = total
-	var position0, position1 plasma.Position
-	// iterate through utxo's looking for optimal input pairing
-	// return input pairing if input + input == total
-	for i, utxo0 := range utxos {
-		exitted, err := eth.HasTxExited(utxo0.Position)
+	// filter out spent and exited utxos
+	var spendable []store.TxOutput
+	for _, utxo := range utxos {
+		if utxo.Spent {
+			continue
+		}
+		exitted, err := eth.HasTxExited(utxo.Position)
 		if err != nil {
 			return nil, nil, fmt.Errorf("must connect full eth node or specify inputs using flags. Error encountered: %s", err)
 		}
-		if exitted || utxo0.Spent {
-			continue
-		}
-		// check if first utxo satisfies transfer amount
-		if utxo0.Output.Amount.Cmp(total) == 0 {
-			inputs = append(inputs, utxo0.Position)
-			return inputs, big.NewInt(0), nil
+		if !exitted {
+			spendable = append(spendable, utxo)
 		}
-		for k, utxo1 := range utxos {
-			// do not pair an input with itself
-			if i == k {
-				continue
-			}
-
-			exitted, err := eth.HasTxExited(utxo1.Position)
-			if err != nil {
-				return nil, nil, fmt.Errorf("must connect full eth node or specify inputs using flags. Error encountered: %s", err)
-			}
-			if exitted || utxo1.Spent {
-				continue
-			}
-
-			// check if only utxo1 satisfies transfer amount
-			if utxo0.Output.Amount.Cmp(total) == 0 {
-				inputs = append(inputs, utxo0.Position)
-				return inputs, big.NewInt(0), nil
-			}
-			// check for exact match
-			sum := new(big.Int).Add(utxo0.Output.Amount, utxo1.Output.Amount)
-			if sum.Cmp(total) == 0 {
-				inputs = append(inputs, utxo0.Position)
-				inputs = append(inputs, utxo1.Position)
-				return inputs, big.NewInt(0), nil
-			}
+	}
 
-			diff := new(big.Int).Sub(sum, total)
-			if diff.Sign() == 1 && diff.Cmp(optimalChange) == -1 {
-				optimalChange = diff
-				position0 = utxo0.Position
-				position1 = utxo1.Position
-			}
+	inputs, change = selectInputs(spendable, total)
+	return inputs, change, nil
+}
 
+// selectInputs chooses the utxos that cover `total` and returns them along with
+// sum(inputs) - total. Exact matches using one or two utxos are preferred,
+// followed by the selection with the least change. At most two utxos are
+// selected since larger spends cannot be decoded by the rootchain contract.
+// Inputs are ordered largest first so that the first input covers the fee.
+// No inputs are returned if no pairing covers the total
+func selectInputs(utxos []store.TxOutput, total *big.Int) ([]plasma.Position, *big.Int) {
+	sort.SliceStable(utxos, func(i, j int) bool {
+		return utxos[i].Output.Amount.Cmp(utxos[j].Output.Amount) > 0
+	})
+
+	var (
+		optimalInputs []plasma.Position
+		optimalChange *big.Int
+	)
+	consider := func(sum *big.Int, positions ...plasma.Position) {
+		diff := new(big.Int).Sub(sum, total)
+		if diff.Sign() >= 0 && (optimalChange == nil || diff.Cmp(optimalChange) < 0) {
+			optimalInputs = positions
+			optimalChange = diff
 		}
 	}
 
-	// check if a pairing was found
-	if optimalChange.Cmp(total) == 0 {
-		return inputs, optimalChange, nil
+	for i, utxo0 := range utxos {
+		consider(utxo0.Output.Amount, utxo0.Position)
+		for _, utxo1 := range utxos[i+1:] {
+			consider(new(big.Int).Add(utxo0.Output.Amount, utxo1.Output.Amount), utxo0.Position, utxo1.Position)
+		}
+		if optimalChange != nil && optimalChange.Sign() == 0 {
+			return optimalInputs, optimalChange
+		}
+	}
+	if optimalChange != nil {
+		return optimalInputs, optimalChange
 	}
 
-	inputs = append(inputs, position0)
-	inputs = append(inputs, position1)
-	return inputs, optimalChange, nil
+	return nil, total
 }
@@ -0,0 +1,102 @@
+package tx
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/subcmd/eth"
+	"github.com/FourthState/plasma-mvp-sidechain/handlers"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// SweepFeesCmd returns the sweep fees command
+func SweepFeesCmd() *cobra.Command {
+	sweepFeesCmd.Flags().Bool(asyncF, false, "broadcast transactions asynchronously")
+	return sweepFeesCmd
+}
+
+var sweepFeesCmd = &cobra.Command{
+	Use:   "sweep-fees <account>",
+	Short: "Consolidate fee outputs into a single output",
+	Long: `Send a transaction spending the unspent fee outputs of the account, along with the output of its previous sweep, into a single output owned by the account.
+Each sweep spends 2 outputs, the output of the previous sweep and the next fee output, since the rootchain contract only decodes transactions with 2 inputs.
+The sweep pays the minimum fee required by the fee policy of the full node. Fee outputs that have been exited on the rootchain are skipped.
+Run the command repeatedly to sweep the remaining outputs.
+
+Usage:
+	plasmacli tx sweep-fees <account>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+		ctx := context.NewCLIContext()
+
+		signer, err := clistore.GetSigner(args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		fees, err := client.FeeOutputs(ctx, signer.Address())
+		if err != nil {
+			return err
+		}
+		policy, err := client.FeePolicy(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve the fee policy of the full node: %s", err)
+		}
+
+		var exitErr error
+		exited := func(pos plasma.Position) bool {
+			ok, err := eth.HasTxExited(pos)
+			if err != nil && exitErr == nil {
+				exitErr = fmt.Errorf("must connect full eth node to sweep fees. Error encountered: %s", err)
+			}
+			return ok || err != nil
+		}
+		sweep, ok := handlers.NewFeeSweep(fees, signer.Address(), policy, exited)
+		if exitErr != nil {
+			return exitErr
+		}
+		if !ok {
+			return fmt.Errorf("no fee outputs to sweep. At least two unspent outputs covering the fee are required")
+		}
+
+		tx, err := sweep.Sign(signer.SignHash)
+		if err != nil {
+			return err
+		}
+
+		msg := msgs.SpendMsg{
+			Transaction: tx,
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			return fmt.Errorf("failed on validating transaction. please open an issue on github. error: %s", err)
+		}
+
+		txBytes, err := rlp.EncodeToBytes(&msg)
+		if err != nil {
+			return err
+		}
+
+		// broadcast to the node
+		if viper.GetBool(asyncF) {
+			if _, err := ctx.BroadcastTxAsync(txBytes); err != nil {
+				return err
+			}
+		} else {
+			res, err := ctx.BroadcastTxAndAwaitCommit(txBytes)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Swept %d outputs into %s. Committed at block %d. Hash 0x%x\n", len(tx.Inputs), tx.Outputs[0].Amount, res.Height, res.TxHash)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,106 @@
+package tx
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
+	clistore "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"strconv"
+)
+
+// UpdateValidatorCmd returns the update validator command
+func UpdateValidatorCmd() *cobra.Command {
+	updateValidatorCmd.Flags().Bool(asyncF, false, "broadcast transactions asynchronously")
+	return updateValidatorCmd
+}
+
+var updateValidatorCmd = &cobra.Command{
+	Use:   "update-validator <account> <pubkey> <power>",
+	Short: "Add, remove or change the voting power of a validator",
+	Long: `Send a transaction setting the voting power of the validator with the ed25519 consensus public key. The public key is
+hex or base64 encoded, as found in the priv_validator_key.json of the validator. A power of 0 removes the validator from the set.
+The account must be the operator of the rootchain contract. The update takes effect two blocks after the transaction is included.
+
+Usage:
+	plasmacli tx update-validator <account> <pubkey> <power>`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+		ctx := context.NewCLIContext()
+
+		pubKey, err := hex.DecodeString(utils.RemoveHexPrefix(args[1]))
+		if err != nil {
+			if pubKey, err = base64.StdEncoding.DecodeString(args[1]); err != nil {
+				return fmt.Errorf("public key must be hex or base64 encoded")
+			}
+		}
+		if len(pubKey) != 32 {
+			return fmt.Errorf("public key must be a 32-byte ed25519 key")
+		}
+
+		power, err := strconv.ParseUint(args[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("power must be a non-negative integer")
+		}
+
+		signer, err := clistore.GetSigner(args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+
+		set, err := client.Validators(ctx)
+		if err != nil {
+			return err
+		}
+
+		contractAddr, err := config.GetContractAddress()
+		if err != nil {
+			return err
+		}
+
+		msg := msgs.UpdateValidatorMsg{
+			Power:          power,
+			Nonce:          set.Nonce,
+			PlasmaContract: contractAddr,
+		}
+		copy(msg.ConsPubKey[:], pubKey)
+		sig, err := signer.SignHash(msg.GetSignBytes())
+		if err != nil {
+			return err
+		}
+		copy(msg.Signature[:], sig)
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+
+		txBytes, err := rlp.EncodeToBytes(&msg)
+		if err != nil {
+			return err
+		}
+
+		// broadcast to the node
+		if viper.GetBool(asyncF) {
+			if _, err := ctx.BroadcastTxAsync(txBytes); err != nil {
+				return err
+			}
+		} else {
+			res, err := ctx.BroadcastTxAndAwaitCommit(txBytes)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Committed at block %d. Hash 0x%x\n", res.Height, res.TxHash)
+		}
+
+		return nil
+	},
+}
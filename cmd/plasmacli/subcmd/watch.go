@@ -0,0 +1,102 @@
+package subcmd
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/config"
+	ks "github.com/FourthState/plasma-mvp-sidechain/cmd/plasmacli/store"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// flags
+	watchGasLimitF     = "gas-limit"
+	watchPollIntervalF = "poll-interval"
+	watchStartBlockF   = "start-block"
+)
+
+// WatchCmd returns the watchtower command
+func WatchCmd() *cobra.Command {
+	config.AddPersistentTMFlags(watchCmd)
+	watchCmd.Flags().StringP(watchGasLimitF, "g", "300000", "gas limit for challenge transactions")
+	watchCmd.Flags().String(watchPollIntervalF, "15s", "interval at which the rootchain is checked for new exits")
+	watchCmd.Flags().Uint64(watchStartBlockF, 0, "ethereum block to start watching for exits from")
+	return watchCmd
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <account>",
+	Short: "Watch the rootchain and challenge invalid exits",
+	Long: `Run a watchtower that monitors the rootchain for deposit and transaction exits.
+Every exited position is checked against the connected full node for a spending transaction.
+If the position was spent, the exit is challenged with the spend from the given account
+and the account receives the exit bond of every successful challenge.
+
+A spend can only be used in a challenge once its confirmation signatures have been included
+in a later transaction, unless the exit committed to an incorrect fee. Exits that cannot be
+challenged yet are checked again until they are finalized.
+
+Usage:
+	plasmacli watch <account> --node <host>:<port> --trust-node
+	plasmacli watch <account> --start-block 4000000 --poll-interval 1m`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		viper.BindPFlags(cmd.Flags())
+
+		gasLimit, err := strconv.ParseUint(viper.GetString(watchGasLimitF), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse gas limit: %s", err)
+		}
+
+		pollInterval, err := time.ParseDuration(viper.GetString(watchPollIntervalF))
+		if err != nil {
+			return fmt.Errorf("failed to parse poll interval: %s", err)
+		} else if pollInterval <= 0 {
+			return fmt.Errorf("poll interval must be positive")
+		}
+
+		signer, err := ks.GetSigner(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to retrieve account: %s", err)
+		}
+		if err := signer.Unlock(); err != nil {
+			return fmt.Errorf("failed to unlock account: %s", err)
+		}
+
+		cmd.SilenceUsage = true
+
+		plasmaContract, err := config.GetContractConn()
+		if err != nil {
+			return err
+		}
+
+		transactOpts := ks.TransactOpts(signer, gasLimit)
+
+		w := newWatchtower(context.NewCLIContext(), plasmaContract, transactOpts, viper.GetUint64(watchStartBlockF))
+
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+		fmt.Printf("Watching for exits as 0x%x\n", signer.Address())
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			if err := w.poll(); err != nil {
+				fmt.Printf("Error: %s\n", err)
+			}
+
+			select {
+			case <-sigs:
+				w.printSummary()
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
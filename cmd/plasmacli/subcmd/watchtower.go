@@ -0,0 +1,322 @@
+package subcmd
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/client"
+	"github.com/FourthState/plasma-mvp-sidechain/eth"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// exit states of the rootchain contract
+const (
+	exitNonExistent uint8 = iota
+	exitPending
+	exitChallenged
+	exitFinalized
+)
+
+// progress of a watched exit
+const (
+	watchPending     = "Pending"     // the exit has not been challenged yet
+	watchChallenging = "Challenging" // a challenge has been sent and is waiting to be mined
+	watchSucceeded   = "Succeeded"   // the exit was challenged by the watchtower
+	watchClosed      = "Closed"      // the exit was finalized or challenged by another party
+)
+
+// watchedExit tracks an exit and the challenges sent against it
+type watchedExit struct {
+	position     plasma.Position
+	owner        common.Address
+	committedFee *big.Int
+
+	status      string
+	challengeTx common.Hash // latest challenge sent
+	attempts    int         // number of challenges sent
+	lastError   string
+}
+
+// watchtower challenges exits of positions that have been spent on the sidechain
+type watchtower struct {
+	ctx    context.CLIContext
+	plasma *eth.Plasma
+	opts   *bind.TransactOpts
+
+	// next ethereum block to look for exits in
+	nextBlock uint64
+	// position -> exit. `order` holds the positions in the order the exits started
+	exits map[string]*watchedExit
+	order []string
+}
+
+func newWatchtower(ctx context.CLIContext, plasmaContract *eth.Plasma, opts *bind.TransactOpts, startBlock uint64) *watchtower {
+	return &watchtower{
+		ctx:       ctx,
+		plasma:    plasmaContract,
+		opts:      opts,
+		nextBlock: startBlock,
+		exits:     make(map[string]*watchedExit),
+	}
+}
+
+// poll picks up the exits started since the last poll and makes progress on
+// every exit that is still pending
+func (w *watchtower) poll() error {
+	if err := w.syncExits(); err != nil {
+		return fmt.Errorf("failed to retrieve exits: %s", err)
+	}
+
+	for _, key := range w.order {
+		exit := w.exits[key]
+		if exit.status == watchSucceeded || exit.status == watchClosed {
+			continue
+		}
+
+		if err := w.process(exit); err != nil {
+			exit.lastError = err.Error()
+		}
+	}
+
+	return nil
+}
+
+// syncExits adds every exit started between the next block and the latest ethereum block
+func (w *watchtower) syncExits() error {
+	latestBlock, err := w.plasma.Client().LatestBlockNum()
+	if err != nil {
+		return err
+	}
+
+	end := latestBlock.Uint64()
+	if w.nextBlock > end {
+		return nil
+	}
+	opts := &bind.FilterOpts{Start: w.nextBlock, End: &end}
+
+	depositExits, err := w.plasma.FilterStartedDepositExit(opts)
+	if err != nil {
+		return err
+	}
+	for depositExits.Next() {
+		e := depositExits.Event
+		w.addExit(plasma.NewPosition(nil, 0, 0, e.Nonce), e.Owner, e.CommittedFee)
+	}
+	depositExits.Close()
+	if err := depositExits.Error(); err != nil {
+		return err
+	}
+
+	txExits, err := w.plasma.FilterStartedTransactionExit(opts)
+	if err != nil {
+		return err
+	}
+	for txExits.Next() {
+		e := txExits.Event
+		position := plasma.NewPosition(e.Position[0], uint16(e.Position[1].Uint64()), uint8(e.Position[2].Uint64()), nil)
+		w.addExit(position, e.Owner, e.CommittedFee)
+	}
+	txExits.Close()
+	if err := txExits.Error(); err != nil {
+		return err
+	}
+
+	w.nextBlock = end + 1
+	return nil
+}
+
+// addExit starts watching the exit of `position`. A position that is exited
+// again after a fee mismatch challenge is watched again
+func (w *watchtower) addExit(position plasma.Position, owner common.Address, committedFee *big.Int) {
+	key := position.String()
+	if exit, ok := w.exits[key]; ok {
+		if exit.status == watchPending || exit.status == watchChallenging {
+			return
+		}
+	} else {
+		w.order = append(w.order, key)
+	}
+
+	fmt.Printf("Exit started: position %s, owner 0x%x, committed fee %s\n", position, owner, committedFee)
+	w.exits[key] = &watchedExit{
+		position:     position,
+		owner:        owner,
+		committedFee: committedFee,
+		status:       watchPending,
+	}
+}
+
+// process checks on the challenge of `exit` and challenges the exit if it is
+// pending and its position has been spent
+func (w *watchtower) process(exit *watchedExit) error {
+	if exit.status == watchChallenging {
+		receipt, err := w.plasma.Client().TransactionReceipt(exit.challengeTx)
+		if err != nil || receipt == nil {
+			return err
+		}
+
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			exit.status = watchSucceeded
+			exit.lastError = ""
+			fmt.Printf("Challenge succeeded: position %s, tx 0x%x\n", exit.position, exit.challengeTx)
+			return nil
+		}
+
+		// the exit may have been challenged or finalized before the challenge was mined
+		exit.status = watchPending
+		fmt.Printf("Challenge reverted: position %s, tx 0x%x\n", exit.position, exit.challengeTx)
+	}
+
+	state, err := w.exitState(exit.position)
+	if err != nil {
+		return err
+	}
+	if state != exitPending {
+		exit.status = watchClosed
+		fmt.Printf("Exit closed: position %s, state %s\n", exit.position, exitStateString(state))
+		return nil
+	}
+
+	challengingPos, txBytes, proof, confirmSignature, err := w.findChallenge(exit)
+	if err != nil {
+		return err
+	}
+
+	exitPos := exit.position.ToBigIntArray()
+	challengePos := [2]*big.Int{challengingPos.BlockNum, big.NewInt(int64(challengingPos.TxIndex))}
+	tx, err := w.plasma.ChallengeExit(w.opts, exitPos, challengePos, txBytes, proof, confirmSignature)
+	if err != nil {
+		return fmt.Errorf("failed to send challenge transaction: %s", err)
+	}
+
+	exit.status = watchChallenging
+	exit.challengeTx = tx.Hash()
+	exit.attempts++
+	exit.lastError = ""
+	fmt.Printf("Challenge sent: position %s, challenging position %s, tx 0x%x\n", exit.position, challengingPos, tx.Hash())
+	return nil
+}
+
+// findChallenge looks up the transaction that spent the exited position and
+// returns its position, bytes and inclusion proof along with the confirmation
+// signature of the exit owner if the challenge requires one
+func (w *watchtower) findChallenge(exit *watchedExit) (plasma.Position, []byte, []byte, []byte, error) {
+	// fails until the position has been spent
+	spender, err := client.Spender(w.ctx, exit.position)
+	if err != nil {
+		return plasma.Position{}, nil, nil, nil, err
+	}
+	spenderHash := spender.Transaction.TxHash()
+
+	inputIndex := -1
+	for i, input := range spender.Transaction.Inputs {
+		if input.Position.String() == exit.position.String() {
+			inputIndex = i
+		}
+	}
+	if inputIndex < 0 {
+		return plasma.Position{}, nil, nil, nil, fmt.Errorf("spend 0x%x does not include the position", spenderHash)
+	}
+
+	proof, err := client.TxProof(w.ctx, spender.Position)
+	if err != nil {
+		return plasma.Position{}, nil, nil, nil, err
+	}
+
+	// the contract verifies the signature of the spend instead of a confirmation
+	// signature when the first input committed to an incorrect fee
+	if inputIndex == 0 && exit.committedFee.Cmp(spender.Transaction.Fee) != 0 {
+		return spender.Position, proof.TxBytes, proof.Proof, nil, nil
+	}
+
+	confirmSignature, err := w.confirmSignature(spender, inputIndex)
+	if err != nil {
+		return plasma.Position{}, nil, nil, nil, err
+	}
+
+	return spender.Position, proof.TxBytes, proof.Proof, confirmSignature, nil
+}
+
+// confirmSignature searches the transactions spending the outputs of `tx` for
+// the confirmation signature of the input at `inputIndex`
+func (w *watchtower) confirmSignature(tx store.Transaction, inputIndex int) ([]byte, error) {
+	for _, hash := range tx.SpenderTxs {
+		if len(hash) == 0 {
+			continue
+		}
+
+		spender, err := client.Tx(w.ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, input := range spender.Transaction.Inputs {
+			if input.Position.IsDeposit() || input.Position.BlockNum.Cmp(tx.Position.BlockNum) != 0 ||
+				input.Position.TxIndex != tx.Position.TxIndex {
+				continue
+			}
+
+			if len(input.ConfirmSignatures) > inputIndex {
+				return input.ConfirmSignatures[inputIndex][:], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no confirmation signature of the spend is available")
+}
+
+// exitState retrieves the state of the exit of `position` from the contract
+func (w *watchtower) exitState(position plasma.Position) (uint8, error) {
+	var exit struct {
+		Amount       *big.Int
+		CommittedFee *big.Int
+		CreatedAt    *big.Int
+		EthBlockNum  *big.Int
+		Owner        common.Address
+		State        uint8
+	}
+
+	var err error
+	if position.IsDeposit() {
+		exit, err = w.plasma.DepositExits(nil, position.DepositNonce)
+	} else {
+		exit, err = w.plasma.TxExits(nil, position.Priority())
+	}
+
+	return exit.State, err
+}
+
+// printSummary displays every watched exit along with its challenge status
+func (w *watchtower) printSummary() {
+	fmt.Printf("\nWatched %d exits\n", len(w.order))
+	for _, key := range w.order {
+		exit := w.exits[key]
+		fmt.Printf("Position: %s\nOwner: 0x%x\nStatus: %s\nChallenges Sent: %d\n", exit.position, exit.owner, exit.status, exit.attempts)
+		if exit.attempts > 0 {
+			fmt.Printf("Latest Challenge: 0x%x\n", exit.challengeTx)
+		}
+		if exit.lastError != "" {
+			fmt.Printf("Last Error: %s\n", exit.lastError)
+		}
+		fmt.Println()
+	}
+}
+
+func exitStateString(state uint8) string {
+	switch state {
+	case exitNonExistent:
+		return "Nonexistent"
+	case exitPending:
+		return "Pending"
+	case exitChallenged:
+		return "Challenged"
+	case exitFinalized:
+		return "Finalized"
+	default:
+		return "Unknown"
+	}
+}
@@ -21,6 +21,16 @@ ethereum_nodeurl = "{{ .EthNodeURL }}"
 # Number of Ethereum blocks until a submitted block header is considered final
 ethereum_finality = "{{ .EthBlockFinality }}"
 
+# Ethereum block the plasma contract was deployed in. The events of the
+# contract and the times of the ethereum blocks are mirrored from this block
+ethereum_deployment_block = "{{ .EthDeploymentBlock }}"
+
+# Plasma block from which blocks are pegged to the ethereum state as of a
+# minute before their block time, instead of the latest block submission seen
+# by the node. Changes the result of delivering a block, so every validator
+# must set the same height. 0 keeps pegging to the latest block submission
+time_peg_height = "{{ .TimePegHeight }}"
+
 ##### plasma configuration #####
 
 # Plasma block commitment rate. i.e 1m30s, 1m, 1h, etc.
@@ -31,7 +41,76 @@ is_operator = "{{ .IsOperator }}"
 
 # Hex encoded private key
 # Used to sign eth transactions interacting with the contract
-operator_privatekey = "{{ .OperatorPrivateKey }}"`
+operator_privatekey = "{{ .OperatorPrivateKey }}"
+
+# Boolean specifying if the operator should automatically include
+# finalized rootchain deposits into the sidechain
+include_deposits = "{{ .IncludeDeposits }}"
+
+# Boolean specifying if this node, when it is not the operator, checks that
+# the plasma headers committed to the rootchain match the blocks agreed upon
+# by the validators. Mismatches are logged as errors
+verify_headers = "{{ .VerifyHeaders }}"
+
+# Gas price strategy of block submissions. "suggested" uses the gas price
+# suggested by the ethereum node, capped at gas_price when non-zero.
+# "fixed" always uses gas_price
+gas_price_strategy = "{{ .GasPriceStrategy }}"
+
+# Gas price in wei
+gas_price = "{{ .GasPrice }}"
+
+# Percentage increase of the gas price when resubmitting a stuck block
+# submission. Must be at least 10
+gas_price_bump = "{{ .GasPriceBump }}"
+
+# Duration a block submission may stay pending before it is resubmitted
+submission_timeout = "{{ .SubmissionTimeout }}"
+
+# Maximum number of plasma headers committed by a single block submission.
+# A backlog of uncommitted headers is committed in order over several
+# submissions
+max_blocks_per_submission = "{{ .MaxBlocksPerSubmission }}"
+
+# Boolean specifying if this node accepts confirm signatures posted by
+# senders and serves them to recipients. Posted signatures are stored
+# locally and are not shared with other nodes
+confirm_sig_mailbox = "{{ .ConfirmSigMailbox }}"
+
+##### fee configuration #####
+
+# The fee policy is read when the node starts and read again from this file
+# when plasmad receives SIGHUP, so fees are changed without a restart
+
+# Minimum fee in wei of every spend accepted into the mempool of this node
+min_fee = "{{ .MinFee }}"
+
+# Fee in wei charged per byte of the encoded transaction on top of min_fee
+fee_per_byte = "{{ .FeePerByte }}"
+
+# Maximum number of spends from a single address accepted into the mempool
+# of this node between blocks. 0 disables the limit
+max_spends_per_address = "{{ .MaxSpendsPerAddress }}"
+
+# Number of spends accepted into the mempool of this node between blocks after
+# which only spends paying at least fee_floor (in wei) are accepted. Spends
+# already in the mempool are not reordered by fee. 0 disables the fee floor
+fee_floor_threshold = "{{ .FeeFloorThreshold }}"
+fee_floor = "{{ .FeeFloor }}"
+
+# Interval at which the operator consolidates the fee outputs into a single
+# output, i.e 10m, 1h. Fees are only swept while the current fee address is
+# the operator. "0s" disables sweeping
+fee_sweep_interval = "{{ .FeeSweepInterval }}"
+
+# Minimum number of unspent fee outputs before the operator sweeps them
+fee_sweep_threshold = "{{ .FeeSweepThreshold }}"
+
+##### metrics configuration #####
+
+# Address prometheus metrics are served on at /metrics, i.e :26661.
+# Metrics are not served if empty
+prometheus_listen_addr = "{{ .PrometheusListenAddr }}"`
 
 // PlasmaConfig is the object representation of config file. It must match
 // the above defaultConfigTemplate.
@@ -39,10 +118,34 @@ type PlasmaConfig struct {
 	EthPlasmaContractAddr string `mapstructure:"ethereum_plasma_contract_address"`
 	EthNodeURL            string `mapstructure:"ethereum_nodeurl"`
 	EthBlockFinality      string `mapstructure:"ethereum_finality"`
+	EthDeploymentBlock    string `mapstructure:"ethereum_deployment_block"`
+	TimePegHeight         string `mapstructure:"time_peg_height"`
 
 	IsOperator           bool   `mapstructure:"is_operator"`
 	OperatorPrivateKey   string `mapstructure:"operator_privatekey"`
 	PlasmaCommitmentRate string `mapstructure:"block_commitment_rate"`
+	IncludeDeposits      bool   `mapstructure:"include_deposits"`
+	VerifyHeaders        bool   `mapstructure:"verify_headers"`
+
+	GasPriceStrategy       string `mapstructure:"gas_price_strategy"`
+	GasPrice               string `mapstructure:"gas_price"`
+	GasPriceBump           string `mapstructure:"gas_price_bump"`
+	SubmissionTimeout      string `mapstructure:"submission_timeout"`
+	MaxBlocksPerSubmission string `mapstructure:"max_blocks_per_submission"`
+
+	ConfirmSigMailbox bool `mapstructure:"confirm_sig_mailbox"`
+
+	MinFee     string `mapstructure:"min_fee"`
+	FeePerByte string `mapstructure:"fee_per_byte"`
+
+	MaxSpendsPerAddress string `mapstructure:"max_spends_per_address"`
+	FeeFloorThreshold   string `mapstructure:"fee_floor_threshold"`
+	FeeFloor            string `mapstructure:"fee_floor"`
+
+	FeeSweepInterval  string `mapstructure:"fee_sweep_interval"`
+	FeeSweepThreshold string `mapstructure:"fee_sweep_threshold"`
+
+	PrometheusListenAddr string `mapstructure:"prometheus_listen_addr"`
 }
 
 var configTemplate *template.Template
@@ -61,10 +164,34 @@ func DefaultPlasmaConfig() PlasmaConfig {
 		EthPlasmaContractAddr: "",
 		EthNodeURL:            "http://localhost:8545",
 		EthBlockFinality:      "16",
+		EthDeploymentBlock:    "0",
+		TimePegHeight:         "0",
 
 		IsOperator:           false,
 		OperatorPrivateKey:   "",
 		PlasmaCommitmentRate: "1m",
+		IncludeDeposits:      true,
+		VerifyHeaders:        true,
+
+		GasPriceStrategy:       "suggested",
+		GasPrice:               "0",
+		GasPriceBump:           "10",
+		SubmissionTimeout:      "5m",
+		MaxBlocksPerSubmission: "100",
+
+		ConfirmSigMailbox: false,
+
+		MinFee:     "0",
+		FeePerByte: "0",
+
+		MaxSpendsPerAddress: "100",
+		FeeFloorThreshold:   "0",
+		FeeFloor:            "0",
+
+		FeeSweepInterval:  "10m",
+		FeeSweepThreshold: "15",
+
+		PrometheusListenAddr: "",
 	}
 }
 
@@ -76,10 +203,34 @@ func TestPlasmaConfig() PlasmaConfig {
 		EthPlasmaContractAddr: "31E491FC70cDb231774c61B7F46d94699dacE664",
 		EthNodeURL:            "http://localhost:8545",
 		EthBlockFinality:      "0",
+		EthDeploymentBlock:    "0",
+		TimePegHeight:         "1",
 
 		IsOperator:           true,
 		OperatorPrivateKey:   "9cd69f009ac86203e54ec50e3686de95ff6126d3b30a19f926a0fe9323c17181",
 		PlasmaCommitmentRate: "1m",
+		IncludeDeposits:      true,
+		VerifyHeaders:        true,
+
+		GasPriceStrategy:       "suggested",
+		GasPrice:               "0",
+		GasPriceBump:           "10",
+		SubmissionTimeout:      "5m",
+		MaxBlocksPerSubmission: "100",
+
+		ConfirmSigMailbox: false,
+
+		MinFee:     "0",
+		FeePerByte: "0",
+
+		MaxSpendsPerAddress: "100",
+		FeeFloorThreshold:   "0",
+		FeeFloor:            "0",
+
+		FeeSweepInterval:  "10m",
+		FeeSweepThreshold: "15",
+
+		PrometheusListenAddr: "",
 	}
 }
 
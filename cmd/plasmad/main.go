@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/app"
 	"github.com/FourthState/plasma-mvp-sidechain/cmd/plasmad/config"
@@ -12,9 +13,12 @@ import (
 	"github.com/tendermint/tendermint/libs/cli"
 	dbm "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
+	tmtypes "github.com/tendermint/tendermint/types"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 )
 
 var rootDir string = os.ExpandEnv("$HOME/.plasmad")
@@ -31,7 +35,7 @@ func main() {
 		PersistentPreRunE: persistentPreRunEFn(ctx),
 	}
 	rootCmd.AddCommand(subcmd.InitCmd(ctx, cdc))
-	server.AddCommands(ctx, cdc, rootCmd, newApp, nil)
+	server.AddCommands(ctx, cdc, rootCmd, newApp, exportAppStateAndTMValidators)
 
 	executor := cli.PrepareBaseCmd(rootCmd, "PD", rootDir)
 	if err := executor.Execute(); err != nil {
@@ -69,7 +73,88 @@ func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application
 		panic(err)
 	}
 
-	return app.NewPlasmaMVPChain(logger, db, traceStore,
+	options := []func(*app.PlasmaMVPChain){
 		app.SetPlasmaOptionsFromConfig(plasmaConfig),
-	)
+		app.SetTendermintRPCAddress(viper.GetString("rpc.laddr")),
+		app.SetGenesisFile(genesisFile()),
+	}
+	if plasmaConfig.ConfirmSigMailbox {
+		mailboxDB, err := dbm.NewGoLevelDB("mailbox", filepath.Join(viper.GetString(cli.HomeFlag), "data"))
+		if err != nil {
+			panic(err)
+		}
+		options = append(options, app.SetConfirmSigMailbox(mailboxDB))
+	}
+	if plasmaConfig.VerifyHeaders && !plasmaConfig.IsOperator {
+		verifierDB, err := dbm.NewGoLevelDB("verifier", filepath.Join(viper.GetString(cli.HomeFlag), "data"))
+		if err != nil {
+			panic(err)
+		}
+		options = append(options, app.SetHeaderVerifierDB(verifierDB))
+	}
+
+	plasmaApp, err := app.NewPlasmaMVPChain(logger, db, traceStore, options...)
+	if err != nil {
+		panic(err)
+	}
+	go reloadFeePolicy(plasmaApp, logger)
+
+	return plasmaApp
+}
+
+// reloadFeePolicy reads the fee policy from plasma.toml again every time the
+// process receives SIGHUP. An invalid policy is logged and the current one is kept
+func reloadFeePolicy(plasmaApp *app.PlasmaMVPChain, logger log.Logger) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	for range sighup {
+		if err := viper.MergeInConfig(); err != nil {
+			logger.Error(fmt.Sprintf("error reading plasma.toml: %s", err))
+			continue
+		}
+		plasmaConfig, err := config.ParsePlasmaConfigFromViper()
+		if err != nil {
+			logger.Error(fmt.Sprintf("error parsing plasma.toml: %s", err))
+			continue
+		}
+		policy, err := app.FeePolicyFromConfig(plasmaConfig)
+		if err != nil {
+			logger.Error(fmt.Sprintf("fee policy not reloaded: %s", err))
+			continue
+		}
+
+		plasmaApp.SetFeePolicy(policy)
+	}
+}
+
+// genesisFile returns the path of the genesis file set in config.toml
+func genesisFile() string {
+	file := viper.GetString("genesis_file")
+	if file == "" {
+		file = "config/genesis.json"
+	}
+	if filepath.IsAbs(file) {
+		return file
+	}
+
+	return filepath.Join(viper.GetString(cli.HomeFlag), file)
+}
+
+// the state is exported from the stores alone, so neither the ethereum node nor
+// the plasma config are needed
+func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB, traceStore io.Writer, height int64, forZeroHeight bool, jailWhiteList []string) (json.RawMessage, []tmtypes.GenesisValidator, error) {
+	plasmaApp, err := app.NewPlasmaMVPChain(logger, db, traceStore, app.SetStoreOnly())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer plasmaApp.Stop()
+
+	if height != -1 {
+		if err := plasmaApp.LoadHeight(height); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return plasmaApp.ExportAppStateJSON()
 }
@@ -23,6 +23,8 @@ const (
 	flagMoniker   = "moniker"
 	flagChainID   = "chainId"
 	flagTest      = "test"
+	flagFeeAddr   = "fee-address"
+	flagOperator  = "operator"
 )
 
 type chainInfo struct {
@@ -37,8 +39,9 @@ func InitCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize private validator, p2p, genesis, and application configuration files",
-		Long:  `Initialize validators's and node's configuration files.`,
-		Args:  cobra.NoArgs,
+		Long: `Initialize validators's and node's configuration files.
+The fee address and the operator must be set in genesis.json before the chain is started, if not given with --fee-address and --operator.`,
+		Args: cobra.NoArgs,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			config := ctx.Config
 			config.SetRoot(viper.GetString(cli.HomeFlag))
@@ -82,7 +85,7 @@ func InitCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 			valPubKey := privValidator.GetPubKey()
 
 			// create genesis and write to disk
-			appState, err = codec.MarshalJSONIndent(cdc, app.NewDefaultGenesisState(valPubKey))
+			appState, err = codec.MarshalJSONIndent(cdc, app.NewDefaultGenesisState(valPubKey, viper.GetString(flagFeeAddr), viper.GetString(flagOperator)))
 			if err != nil {
 				return err
 			}
@@ -131,5 +134,7 @@ func InitCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
 	cmd.Flags().BoolP(flagTest, "t", false, "write default testing configuration")
 	cmd.Flags().String(flagChainID, "", "genesis file chain-id, if left blank will be randomly created")
 	cmd.Flags().String(flagMoniker, "m", "set the validator's moniker")
+	cmd.Flags().String(flagFeeAddr, "", "ethereum address the fees of each block are collected to")
+	cmd.Flags().String(flagOperator, "", "ethereum address authorized to update the validator set, usually the operator of the rootchain contract")
 	return cmd
 }
@@ -0,0 +1,586 @@
+package eth
+
+import (
+	"fmt"
+	contracts "github.com/FourthState/plasma-mvp-sidechain/contracts/wrappers"
+	"github.com/FourthState/plasma-mvp-sidechain/metrics"
+	plasmaTypes "github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maximum number of ethereum blocks mirrored per round of a sync
+const syncBatchSize = 1000
+
+// rootchainCache mirrors the deposit, exit and block submission state of the
+// plasma contract, along with the time every ethereum block since its
+// deployment was mined. The mirror is fed by the contract's event logs and
+// every entry is indexed by the ethereum block it occurred in so that the
+// state at any pegged ethereum block can be answered without a network round
+// trip.
+type rootchainCache struct {
+	contract *contracts.PlasmaMVP
+	client   Client
+	// ethereum block the contract was deployed in. Earlier blocks are not mirrored
+	deploymentBlock uint64
+
+	// mirrored events of blocks replaced by a reorg are dropped and mirrored again
+	reorgs     *reorgTracker
+	reorgsSeen int // only accessed by sync
+
+	mtx sync.RWMutex
+	// last ethereum block whose events have been mirrored. nil if nothing has been synced
+	syncedBlock *big.Int
+	// local time the latest sync started at, if it mirrored every block up to the latest
+	// ethereum block. The zero time while the mirror is catching up
+	syncedAt time.Time
+	// time each ethereum block from the deployment block up to the synced block was mined
+	blockTimes []uint64
+	// deposit nonce -> deposit
+	deposits map[string]plasmaTypes.Deposit
+	// exit position -> state transitions in the order they occurred
+	exits map[string][]exitTransition
+	// plasma block number -> ethereum block the header was submitted in
+	submissions        map[string]*big.Int
+	lastCommittedBlock *big.Int
+	// operator of the contract. The zero address until the first successful sync
+	operator common.Address
+	// set by a rewind until the operator is retrieved again. The operator is
+	// kept meanwhile since the contract rarely changes it
+	operatorStale bool
+	// error of the latest sync. The mirror is stale while set
+	syncErr error
+
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// exitTransition records the exit state of a position starting at `ethBlockNum`
+type exitTransition struct {
+	ethBlockNum *big.Int
+	exited      bool
+}
+
+// rootchainEvent is a log paired with the update it applies to the cache
+type rootchainEvent struct {
+	log   types.Log
+	apply func()
+}
+
+func newRootchainCache(contract *contracts.PlasmaMVP, client Client, reorgs *reorgTracker, deploymentBlock uint64) *rootchainCache {
+	return &rootchainCache{
+		contract:           contract,
+		client:             client,
+		deploymentBlock:    deploymentBlock,
+		reorgs:             reorgs,
+		deposits:           make(map[string]plasmaTypes.Deposit),
+		exits:              make(map[string][]exitTransition),
+		submissions:        make(map[string]*big.Int),
+		lastCommittedBlock: big.NewInt(0),
+		quit:               make(chan struct{}),
+		done:               make(chan struct{}),
+	}
+}
+
+// start keeps the cache synced with the rootchain in a separate goroutine
+func (cache *rootchainCache) start(pollInterval time.Duration) {
+	go func() {
+		defer close(cache.done)
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cache.quit:
+				return
+			case <-ticker.C:
+				if err := cache.sync(); err != nil {
+					logger.Error(fmt.Sprintf("error syncing the rootchain cache: %s", err))
+				}
+				if err := cache.reportExitQueues(); err != nil {
+					logger.Error(fmt.Sprintf("error retrieving the exit queue lengths: %s", err))
+				}
+			}
+		}
+	}()
+}
+
+// stop halts the sync goroutine
+func (cache *rootchainCache) stop() {
+	cache.stopOnce.Do(func() {
+		close(cache.quit)
+	})
+	<-cache.done
+}
+
+// sync mirrors all events that occurred after the last synced block up to the latest ethereum block.
+// The mirror is considered stale until a sync succeeds
+func (cache *rootchainCache) sync() error {
+	err := cache.syncEvents()
+
+	cache.mtx.Lock()
+	cache.syncErr = err
+	cache.mtx.Unlock()
+
+	return err
+}
+
+func (cache *rootchainCache) syncEvents() error {
+	// any block mined after the sync started has a later time
+	syncStart := time.Now()
+	latestBlock, err := cache.client.LatestBlockNum()
+	if err != nil {
+		return err
+	}
+
+	if _, err := cache.reorgs.check(latestBlock); err != nil {
+		return fmt.Errorf("checking for rootchain reorgs: %s", err)
+	}
+	reorgs, seen := cache.reorgs.reorgsSince(cache.reorgsSeen)
+	if len(reorgs) > 0 {
+		cache.mtx.Lock()
+		for _, reorg := range reorgs {
+			cache.rewind(reorg.BlockNum)
+		}
+		cache.mtx.Unlock()
+	}
+	cache.reorgsSeen = seen
+
+	cache.mtx.RLock()
+	operator := cache.operator
+	refreshOperator := utils.IsZeroAddress(operator) || cache.operatorStale
+	cache.mtx.RUnlock()
+
+	// later changes are mirrored from the contract events
+	if refreshOperator {
+		if operator, err = cache.contract.Operator(nil); err != nil {
+			return err
+		}
+
+		cache.mtx.Lock()
+		cache.operator = operator
+		cache.operatorStale = false
+		cache.mtx.Unlock()
+	}
+
+	// the blocks are mirrored in batches so that a large range is not lost to a single failure
+	end := latestBlock.Uint64()
+	for {
+		cache.mtx.RLock()
+		start := cache.deploymentBlock
+		if cache.syncedBlock != nil {
+			start = cache.syncedBlock.Uint64() + 1
+		}
+		cache.mtx.RUnlock()
+
+		if start > end {
+			break
+		}
+		batchEnd := end
+		if batchEnd-start >= syncBatchSize {
+			batchEnd = start + syncBatchSize - 1
+		}
+
+		if err := cache.syncBatch(start, batchEnd); err != nil {
+			return err
+		}
+	}
+
+	cache.mtx.Lock()
+	cache.syncedAt = syncStart
+	metrics.LastCommittedBlock.Set(float64(cache.lastCommittedBlock.Int64()))
+	cache.mtx.Unlock()
+
+	return nil
+}
+
+// syncBatch mirrors the events and block times of the ethereum blocks within [start, end]
+func (cache *rootchainCache) syncBatch(start, end uint64) error {
+	events, err := cache.filterEvents(&bind.FilterOpts{Start: start, End: &end})
+	if err != nil {
+		return err
+	}
+
+	var blockTimes []uint64
+	for num := start; num <= end; num++ {
+		header, err := cache.client.HeaderByNumber(new(big.Int).SetUint64(num))
+		if err != nil {
+			return err
+		} else if header == nil {
+			return fmt.Errorf("ethereum block %d does not exist", num)
+		}
+		blockTimes = append(blockTimes, header.Time)
+	}
+
+	// apply the events in the order they occurred on the rootchain
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].log.BlockNumber != events[j].log.BlockNumber {
+			return events[i].log.BlockNumber < events[j].log.BlockNumber
+		}
+		return events[i].log.Index < events[j].log.Index
+	})
+
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+	for _, event := range events {
+		event.apply()
+	}
+	cache.blockTimes = append(cache.blockTimes, blockTimes...)
+	cache.syncedBlock = new(big.Int).SetUint64(end)
+
+	return nil
+}
+
+// rewind drops the mirrored state of the ethereum blocks starting at `ethBlockNum`
+// so that it is mirrored again from the canonical chain. The operator is retrieved
+// again by the next sync and the previous one is served until then. Must be
+// called while holding the write lock
+func (cache *rootchainCache) rewind(ethBlockNum *big.Int) {
+	if cache.syncedBlock == nil || cache.syncedBlock.Cmp(ethBlockNum) < 0 {
+		return
+	}
+
+	for key, deposit := range cache.deposits {
+		if deposit.EthBlockNum.Cmp(ethBlockNum) >= 0 {
+			delete(cache.deposits, key)
+		}
+	}
+
+	for key, transitions := range cache.exits {
+		i := sort.Search(len(transitions), func(i int) bool {
+			return transitions[i].ethBlockNum.Cmp(ethBlockNum) >= 0
+		})
+		if i == 0 {
+			delete(cache.exits, key)
+		} else {
+			cache.exits[key] = transitions[:i]
+		}
+	}
+
+	cache.lastCommittedBlock = big.NewInt(0)
+	for key, submittedAt := range cache.submissions {
+		if submittedAt.Cmp(ethBlockNum) >= 0 {
+			delete(cache.submissions, key)
+			continue
+		}
+
+		blockNum, _ := new(big.Int).SetString(key, 10)
+		if blockNum.Cmp(cache.lastCommittedBlock) > 0 {
+			cache.lastCommittedBlock = blockNum
+		}
+	}
+
+	cache.operatorStale = true
+	cache.syncedAt = time.Time{}
+	if ethBlockNum.Uint64() <= cache.deploymentBlock {
+		cache.syncedBlock = nil
+		cache.blockTimes = nil
+	} else {
+		cache.syncedBlock = new(big.Int).Sub(ethBlockNum, utils.Big1)
+		cache.blockTimes = cache.blockTimes[:ethBlockNum.Uint64()-cache.deploymentBlock]
+	}
+}
+
+// reportExitQueues records the number of exits in the queues of the contract
+func (cache *rootchainCache) reportExitQueues() error {
+	depositQueue, err := cache.contract.DepositQueueLength(nil)
+	if err != nil {
+		return err
+	}
+	txQueue, err := cache.contract.TxQueueLength(nil)
+	if err != nil {
+		return err
+	}
+
+	metrics.ExitQueueLength.WithLabelValues("deposit").Set(float64(depositQueue.Int64()))
+	metrics.ExitQueueLength.WithLabelValues("transaction").Set(float64(txQueue.Int64()))
+	return nil
+}
+
+// filterEvents retrieves all contract events relevant to the cache within the range of `opts`.
+// The returned updates must be applied while holding the write lock
+func (cache *rootchainCache) filterEvents(opts *bind.FilterOpts) ([]rootchainEvent, error) {
+	var events []rootchainEvent
+
+	deposits, err := cache.contract.FilterDeposit(opts)
+	if err != nil {
+		return nil, err
+	}
+	for deposits.Next() {
+		e := deposits.Event
+		events = append(events, rootchainEvent{e.Raw, func() {
+			cache.deposits[e.DepositNonce.String()] = plasmaTypes.NewDeposit(e.Depositor, e.Amount, e.EthBlockNum)
+		}})
+	}
+	deposits.Close()
+	if err := deposits.Error(); err != nil {
+		return nil, err
+	}
+
+	blocks, err := cache.contract.FilterBlockSubmitted(opts)
+	if err != nil {
+		return nil, err
+	}
+	for blocks.Next() {
+		e := blocks.Event
+		events = append(events, rootchainEvent{e.Raw, func() {
+			cache.submissions[e.BlockNumber.String()] = new(big.Int).SetUint64(e.Raw.BlockNumber)
+			if e.BlockNumber.Cmp(cache.lastCommittedBlock) > 0 {
+				cache.lastCommittedBlock = e.BlockNumber
+			}
+		}})
+	}
+	blocks.Close()
+	if err := blocks.Error(); err != nil {
+		return nil, err
+	}
+
+	operators, err := cache.contract.FilterChangedOperator(opts)
+	if err != nil {
+		return nil, err
+	}
+	for operators.Next() {
+		e := operators.Event
+		events = append(events, rootchainEvent{e.Raw, func() {
+			cache.operator = e.NewOperator
+		}})
+	}
+	operators.Close()
+	if err := operators.Error(); err != nil {
+		return nil, err
+	}
+
+	depositExits, err := cache.contract.FilterStartedDepositExit(opts)
+	if err != nil {
+		return nil, err
+	}
+	for depositExits.Next() {
+		e := depositExits.Event
+		position := plasmaTypes.NewPosition(nil, 0, 0, e.Nonce)
+		events = append(events, cache.exitEvent(e.Raw, position, true))
+	}
+	depositExits.Close()
+	if err := depositExits.Error(); err != nil {
+		return nil, err
+	}
+
+	txExits, err := cache.contract.FilterStartedTransactionExit(opts)
+	if err != nil {
+		return nil, err
+	}
+	for txExits.Next() {
+		e := txExits.Event
+		position := plasmaTypes.NewPosition(e.Position[0], uint16(e.Position[1].Uint64()), uint8(e.Position[2].Uint64()), nil)
+		events = append(events, cache.exitEvent(e.Raw, position, true))
+	}
+	txExits.Close()
+	if err := txExits.Error(); err != nil {
+		return nil, err
+	}
+
+	// a challenged exit is either reset or can never be reopened. In both cases the position has not exited
+	challenges, err := cache.contract.FilterChallengedExit(opts)
+	if err != nil {
+		return nil, err
+	}
+	for challenges.Next() {
+		e := challenges.Event
+		position := plasmaTypes.NewPosition(e.Position[0], uint16(e.Position[1].Uint64()), uint8(e.Position[2].Uint64()), e.Position[3])
+		events = append(events, cache.exitEvent(e.Raw, position, false))
+	}
+	challenges.Close()
+	if err := challenges.Error(); err != nil {
+		return nil, err
+	}
+
+	finalized, err := cache.contract.FilterFinalizedExit(opts)
+	if err != nil {
+		return nil, err
+	}
+	for finalized.Next() {
+		e := finalized.Event
+		position := plasmaTypes.NewPosition(e.Position[0], uint16(e.Position[1].Uint64()), uint8(e.Position[2].Uint64()), e.Position[3])
+		events = append(events, cache.exitEvent(e.Raw, position, true))
+	}
+	finalized.Close()
+	if err := finalized.Error(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (cache *rootchainCache) exitEvent(log types.Log, position plasmaTypes.Position, exited bool) rootchainEvent {
+	return rootchainEvent{log, func() {
+		key := position.String()
+		cache.exits[key] = append(cache.exits[key], exitTransition{new(big.Int).SetUint64(log.BlockNumber), exited})
+	}}
+}
+
+// available reports if the latest sync succeeded
+func (cache *rootchainCache) available() bool {
+	cache.mtx.RLock()
+	defer cache.mtx.RUnlock()
+
+	return cache.syncedBlock != nil && cache.syncErr == nil
+}
+
+// getOperator returns the mirrored operator of the contract
+func (cache *rootchainCache) getOperator() (common.Address, error) {
+	cache.mtx.RLock()
+	defer cache.mtx.RUnlock()
+
+	if cache.syncedBlock == nil {
+		return common.Address{}, fmt.Errorf("rootchain cache has not been synced")
+	} else if utils.IsZeroAddress(cache.operator) {
+		return common.Address{}, fmt.Errorf("operator of the contract has not been mirrored")
+	}
+
+	return cache.operator, nil
+}
+
+// getDeposit returns the mirrored deposit with `nonce`. An error is returned until the mirror has been
+// synced up to the pegged `ethBlockNum`
+func (cache *rootchainCache) getDeposit(ethBlockNum *big.Int, nonce *big.Int) (plasmaTypes.Deposit, bool, error) {
+	cache.mtx.RLock()
+	defer cache.mtx.RUnlock()
+
+	if err := cache.checkSynced(ethBlockNum); err != nil {
+		return plasmaTypes.Deposit{}, false, err
+	}
+
+	deposit, ok := cache.deposits[nonce.String()]
+	return deposit, ok, nil
+}
+
+// hasExited reports if `position` was in an exited state at `ethBlockNum`. If nil, the latest mirrored
+// state is used. An error is returned until the mirror has been synced up to `ethBlockNum`
+func (cache *rootchainCache) hasExited(ethBlockNum *big.Int, position plasmaTypes.Position) (bool, error) {
+	cache.mtx.RLock()
+	defer cache.mtx.RUnlock()
+
+	if ethBlockNum != nil {
+		if err := cache.checkSynced(ethBlockNum); err != nil {
+			return false, err
+		}
+	} else if cache.syncedBlock == nil {
+		return false, fmt.Errorf("rootchain cache has not been synced")
+	}
+
+	exited := false
+	for _, transition := range cache.exits[position.String()] {
+		if ethBlockNum != nil && transition.ethBlockNum.Cmp(ethBlockNum) > 0 {
+			break
+		}
+		exited = transition.exited
+	}
+
+	return exited, nil
+}
+
+// legacyPeg mirrors `Plasma.legacyPeg` using the synced state
+func (cache *rootchainCache) legacyPeg(plasmaBlockHeight *big.Int) (*big.Int, error) {
+	cache.mtx.RLock()
+	defer cache.mtx.RUnlock()
+
+	if cache.syncedBlock == nil {
+		return nil, fmt.Errorf("rootchain cache has not been synced")
+	}
+
+	// If no blocks submitted, use the latest block as peg
+	if cache.lastCommittedBlock.Sign() == 0 {
+		return new(big.Int).Set(cache.syncedBlock), nil
+	}
+
+	blockIndex := cache.lastCommittedBlock
+	prevBlock := new(big.Int).Sub(plasmaBlockHeight, big.NewInt(1))
+	if blockIndex.Cmp(prevBlock) == 1 {
+		blockIndex = prevBlock
+	}
+	if blockIndex.Sign() <= 0 {
+		return big.NewInt(0), nil
+	}
+
+	ethBlockNum, ok := cache.submissions[blockIndex.String()]
+	if !ok {
+		return nil, fmt.Errorf("submission of plasma block %s has not been synced", blockIndex)
+	}
+
+	return ethBlockNum, nil
+}
+
+// timePeg returns the ethereum block in which the latest plasma block before `plasmaBlockHeight` was
+// submitted, among the submissions mined at or before the block mined `pegDelay` before `blockTime`.
+// That block is the peg if there are no such submissions. An error is returned until the mirror can
+// resolve the block mined `pegDelay` before `blockTime`
+func (cache *rootchainCache) timePeg(plasmaBlockHeight *big.Int, blockTime time.Time) (*big.Int, error) {
+	cache.mtx.RLock()
+	defer cache.mtx.RUnlock()
+
+	bound, err := cache.blockAt(blockTime.Add(-pegDelay))
+	if err != nil {
+		return nil, err
+	}
+
+	blockIndex := cache.lastCommittedBlock
+	prevBlock := new(big.Int).Sub(plasmaBlockHeight, big.NewInt(1))
+	if blockIndex.Cmp(prevBlock) == 1 {
+		blockIndex = prevBlock
+	}
+
+	ethBlockNum, err := latestSubmission(blockIndex, bound, func(blockNum *big.Int) (*big.Int, error) {
+		ethBlockNum, ok := cache.submissions[blockNum.String()]
+		if !ok {
+			return nil, fmt.Errorf("submission of plasma block %s has not been synced", blockNum)
+		}
+
+		return ethBlockNum, nil
+	})
+	if err != nil || ethBlockNum != nil {
+		return ethBlockNum, err
+	}
+
+	return bound, nil
+}
+
+// blockAt returns the latest mirrored ethereum block mined at or before `t`. The block is only
+// resolved once the mirror holds a block mined after `t`, or has been synced up to the latest
+// ethereum block `pegDelay` after `t`, so that the result does not depend on how far the mirror
+// has synced. Blocks mined before the deployment of the contract hold no rootchain state and
+// resolve to the deployment block. Must be called while holding the lock
+func (cache *rootchainCache) blockAt(t time.Time) (*big.Int, error) {
+	if cache.syncedBlock == nil {
+		return nil, fmt.Errorf("rootchain cache has not been synced")
+	}
+
+	i := sort.Search(len(cache.blockTimes), func(i int) bool {
+		return cache.blockTimes[i] > uint64(t.Unix())
+	})
+	if i == len(cache.blockTimes) && cache.syncedAt.Before(t.Add(pegDelay)) {
+		return nil, fmt.Errorf("no ethereum block mined after %s has been mirrored", t.UTC())
+	}
+
+	if i == 0 {
+		return new(big.Int).SetUint64(cache.deploymentBlock), nil
+	}
+	return new(big.Int).SetUint64(cache.deploymentBlock + uint64(i) - 1), nil
+}
+
+// checkSynced returns an error if the events of `ethBlockNum` have not been mirrored.
+// Must be called while holding the lock
+func (cache *rootchainCache) checkSynced(ethBlockNum *big.Int) error {
+	if cache.syncedBlock == nil {
+		return fmt.Errorf("rootchain cache has not been synced")
+	} else if cache.syncedBlock.Cmp(ethBlockNum) < 0 {
+		return fmt.Errorf("rootchain cache has only been synced up to ethereum block %s. ethereum block %s is required", cache.syncedBlock, ethBlockNum)
+	}
+
+	return nil
+}
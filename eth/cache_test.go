@@ -0,0 +1,81 @@
+package eth
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// The cached rootchain state must match the state queried from the contract
+func TestRootchainCache(t *testing.T) {
+	client, _ := InitEthConn(clientAddr)
+	privKey, _ := crypto.HexToECDSA(operatorPrivKey)
+	plasmaContract, _ := InitPlasma(common.HexToAddress(plasmaContractAddr), client, 0)
+	plasmaContract, _ = plasmaContract.WithOperatorSession(privKey, commitmentRate)
+
+	cachedContract, _ := InitPlasma(common.HexToAddress(plasmaContractAddr), client, 0)
+	cachedContract, err := cachedContract.WithCache(time.Hour)
+	require.NoError(t, err, "error syncing the cache")
+	defer cachedContract.StopCache()
+
+	nonce, err := plasmaContract.DepositNonce(nil)
+	require.NoError(t, err, "error querying for the deposit nonce")
+
+	plasmaContract.operatorSession.TransactOpts.Value = big.NewInt(10)
+	operatorAddress := crypto.PubkeyToAddress(privKey.PublicKey)
+	_, err = plasmaContract.operatorSession.Deposit(operatorAddress)
+	require.NoError(t, err, "error sending a deposit tx")
+	plasmaContract.operatorSession.TransactOpts.Value = nil
+	time.Sleep(1 * time.Second)
+	blockTime := pegToLatestBlock(t, client)
+
+	// deposit is unknown until synced
+	_, _, ok, err := cachedContract.GetDeposit(big.NewInt(1), blockTime, nonce)
+	require.Error(t, err, "retrieved a deposit that has not been synced")
+	require.False(t, ok, "retrieved a deposit that has not been synced")
+
+	require.NoError(t, cachedContract.cache.sync())
+
+	lastCommittedBlock, err := plasmaContract.LastCommittedBlock(nil)
+	require.NoError(t, err)
+	height := new(big.Int).Add(lastCommittedBlock, big.NewInt(1))
+
+	expectedPeg, err := plasmaContract.ethBlockPeg(height, blockTime)
+	require.NoError(t, err)
+	peg, err := cachedContract.ethBlockPeg(height, blockTime)
+	require.NoError(t, err)
+	require.Equal(t, expectedPeg, peg, "mismatch in pegged ethereum block")
+
+	expectedDeposit, expectedThreshold, expectedOk, err := plasmaContract.GetDeposit(height, blockTime, nonce)
+	require.NoError(t, err)
+	deposit, threshold, ok, err := cachedContract.GetDeposit(height, blockTime, nonce)
+	require.NoError(t, err)
+	require.Equal(t, expectedOk, ok, "mismatch in deposit finality")
+	require.Equal(t, expectedThreshold, threshold, "mismatch in finality threshold")
+	require.Equal(t, expectedDeposit, deposit, "mismatch in deposit")
+
+	position := plasma.NewPosition(nil, 0, 0, nonce)
+	exited, err := cachedContract.HasTxExited(nil, time.Time{}, position)
+	require.NoError(t, err)
+	require.False(t, exited, "deposit has not exited")
+
+	// exit the deposit
+	plasmaContract.operatorSession.TransactOpts.Value = big.NewInt(minExitBond)
+	_, err = plasmaContract.operatorSession.StartDepositExit(nonce, big.NewInt(0))
+	require.NoError(t, err, "error starting a deposit exit")
+	plasmaContract.operatorSession.TransactOpts.Value = nil
+	time.Sleep(1 * time.Second)
+
+	require.NoError(t, cachedContract.cache.sync())
+
+	expectedExited, err := plasmaContract.HasTxExited(nil, time.Time{}, position)
+	require.NoError(t, err)
+	exited, err = cachedContract.HasTxExited(nil, time.Time{}, position)
+	require.NoError(t, err)
+	require.True(t, expectedExited, "deposit exit not started")
+	require.Equal(t, expectedExited, exited, "mismatch in exit state")
+}
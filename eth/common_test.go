@@ -4,9 +4,12 @@ import (
 	"github.com/FourthState/plasma-mvp-sidechain/store"
 	cosmosStore "github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
+	"testing"
+	"time"
 )
 
 func setup() (sdk.Context, store.DataStore) {
@@ -24,3 +27,15 @@ func setup() (sdk.Context, store.DataStore) {
 
 	return ctx, ds
 }
+
+// pegToLatestBlock returns a tendermint block time whose peg is bounded by the
+// latest ethereum block. A later block is mined so that the bound can be resolved
+func pegToLatestBlock(t *testing.T, client Client) time.Time {
+	header, err := client.HeaderByNumber(nil)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Second)
+	require.NoError(t, client.conn.rpc.Call(nil, "evm_mine"), "error mining a block")
+
+	return time.Unix(int64(header.Time), 0).Add(pegDelay)
+}
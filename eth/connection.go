@@ -0,0 +1,218 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"sync"
+	"time"
+)
+
+const (
+	// interval at which the health of a connected ethereum node is checked
+	healthCheckInterval = 10 * time.Second
+
+	// bounds of the delay between reconnection attempts. The delay doubles
+	// with every consecutive failure
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// Connection states of the ethereum node
+const (
+	StatusConnecting  = "connecting"  // no connection attempt has completed yet
+	StatusSyncing     = "syncing"     // connected but the node is still syncing
+	StatusHealthy     = "healthy"     // connected and fully synced
+	StatusUnavailable = "unavailable" // the node could not be reached
+)
+
+// ErrUnavailable is returned by rpc calls made while the ethereum node is not connected
+var ErrUnavailable = errors.New("rootchain unavailable")
+
+// Health describes the state of the connection to the ethereum node
+type Health struct {
+	Status      string    `json:"status"`
+	LastError   string    `json:"last_error"`
+	LastContact time.Time `json:"last_contact"` // last time the node responded
+	Failures    int       `json:"failures"`     // consecutive failed connection attempts or health checks
+}
+
+// connection holds the rpc clients of the ethereum node. The clients are replaced
+// when the node is redialed, so they are shared by every copy of a `Client`
+type connection struct {
+	url string
+
+	mtx    sync.RWMutex
+	rpc    *rpc.Client
+	ec     *ethclient.Client
+	health Health
+
+	maintained bool // health checks are running
+	quit       chan struct{}
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+func newConnection(url string) *connection {
+	return &connection{
+		url:    url,
+		health: Health{Status: StatusConnecting},
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// dial connects to the ethereum node, replacing any previous connection
+func (conn *connection) dial() error {
+	c, err := rpc.Dial(conn.url)
+	if err != nil {
+		return err
+	}
+
+	conn.mtx.Lock()
+	if conn.rpc != nil {
+		conn.rpc.Close()
+	}
+	conn.rpc, conn.ec = c, ethclient.NewClient(c)
+	conn.mtx.Unlock()
+
+	return nil
+}
+
+// clients returns the current rpc clients. ErrUnavailable is returned if the node has never been dialed
+func (conn *connection) clients() (*rpc.Client, *ethclient.Client, error) {
+	conn.mtx.RLock()
+	defer conn.mtx.RUnlock()
+
+	if conn.rpc == nil {
+		return nil, nil, ErrUnavailable
+	}
+
+	return conn.rpc, conn.ec, nil
+}
+
+// maintain checks the health of the node in a separate goroutine, starting
+// after `delay`. The node is redialed with an exponential backoff while it
+// cannot be reached
+func (conn *connection) maintain(delay time.Duration) {
+	conn.maintained = true
+	go func() {
+		defer close(conn.done)
+
+		for {
+			select {
+			case <-conn.quit:
+				return
+			case <-time.After(delay):
+			}
+			delay = conn.check()
+		}
+	}()
+}
+
+// stop halts the health checks and closes the connection
+func (conn *connection) stop() {
+	conn.stopOnce.Do(func() {
+		close(conn.quit)
+	})
+	if conn.maintained {
+		<-conn.done
+	}
+
+	conn.mtx.Lock()
+	if conn.rpc != nil {
+		conn.rpc.Close()
+	}
+	conn.mtx.Unlock()
+}
+
+// check dials the node if necessary and updates the health state. The delay
+// until the next check is returned
+func (conn *connection) check() time.Duration {
+	if _, _, err := conn.clients(); err != nil {
+		if err := conn.dial(); err != nil {
+			return conn.failed(err)
+		}
+	}
+
+	synced, err := conn.synced()
+	if err != nil {
+		return conn.failed(err)
+	}
+
+	status := StatusHealthy
+	if !synced {
+		status = StatusSyncing
+	}
+	conn.update(Health{Status: status, LastContact: time.Now()})
+
+	return healthCheckInterval
+}
+
+// failed records a failed connection attempt or health check and returns the backoff delay
+func (conn *connection) failed(err error) time.Duration {
+	conn.mtx.RLock()
+	health := conn.health
+	conn.mtx.RUnlock()
+
+	health.Status = StatusUnavailable
+	health.LastError = err.Error()
+	health.Failures++
+	conn.update(health)
+
+	delay := minReconnectDelay
+	for i := 1; i < health.Failures && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxReconnectDelay {
+		delay = maxReconnectDelay
+	}
+
+	return delay
+}
+
+// update sets the health state and logs any change of the status
+func (conn *connection) update(health Health) {
+	conn.mtx.Lock()
+	prev := conn.health.Status
+	conn.health = health
+	conn.mtx.Unlock()
+
+	if prev == health.Status {
+		return
+	}
+
+	if health.Status == StatusUnavailable {
+		logger.Error(fmt.Sprintf("ethereum node %s is unavailable: %s", conn.url, health.LastError))
+	} else {
+		logger.Info(fmt.Sprintf("ethereum node %s is %s", conn.url, health.Status))
+	}
+}
+
+// getHealth returns a copy of the health state
+func (conn *connection) getHealth() Health {
+	conn.mtx.RLock()
+	defer conn.mtx.RUnlock()
+
+	return conn.health
+}
+
+// synced checks if the node is fully synced with its network
+func (conn *connection) synced() (bool, error) {
+	c, _, err := conn.clients()
+	if err != nil {
+		return false, err
+	}
+
+	var res json.RawMessage
+	start := time.Now()
+	err = c.Call(&res, "eth_syncing")
+	observe("eth_syncing", start, err)
+	if err != nil {
+		return false, fmt.Errorf("rpc: %s", err)
+	}
+
+	return string(res) == "false", nil
+}
@@ -0,0 +1,34 @@
+package eth
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// An unreachable node must be retried with an exponential backoff
+func TestReconnectBackoff(t *testing.T) {
+	conn := newConnection("http://127.0.0.1:1")
+	client := Client{conn, meteredClient{conn}}
+	require.Equal(t, StatusConnecting, client.Health().Status)
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for i, delay := range expected {
+		require.Equal(t, delay, conn.check(), "attempt %d: incorrect backoff", i)
+
+		health := client.Health()
+		require.Equal(t, StatusUnavailable, health.Status)
+		require.Equal(t, i+1, health.Failures)
+		require.NotEmpty(t, health.LastError)
+	}
+	require.False(t, client.Healthy())
+
+	// the backoff is capped
+	for i := 0; i < 10; i++ {
+		conn.check()
+	}
+	require.Equal(t, maxReconnectDelay, conn.check())
+
+	_, err := client.LatestBlockNum()
+	require.Error(t, err, "call to an unreachable node succeeded")
+}
@@ -0,0 +1,234 @@
+package eth
+
+import (
+	"fmt"
+	plasmaTypes "github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// number of polls an accepted include-deposit transaction is given to be
+// committed before it is submitted again
+const resubmitPolls = 10
+
+// DepositIncluder is used by the DepositWatcher to include finalized deposits
+// into the sidechain
+type DepositIncluder interface {
+	// HasDeposit reports if the deposit has already been included into the sidechain
+	HasDeposit(nonce *big.Int) bool
+
+	// IncludeDeposit submits an include-deposit transaction for the deposit. An error
+	// is returned if the transaction was not accepted
+	IncludeDeposit(nonce *big.Int, owner common.Address, replayNonce uint64) error
+}
+
+// DepositWatcher polls the rootchain for deposits and includes them into the
+// sidechain once they have passed the finality bound
+type DepositWatcher struct {
+	plasma       *Plasma
+	includer     DepositIncluder
+	pollInterval time.Duration
+
+	// last ethereum block scanned for deposit events
+	lastScannedBlock *big.Int
+	// deposits that have not been included yet, keyed by nonce
+	pending map[string]*pendingDeposit
+	// number of rootchain reorgs the pending deposits have been rewound for
+	reorgsSeen int
+
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+type pendingDeposit struct {
+	nonce       *big.Int
+	owner       common.Address
+	ethBlockNum *big.Int
+
+	replayNonce uint64
+	// number of polls since the include-deposit transaction was accepted. -1 if not submitted
+	pollsSinceSubmission int
+}
+
+// NewDepositWatcher creates a watcher that checks for new deposits every
+// `pollInterval` and submits them through the `includer`
+func (plasma *Plasma) NewDepositWatcher(includer DepositIncluder, pollInterval time.Duration) *DepositWatcher {
+	return &DepositWatcher{
+		plasma:       plasma,
+		includer:     includer,
+		pollInterval: pollInterval,
+		pending:      make(map[string]*pendingDeposit),
+		quit:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start begins watching the rootchain in a separate goroutine
+func (w *DepositWatcher) Start() {
+	logger.Info(fmt.Sprintf("watching for deposits every %s", w.pollInterval))
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+		for {
+			if err := w.poll(); err != nil {
+				logger.Error(fmt.Sprintf("error polling for deposits: %s", err))
+			}
+
+			select {
+			case <-w.quit:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop halts the watcher and waits for the current poll to finish
+func (w *DepositWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.quit)
+	})
+	<-w.done
+}
+
+// poll scans the new ethereum blocks for deposits and submits the pending deposits that have finalized
+func (w *DepositWatcher) poll() error {
+	latestBlock, err := w.plasma.client.LatestBlockNum()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.plasma.reorgs.check(latestBlock); err != nil {
+		return fmt.Errorf("checking for rootchain reorgs: %s", err)
+	}
+	reorgs, seen := w.plasma.reorgs.reorgsSince(w.reorgsSeen)
+	for _, reorg := range reorgs {
+		w.rewind(reorg.BlockNum)
+		if reorg.Depth > w.plasma.finalityBound {
+			logger.Error(fmt.Sprintf("ALERT: deposit submission halted after a rootchain reorg of %d blocks starting at block %s. "+
+				"Review the included deposits and restart the node to resume", reorg.Depth, reorg.BlockNum))
+		}
+	}
+	w.reorgsSeen = seen
+	if w.plasma.reorgs.halted() {
+		return fmt.Errorf("deposit inclusion halted after a rootchain reorg deeper than the finality bound")
+	}
+
+	if w.lastScannedBlock == nil || latestBlock.Cmp(w.lastScannedBlock) > 0 {
+		if err := w.scan(latestBlock); err != nil {
+			return err
+		}
+	}
+
+	for key, deposit := range w.pending {
+		if w.includer.HasDeposit(deposit.nonce) {
+			logger.Info(fmt.Sprintf("deposit %s included into the sidechain", deposit.nonce))
+			delete(w.pending, key)
+			continue
+		}
+
+		// wait for the previous submission to be committed
+		if deposit.pollsSinceSubmission >= 0 && deposit.pollsSinceSubmission < resubmitPolls {
+			deposit.pollsSinceSubmission++
+			continue
+		}
+
+		if !w.isFinal(latestBlock, deposit.ethBlockNum) {
+			continue
+		}
+
+		// deposits exited on the rootchain can never be included
+		exited, err := w.plasma.HasTxExited(nil, time.Time{}, plasmaTypes.NewPosition(utils.Big0, 0, 0, deposit.nonce))
+		if err != nil {
+			continue
+		} else if exited {
+			logger.Info(fmt.Sprintf("deposit %s has exited. skipping inclusion", deposit.nonce))
+			delete(w.pending, key)
+			continue
+		}
+
+		deposit.replayNonce++
+		if err := w.includer.IncludeDeposit(deposit.nonce, deposit.owner, deposit.replayNonce); err != nil {
+			logger.Info(fmt.Sprintf("failed to include deposit %s. retrying next poll: %s", deposit.nonce, err))
+			deposit.pollsSinceSubmission = -1
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("submitted include-deposit transaction for deposit %s", deposit.nonce))
+		deposit.pollsSinceSubmission = 0
+	}
+
+	return nil
+}
+
+// scan adds all deposits that occurred after the last scanned block up to `latestBlock` to the pending set
+func (w *DepositWatcher) scan(latestBlock *big.Int) error {
+	start := w.plasma.deploymentBlock
+	if w.lastScannedBlock != nil {
+		start = w.lastScannedBlock.Uint64() + 1
+	}
+	end := latestBlock.Uint64()
+	if start > end {
+		return nil
+	}
+
+	iter, err := w.plasma.FilterDeposit(&bind.FilterOpts{Start: start, End: &end})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.Next() {
+		event := iter.Event
+		key := event.DepositNonce.String()
+		if _, ok := w.pending[key]; ok || w.includer.HasDeposit(event.DepositNonce) {
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("found deposit %s from 0x%x", event.DepositNonce, event.Depositor))
+		w.pending[key] = &pendingDeposit{
+			nonce:                event.DepositNonce,
+			owner:                event.Depositor,
+			ethBlockNum:          event.EthBlockNum,
+			pollsSinceSubmission: -1,
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	w.lastScannedBlock = latestBlock
+	return nil
+}
+
+// rewind forgets the deposits found in the ethereum blocks starting at `ethBlockNum`
+// so that they are scanned again from the canonical chain
+func (w *DepositWatcher) rewind(ethBlockNum *big.Int) {
+	for key, deposit := range w.pending {
+		if deposit.ethBlockNum.Cmp(ethBlockNum) >= 0 {
+			delete(w.pending, key)
+		}
+	}
+
+	if w.lastScannedBlock == nil || w.lastScannedBlock.Cmp(ethBlockNum) < 0 {
+		return
+	}
+	if ethBlockNum.Uint64() <= w.plasma.deploymentBlock {
+		w.lastScannedBlock = nil
+	} else {
+		w.lastScannedBlock = new(big.Int).Sub(ethBlockNum, utils.Big1)
+	}
+}
+
+// isFinal reports if a deposit made in `ethBlockNum` has passed the finality bound at `latestBlock`
+func (w *DepositWatcher) isFinal(latestBlock, ethBlockNum *big.Int) bool {
+	interval := new(big.Int).Sub(latestBlock, ethBlockNum)
+	return interval.Cmp(new(big.Int).SetUint64(w.plasma.finalityBound)) >= 0
+}
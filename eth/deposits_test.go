@@ -0,0 +1,75 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// records the include-deposit submissions made by the watcher
+type cookedIncluder struct {
+	included  map[string]bool
+	submitted map[string]common.Address
+}
+
+func (includer *cookedIncluder) HasDeposit(nonce *big.Int) bool {
+	return includer.included[nonce.String()]
+}
+
+func (includer *cookedIncluder) IncludeDeposit(nonce *big.Int, owner common.Address, replayNonce uint64) error {
+	includer.submitted[nonce.String()] = owner
+	return nil
+}
+
+func TestDepositWatcher(t *testing.T) {
+	client, _ := InitEthConn(clientAddr)
+	privKey, _ := crypto.HexToECDSA(operatorPrivKey)
+	plasmaContract, _ := InitPlasma(common.HexToAddress(plasmaContractAddr), client, 2)
+	plasmaContract, _ = plasmaContract.WithOperatorSession(privKey, commitmentRate)
+
+	includer := &cookedIncluder{make(map[string]bool), make(map[string]common.Address)}
+	watcher := plasmaContract.NewDepositWatcher(includer, time.Second)
+
+	// previous deposits are picked up by the first scan
+	require.NoError(t, watcher.poll())
+	for key := range watcher.pending {
+		includer.included[key] = true
+	}
+	require.NoError(t, watcher.poll())
+	require.Empty(t, watcher.pending, "included deposits remain pending")
+
+	nonce, err := plasmaContract.DepositNonce(nil)
+	require.NoError(t, err, "error querying for the deposit nonce")
+
+	plasmaContract.operatorSession.TransactOpts.Value = big.NewInt(10)
+	operatorAddress := crypto.PubkeyToAddress(privKey.PublicKey)
+	_, err = plasmaContract.operatorSession.Deposit(operatorAddress)
+	require.NoError(t, err, "error sending a deposit tx")
+	plasmaContract.operatorSession.TransactOpts.Value = nil
+	time.Sleep(1 * time.Second)
+
+	// deposit is found but has not finalized
+	require.NoError(t, watcher.poll())
+	require.Contains(t, watcher.pending, nonce.String(), "deposit not picked up by the watcher")
+	require.Empty(t, includer.submitted, "submitted a deposit before it was final")
+
+	for i := 0; i < 2; i++ {
+		err = client.conn.rpc.Call(nil, "evm_mine")
+		require.NoError(t, err, "error mining a block")
+	}
+	time.Sleep(1 * time.Second)
+
+	// deposit has finalized
+	require.NoError(t, watcher.poll())
+	owner, ok := includer.submitted[nonce.String()]
+	require.True(t, ok, "finalized deposit not submitted")
+	require.Equal(t, operatorAddress, owner, "deposit submitted with the wrong owner")
+
+	// removed once included
+	includer.included[nonce.String()] = true
+	require.NoError(t, watcher.poll())
+	require.NotContains(t, watcher.pending, nonce.String(), "included deposit remains pending")
+}
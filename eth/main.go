@@ -3,37 +3,35 @@
 package eth
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/utils"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"math/big"
+	"time"
 )
 
 // Client defines wrappers to a remote endpoint
 type Client struct {
-	rpc *rpc.Client
-	ec  *ethclient.Client
+	conn *connection
+	ec   meteredClient
 }
 
 // InitEthConn will instantiate a connection and bind the go plasma contract
 // wrapper with this client. Will return an error if the ethereum client is
 // not fully sycned.
 func InitEthConn(nodeUrl string) (Client, error) {
-	// Connect to a remote ethereum client
-	//
-	// Ethclient wraps around the underlying rpc module and provides convenient functions. We still keep reference
-	// to the underlying rpc module to make calls that the wrapper does not support
-	c, err := rpc.Dial(nodeUrl)
-	if err != nil {
+	// Connect to a remote ethereum client. The connection is not maintained in the background
+	conn := newConnection(nodeUrl)
+	if err := conn.dial(); err != nil {
 		return Client{}, err
 	}
-	ec := ethclient.NewClient(c)
 
 	// check if the client is synced
-	client := Client{c, ec}
+	client := Client{conn, meteredClient{conn}}
 	if synced, err := client.Synced(); !synced || err != nil {
 		if err != nil {
 			return client, err
@@ -41,28 +39,55 @@ func InitEthConn(nodeUrl string) (Client, error) {
 			return client, fmt.Errorf("geth endpoint is not fully synced")
 		}
 	}
+	conn.update(Health{Status: StatusHealthy, LastContact: time.Now()})
 
 	return client, nil
 }
 
-// Synced checks of the status of the geth endpoint with it's network
-func (client Client) Synced() (bool, error) {
-	var res json.RawMessage
-	if err := client.rpc.Call(&res, "eth_syncing"); err != nil {
-		return false, fmt.Errorf("rpc: %s", err)
-	}
+// ConnectEthConn returns a client that connects to the ethereum node in the
+// background. The node is redialed with an exponential backoff while it cannot
+// be reached and its health is checked periodically. Calls made while the node
+// has not been connected fail with ErrUnavailable
+func ConnectEthConn(nodeUrl string) Client {
+	conn := newConnection(nodeUrl)
+	conn.maintain(conn.check())
 
-	if string(res) != "false" {
-		return false, nil
-	}
+	return Client{conn, meteredClient{conn}}
+}
+
+// Health returns the state of the connection to the ethereum node
+func (client Client) Health() Health {
+	return client.conn.getHealth()
+}
+
+// Healthy reports if the ethereum node is connected and fully synced
+func (client Client) Healthy() bool {
+	return client.Health().Status == StatusHealthy
+}
 
-	return true, nil
+// Close halts the health checks of a client created with ConnectEthConn
+// and closes the connection
+func (client Client) Close() {
+	client.conn.stop()
+}
+
+// Synced checks of the status of the geth endpoint with it's network
+func (client Client) Synced() (bool, error) {
+	return client.conn.synced()
 }
 
 // LatestBlockNum retrieves the latest block height of the of the geth endpoint
 func (client Client) LatestBlockNum() (*big.Int, error) {
+	c, _, err := client.conn.clients()
+	if err != nil {
+		return nil, err
+	}
+
 	var hexStr string
-	if err := client.rpc.Call(&hexStr, "eth_blockNumber"); err != nil {
+	start := time.Now()
+	err = c.Call(&hexStr, "eth_blockNumber")
+	observe("eth_blockNumber", start, err)
+	if err != nil {
 		return nil, fmt.Errorf("rpc: %s", err)
 	}
 
@@ -80,3 +105,29 @@ func (client Client) LatestBlockNum() (*big.Int, error) {
 
 	return new(big.Int).SetBytes(hexBytes), nil
 }
+
+// TransactionReceipt retrieves the receipt of a mined transaction. A nil receipt
+// is returned if the transaction has not been mined
+func (client Client) TransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	receipt, err := client.ec.TransactionReceipt(context.Background(), hash)
+	if err == ethereum.NotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("rpc: %s", err)
+	}
+
+	return receipt, nil
+}
+
+// HeaderByNumber retrieves the header of the canonical block at `number`. A nil
+// header is returned if the block does not exist
+func (client Client) HeaderByNumber(number *big.Int) (*types.Header, error) {
+	header, err := client.ec.HeaderByNumber(context.Background(), number)
+	if err == ethereum.NotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("rpc: %s", err)
+	}
+
+	return header, nil
+}
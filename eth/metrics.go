@@ -0,0 +1,152 @@
+package eth
+
+import (
+	"context"
+	"github.com/FourthState/plasma-mvp-sidechain/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"math/big"
+	"time"
+)
+
+// meteredClient records the latency and errors of the rpc calls made through
+// the ethclient of the connection. Contract bindings use it as their backend so
+// that every call to the plasma contract is measured as well. Calls fail with
+// ErrUnavailable while the ethereum node has not been connected
+type meteredClient struct {
+	conn *connection
+}
+
+// observe records the latency of `method` and counts the call as failed if
+// `err` is set. Missing results, such as the receipt of a pending transaction,
+// are not considered failures
+func observe(method string, start time.Time, err error) {
+	metrics.EthRPCLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil && err != ethereum.NotFound {
+		metrics.EthRPCErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// client returns the ethclient of the current connection
+func (ec meteredClient) client() (*ethclient.Client, error) {
+	_, client, err := ec.conn.clients()
+	return client, err
+}
+
+func (ec meteredClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	defer func(start time.Time) { observe("eth_getCode", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.CodeAt(ctx, account, blockNumber)
+}
+
+func (ec meteredClient) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	defer func(start time.Time) { observe("eth_getCode", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.PendingCodeAt(ctx, account)
+}
+
+func (ec meteredClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (res []byte, err error) {
+	defer func(start time.Time) { observe("eth_call", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.CallContract(ctx, msg, blockNumber)
+}
+
+func (ec meteredClient) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) (res []byte, err error) {
+	defer func(start time.Time) { observe("eth_call", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.PendingCallContract(ctx, msg)
+}
+
+func (ec meteredClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (nonce uint64, err error) {
+	defer func(start time.Time) { observe("eth_getTransactionCount", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return 0, err
+	}
+	return client.NonceAt(ctx, account, blockNumber)
+}
+
+func (ec meteredClient) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	defer func(start time.Time) { observe("eth_getTransactionCount", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return 0, err
+	}
+	return client.PendingNonceAt(ctx, account)
+}
+
+func (ec meteredClient) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	defer func(start time.Time) { observe("eth_gasPrice", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.SuggestGasPrice(ctx)
+}
+
+func (ec meteredClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	defer func(start time.Time) { observe("eth_estimateGas", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return 0, err
+	}
+	return client.EstimateGas(ctx, msg)
+}
+
+func (ec meteredClient) SendTransaction(ctx context.Context, tx *types.Transaction) (err error) {
+	defer func(start time.Time) { observe("eth_sendRawTransaction", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return err
+	}
+	return client.SendTransaction(ctx, tx)
+}
+
+func (ec meteredClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	defer func(start time.Time) { observe("eth_getTransactionReceipt", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.TransactionReceipt(ctx, txHash)
+}
+
+func (ec meteredClient) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	defer func(start time.Time) { observe("eth_getBlockByNumber", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.HeaderByNumber(ctx, number)
+}
+
+func (ec meteredClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (logs []types.Log, err error) {
+	defer func(start time.Time) { observe("eth_getLogs", start, err) }(time.Now())
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.FilterLogs(ctx, q)
+}
+
+func (ec meteredClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	client, err := ec.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.SubscribeFilterLogs(ctx, q, ch)
+}
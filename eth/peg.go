@@ -0,0 +1,40 @@
+package eth
+
+import (
+	"math/big"
+	"time"
+)
+
+// pegDelay is how long before the time of a tendermint block the latest
+// ethereum block it may be pegged to was mined. The delay leaves the ethereum
+// node time to see the blocks mined before it. Unlike the progress of the node
+// syncing with the rootchain, the time of a tendermint block is agreed upon by
+// the validators, so every node resolves the same block
+const pegDelay = time.Minute
+
+// latestSubmission returns the ethereum block in which the latest plasma block up to `blockIndex` was
+// submitted, among the submissions mined at or before `bound`. nil is returned if there are none. Plasma
+// blocks are submitted in order, so the submissions of `submittedAt` are binary searched
+func latestSubmission(blockIndex, bound *big.Int, submittedAt func(blockNum *big.Int) (*big.Int, error)) (*big.Int, error) {
+	if blockIndex.Sign() <= 0 {
+		return nil, nil
+	}
+
+	var ethBlockNum *big.Int
+	low, high := uint64(0), blockIndex.Uint64()
+	for low < high {
+		mid := low + (high-low+1)/2
+		submission, err := submittedAt(new(big.Int).SetUint64(mid))
+		if err != nil {
+			return nil, err
+		}
+
+		if submission.Cmp(bound) <= 0 {
+			low, ethBlockNum = mid, submission
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return ethBlockNum, nil
+}
@@ -0,0 +1,157 @@
+package eth
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// The peg must be the latest submission mined at or before the bound
+func TestLatestSubmission(t *testing.T) {
+	// plasma block i was submitted in ethereum block 10*i
+	submittedAt := func(blockNum *big.Int) (*big.Int, error) {
+		return new(big.Int).Mul(blockNum, big.NewInt(10)), nil
+	}
+
+	cases := []struct {
+		blockIndex, bound int64
+		expected          *big.Int
+	}{
+		{0, 100, nil},
+		{5, 9, nil},
+		{5, 10, big.NewInt(10)},
+		{5, 39, big.NewInt(30)},
+		{5, 50, big.NewInt(50)},
+		{5, 1000, big.NewInt(50)},
+		{1, 1000, big.NewInt(10)},
+	}
+	for _, c := range cases {
+		ethBlockNum, err := latestSubmission(big.NewInt(c.blockIndex), big.NewInt(c.bound), submittedAt)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, ethBlockNum, "block index %d, bound %d", c.blockIndex, c.bound)
+	}
+}
+
+// The mirror must not answer for ethereum blocks it has not synced up to, regardless of how far it has synced
+func TestCachePegSynced(t *testing.T) {
+	cache := newRootchainCache(nil, Client{}, nil, 0)
+	position := plasma.NewPosition(nil, 0, 0, big.NewInt(1))
+
+	// ethereum block i was mined at time 100*i
+	mine := func(blocks uint64) {
+		for i := uint64(len(cache.blockTimes)); i <= blocks; i++ {
+			cache.blockTimes = append(cache.blockTimes, 100*i)
+		}
+		cache.syncedBlock = new(big.Int).SetUint64(blocks)
+	}
+	// block time whose peg is bounded by ethereum block 25
+	blockTime := time.Unix(2550, 0).Add(pegDelay)
+
+	_, err := cache.timePeg(big.NewInt(3), blockTime)
+	require.Error(t, err, "pegged an unsynced mirror")
+
+	mine(20)
+	cache.submissions["1"] = big.NewInt(10)
+	cache.lastCommittedBlock = big.NewInt(1)
+	cache.deposits["1"] = plasma.NewDeposit(common.Address{}, big.NewInt(10), big.NewInt(5))
+	cache.exits[position.String()] = []exitTransition{{big.NewInt(15), true}}
+
+	_, err = cache.timePeg(big.NewInt(3), blockTime)
+	require.Error(t, err, "pegged past the synced block")
+	_, _, err = cache.getDeposit(big.NewInt(25), big.NewInt(1))
+	require.Error(t, err, "retrieved a deposit past the synced block")
+	_, err = cache.hasExited(big.NewInt(25), position)
+	require.Error(t, err, "retrieved an exit past the synced block")
+
+	// a later submission beyond the bound must not change the peg
+	mine(40)
+	cache.submissions["2"] = big.NewInt(30)
+	cache.lastCommittedBlock = big.NewInt(2)
+
+	peg, err := cache.timePeg(big.NewInt(3), blockTime)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), peg, "peg depends on the progress of the mirror")
+
+	exited, err := cache.hasExited(peg, position)
+	require.NoError(t, err)
+	require.False(t, exited, "exit after the peg reported")
+
+	// the bound is the peg without earlier submissions
+	peg, err = cache.timePeg(big.NewInt(1), blockTime)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(25), peg, "peg is not bounded by the block time")
+}
+
+// The block mined at a point in time must only be resolved once no earlier block can be mirrored
+func TestCacheBlockAt(t *testing.T) {
+	// the contract was deployed in ethereum block 10, mined at time 1000. Block i was mined at time 100*i
+	cache := newRootchainCache(nil, Client{}, nil, 10)
+	for i := uint64(10); i <= 20; i++ {
+		cache.blockTimes = append(cache.blockTimes, 100*i)
+	}
+	cache.syncedBlock = big.NewInt(20)
+
+	cases := []struct {
+		time     int64
+		expected *big.Int
+	}{
+		{500, big.NewInt(10)},
+		{1000, big.NewInt(10)},
+		{1550, big.NewInt(15)},
+		{1999, big.NewInt(19)},
+	}
+	for _, c := range cases {
+		block, err := cache.blockAt(time.Unix(c.time, 0))
+		require.NoError(t, err)
+		require.Equal(t, c.expected, block, "block at time %d", c.time)
+	}
+
+	// a block mined at or before time 2000 may not have been mirrored yet
+	_, err := cache.blockAt(time.Unix(2000, 0))
+	require.Error(t, err, "resolved the latest mirrored block while a later block may be mined")
+
+	// an idle chain has not mined a block since, as seen by a sync started well after
+	cache.syncedAt = time.Unix(2000, 0).Add(pegDelay)
+	block, err := cache.blockAt(time.Unix(2000, 0))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20), block, "idle chain not resolved to the latest block")
+
+	// the mirrored blocks replaced by a reorg are mirrored again before resolving the block
+	cache.rewind(big.NewInt(15))
+	require.Equal(t, big.NewInt(14), cache.syncedBlock)
+	require.Len(t, cache.blockTimes, 5)
+	_, err = cache.blockAt(time.Unix(1550, 0))
+	require.Error(t, err, "resolved a block time past the rewound mirror")
+}
+
+// Plasma blocks before the activation height must keep the peg of the latest submission
+func TestTimePegActivation(t *testing.T) {
+	cache := newRootchainCache(nil, Client{}, nil, 0)
+	for i := uint64(0); i <= 40; i++ {
+		cache.blockTimes = append(cache.blockTimes, 100*i)
+	}
+	cache.syncedBlock = big.NewInt(40)
+	cache.submissions["1"] = big.NewInt(10)
+	cache.submissions["2"] = big.NewInt(30)
+	cache.lastCommittedBlock = big.NewInt(2)
+
+	plasmaConn := &Plasma{cache: cache}
+	_, err := (&Plasma{}).WithTimePeg(5)
+	require.Error(t, err, "time peg set up without the rootchain cache")
+	plasmaConn, err = plasmaConn.WithTimePeg(5)
+	require.NoError(t, err)
+
+	// bounded by ethereum block 25
+	blockTime := time.Unix(2550, 0).Add(pegDelay)
+
+	peg, err := plasmaConn.ethBlockPeg(big.NewInt(4), blockTime)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(30), peg, "block before the activation height pegged by block time")
+
+	peg, err = plasmaConn.ethBlockPeg(big.NewInt(5), blockTime)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), peg, "block at the activation height not pegged by block time")
+}
@@ -7,7 +7,6 @@ import (
 	contracts "github.com/FourthState/plasma-mvp-sidechain/contracts/wrappers"
 	plasmaTypes "github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/FourthState/plasma-mvp-sidechain/store"
-	"github.com/FourthState/plasma-mvp-sidechain/utils"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -28,16 +27,29 @@ func SetLogger(l log.Logger) {
 type Plasma struct {
 	*contracts.PlasmaMVP // expose all the contract methods
 
+	address         common.Address
 	client          Client
 	finalityBound   uint64
 	operatorSession *operatorSession
+
+	// ethereum block the contract was deployed in. Events are only retrieved from it onwards
+	deploymentBlock uint64
+
+	// hashes of the rootchain blocks relied upon, used to detect reorgs
+	reorgs *reorgTracker
+
+	// plasma block from which blocks are pegged by their block time. nil if every block
+	// is pegged to the latest submission
+	timePegHeight *big.Int
+
+	// local mirror of the contract state. nil if rootchain state is queried directly
+	cache *rootchainCache
 }
 
 type operatorSession struct {
 	*contracts.PlasmaMVPSession
 
-	commitmentRate      time.Duration
-	lastBlockSubmission time.Time
+	submissions *submissionManager
 }
 
 // InitPlasma binds the go wrapper to the deployed contract. This private key provides authentication for the operator
@@ -50,26 +62,30 @@ func InitPlasma(contractAddr common.Address, client Client, finalityBound uint64
 
 	plasma := &Plasma{
 		PlasmaMVP:     plasmaContract,
+		address:       contractAddr,
 		client:        client,
 		finalityBound: finalityBound,
+		reorgs:        newReorgTracker(client.HeaderByNumber, finalityBound),
 	}
 
 	return plasma, nil
 }
 
 // WithOperatorSession will set up an operators session with the smart contract. The contract's operator public key must
-// match the public key corresponding `operatorPrivKey`
+// match the public key corresponding `operatorPrivKey`. If the rootchain is unavailable, the operator is verified before
+// the first block submission instead
 func (plasma *Plasma) WithOperatorSession(operatorPrivkey *ecdsa.PrivateKey, commitmentRate time.Duration) (*Plasma, error) {
 	logger.Info(fmt.Sprintf("block commitment rate set to %s", commitmentRate))
 
 	// check that the public key matches the address of the operator
 	addr := crypto.PubkeyToAddress(operatorPrivkey.PublicKey)
-	operator, err := plasma.OperatorAddress()
-	if err != nil {
-		return plasma, err
-	}
-	if !bytes.Equal(operator[:], addr[:]) {
+	verified := false
+	if operator, err := plasma.OperatorAddress(); err != nil {
+		logger.Error(fmt.Sprintf("unable to verify the operator address: %s", err))
+	} else if !bytes.Equal(operator[:], addr[:]) {
 		return plasma, fmt.Errorf("operator address mismatch. Got 0x%x. Expected:0x%x", addr, operator)
+	} else {
+		verified = true
 	}
 
 	auth := bind.NewKeyedTransactor(operatorPrivkey)
@@ -79,96 +95,194 @@ func (plasma *Plasma) WithOperatorSession(operatorPrivkey *ecdsa.PrivateKey, com
 			Pending: true,
 		},
 		TransactOpts: bind.TransactOpts{
-			From:     auth.From,
-			Signer:   auth.Signer,
-			GasLimit: 3141592, // aribitrary. TODO: check this
+			From:   auth.From,
+			Signer: auth.Signer,
 		},
 	}
 
+	submissions, err := newSubmissionManager(plasma.PlasmaMVP, plasma.address, plasma.client, auth, commitmentRate)
+	if err != nil {
+		return plasma, err
+	}
+	submissions.verified = verified
+
 	opSession := &operatorSession{
-		PlasmaMVPSession:    contractSession,
-		commitmentRate:      commitmentRate,
-		lastBlockSubmission: time.Now(),
+		PlasmaMVPSession: contractSession,
+		submissions:      submissions,
 	}
 
 	plasma.operatorSession = opSession
 	return plasma, nil
 }
 
-// OperatorAddress will fetch the plasma operator address from the connected smart contract
-func (plasma *Plasma) OperatorAddress() (common.Address, error) {
-	return plasma.Operator(nil)
+// WithSubmissionConfig sets the gas price strategy and resubmission policy of block submissions.
+// An operator session must have been set up
+func (plasma *Plasma) WithSubmissionConfig(config SubmissionConfig) (*Plasma, error) {
+	if plasma.operatorSession == nil {
+		return plasma, fmt.Errorf("block submissions require an operator session")
+	}
+	if config.GasPrice == nil {
+		return plasma, fmt.Errorf("gas price strategy not specified")
+	}
+	if config.GasPriceBump < MinGasPriceBump {
+		return plasma, fmt.Errorf("gas price bump must be at least %d percent", MinGasPriceBump)
+	}
+	if config.MaxBlocks <= 0 {
+		return plasma, fmt.Errorf("a submission must commit at least one block")
+	}
+
+	logger.Info(fmt.Sprintf("stuck block submissions are resubmitted after %s", config.Timeout))
+	plasma.operatorSession.submissions.config = config
+	return plasma, nil
 }
 
-// CommitPlasmaHeaders will commit all new non-committed headers to the smart contract.
-// the commitmentRate interval must pass since the last commitment
-func (plasma *Plasma) CommitPlasmaHeaders(ctx sdk.Context, ds store.DataStore) error {
-	// only the contract operator can submit blocks. The commitment duration must also pass
-	if plasma.operatorSession == nil || time.Since(plasma.operatorSession.lastBlockSubmission).Seconds() < plasma.operatorSession.commitmentRate.Seconds() {
-		return nil
-	}
+// WithDeploymentBlock sets the ethereum block the contract was deployed in. The events of the
+// contract are retrieved starting from it rather than from the genesis block. Must be set before
+// the cache is set up
+func (plasma *Plasma) WithDeploymentBlock(block uint64) *Plasma {
+	logger.Info(fmt.Sprintf("rootchain events are retrieved from ethereum block %d", block))
+	plasma.deploymentBlock = block
+	return plasma
+}
 
-	logger.Info("attempting to commit plasma headers...")
+// WithCache will mirror the deposit, exit and block submission state of the contract locally. The mirror
+// is synced before returning and is kept up to date by polling for new events every `pollInterval`.
+// `GetDeposit` and `HasTxExited` are answered from the mirror without any network round trips.
+// If the rootchain is unavailable, the mirror is synced once it can be reached
+func (plasma *Plasma) WithCache(pollInterval time.Duration) (*Plasma, error) {
+	logger.Info("syncing the rootchain cache...")
 
-	lastCommittedBlock, err := plasma.LastCommittedBlock(nil)
-	if err != nil {
-		logger.Error("error retrieving the last committed block number")
-		return err
+	cache := newRootchainCache(plasma.PlasmaMVP, plasma.client, plasma.reorgs, plasma.deploymentBlock)
+	if err := cache.sync(); err != nil {
+		logger.Error(fmt.Sprintf("error syncing the rootchain cache: %s", err))
 	}
+	cache.start(pollInterval)
 
-	firstBlockNum := new(big.Int).Add(lastCommittedBlock, utils.Big1)
-	blockNum := lastCommittedBlock.Add(lastCommittedBlock, utils.Big1)
+	plasma.cache = cache
+	return plasma, nil
+}
 
-	var (
-		headers      [][32]byte
-		txnsPerBlock []*big.Int
-		feesPerBlock []*big.Int
-	)
+// WithTimePeg pegs the plasma blocks starting at `activationHeight` to the ethereum block mined
+// `pegDelay` before their block time, instead of the latest block submission seen by the node.
+// Every validator must use the same activation height. The peg is resolved from the local mirror,
+// so the cache must have been set up
+func (plasma *Plasma) WithTimePeg(activationHeight uint64) (*Plasma, error) {
+	if plasma.cache == nil {
+		return plasma, fmt.Errorf("the time peg is resolved from the rootchain cache, which is not set up")
+	}
+	if activationHeight == 0 {
+		return plasma, fmt.Errorf("time peg activation height must be positive")
+	}
 
-	block, ok := ds.GetBlock(ctx, blockNum)
-	if !ok { // no blocks to submit
-		logger.Info("no plasma blocks to commit")
-		return nil
+	logger.Info(fmt.Sprintf("plasma blocks are pegged by block time from block %d", activationHeight))
+	plasma.timePegHeight = new(big.Int).SetUint64(activationHeight)
+	return plasma, nil
+}
+
+// StopCache halts the syncing of the local mirror. The last synced state continues to be used
+func (plasma *Plasma) StopCache() {
+	if plasma.cache != nil {
+		plasma.cache.stop()
 	}
+}
 
-	for ok {
-		headers = append(headers, block.Header)
-		txnsPerBlock = append(txnsPerBlock, big.NewInt(int64(block.TxnCount)))
-		feesPerBlock = append(feesPerBlock, block.FeeAmount)
+// Client returns the connection to the ethereum node
+func (plasma *Plasma) Client() Client {
+	return plasma.client
+}
 
-		blockNum = blockNum.Add(blockNum, utils.Big1)
-		block, ok = ds.GetBlock(ctx, blockNum)
+// OperatorAddress will fetch the plasma operator address from the connected smart contract
+func (plasma *Plasma) OperatorAddress() (common.Address, error) {
+	if plasma.cache != nil {
+		return plasma.cache.getOperator()
 	}
 
-	logger.Info(fmt.Sprintf("committing %d plasma blocks. first block num: %s", len(headers), firstBlockNum))
-	plasma.operatorSession.lastBlockSubmission = time.Now()
-	_, err = plasma.operatorSession.SubmitBlock(headers, txnsPerBlock, feesPerBlock, firstBlockNum)
-	if err != nil {
-		logger.Error(fmt.Sprintf("error committing headers %s", err))
-		return err
+	return plasma.Operator(nil)
+}
+
+// ContractAddress returns the address of the rootchain contract the sidechain is bound to
+func (plasma *Plasma) ContractAddress() common.Address {
+	return plasma.address
+}
+
+// RootchainAvailable reports if the ethereum node is healthy and the local mirror,
+// if used, is up to date. Rootchain state must not be relied upon otherwise
+func (plasma *Plasma) RootchainAvailable() bool {
+	if !plasma.client.Healthy() {
+		return false
 	}
 
-	return err
+	return plasma.cache == nil || plasma.cache.available()
 }
 
-// GetDeposit checks the existence of a deposit nonce. The state is synchronized with the provided `plasmaBlockHeight. The deposit
-// must have occured before or at the same pegged ethereum block as `plasmaBlockHeight`.
-func (plasma *Plasma) GetDeposit(plasmaBlockHeight *big.Int, nonce *big.Int) (plasmaTypes.Deposit, *big.Int, bool) {
-	deposit, err := plasma.Deposits(nil, nonce)
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed deposit retrieval: %s", err))
-		return plasmaTypes.Deposit{}, nil, false
+// CommitPlasmaHeaders will commit all new non-committed headers to the smart contract.
+// the commitmentRate interval must pass since the last commitment was mined. A pending
+// commitment is tracked until it is mined and is resubmitted with a higher gas price
+// when stuck
+func (plasma *Plasma) CommitPlasmaHeaders(ctx sdk.Context, ds store.DataStore) error {
+	// only the contract operator can submit blocks
+	if plasma.operatorSession == nil {
+		return nil
 	}
 
-	if deposit.CreatedAt.Sign() == 0 {
-		return plasmaTypes.Deposit{}, nil, false
+	return plasma.operatorSession.submissions.commit(ctx, ds)
+}
+
+// SubmissionStatus returns the status of the latest block submission. The
+// zero value is returned if this is not an operator session
+func (plasma *Plasma) SubmissionStatus() SubmissionStatus {
+	if plasma.operatorSession == nil {
+		return SubmissionStatus{}
 	}
 
+	return plasma.operatorSession.submissions.getStatus()
+}
+
+// DepositsHalted reports if deposit inclusion has been halted by a reorg of the rootchain
+// deeper than the finality bound. This node does not submit or admit deposits into its
+// mempool until it is restarted
+func (plasma *Plasma) DepositsHalted() bool {
+	return plasma.reorgs.halted()
+}
+
+// ReorgStatus returns the reorgs of the rootchain detected by this node
+func (plasma *Plasma) ReorgStatus() ReorgStatus {
+	return plasma.reorgs.getStatus()
+}
+
+// GetDeposit checks the existence of a deposit nonce. The state is synchronized with the provided `plasmaBlockHeight`, mined
+// at `blockTime`. The deposit must have occured before or at the same pegged ethereum block as `plasmaBlockHeight`. Deposits
+// are returned regardless of DepositsHalted, which is local to this node and must not change the result of delivering a block.
+// An error is returned if the rootchain state could not be retrieved, in which case the existence of the deposit is unknown
+func (plasma *Plasma) GetDeposit(plasmaBlockHeight *big.Int, blockTime time.Time, nonce *big.Int) (plasmaTypes.Deposit, *big.Int, bool, error) {
 	// check the finality bound based off pegged ETH block
-	ethBlockNum, err := plasma.ethBlockPeg(plasmaBlockHeight)
+	ethBlockNum, err := plasma.ethBlockPeg(plasmaBlockHeight, blockTime)
 	if err != nil {
 		logger.Error(fmt.Sprintf("could not get pegged ETH Block for sidechain block %s: %s", plasmaBlockHeight, err))
-		return plasmaTypes.Deposit{}, nil, false
+		return plasmaTypes.Deposit{}, nil, false, err
+	}
+
+	var deposit plasmaTypes.Deposit
+	if plasma.cache != nil {
+		var ok bool
+		if deposit, ok, err = plasma.cache.getDeposit(ethBlockNum, nonce); err != nil {
+			logger.Error(fmt.Sprintf("failed deposit retrieval: %s", err))
+			return plasmaTypes.Deposit{}, nil, false, err
+		} else if !ok {
+			return plasmaTypes.Deposit{}, nil, false, nil
+		}
+	} else {
+		d, err := plasma.Deposits(nil, nonce)
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed deposit retrieval: %s", err))
+			return plasmaTypes.Deposit{}, nil, false, err
+		}
+
+		if d.CreatedAt.Sign() == 0 {
+			return plasmaTypes.Deposit{}, nil, false, nil
+		}
+
+		deposit = plasmaTypes.NewDeposit(d.Owner, d.Amount, d.EthBlockNum)
 	}
 
 	// how many blocks have occurred since deposit.
@@ -178,19 +292,36 @@ func (plasma *Plasma) GetDeposit(plasmaBlockHeight *big.Int, nonce *big.Int) (pl
 	// Note: If deposit is finalized, threshold can be 0 or negative
 	threshold := new(big.Int).Sub(big.NewInt(int64(plasma.finalityBound)), interval)
 	if threshold.Sign() > 0 {
-		return plasmaTypes.Deposit{}, threshold, false
+		return plasmaTypes.Deposit{}, threshold, false, nil
 	}
 
-	return plasmaTypes.Deposit{
-		Owner:       deposit.Owner,
-		Amount:      deposit.Amount,
-		EthBlockNum: deposit.EthBlockNum,
-	}, threshold, true
+	return deposit, threshold, true, nil
 }
 
 // HasTxExited indicates if the position has ever been exited at a time less than or equal to
-// the time `plasmaBlockHeight` was submitted. If nil, it is checked against the latest state
-func (plasma *Plasma) HasTxExited(plasmaBlockHeight *big.Int, position plasmaTypes.Position) (bool, error) {
+// the time `plasmaBlockHeight`, mined at `blockTime`, was submitted. If nil, it is checked against the latest state
+func (plasma *Plasma) HasTxExited(plasmaBlockHeight *big.Int, blockTime time.Time, position plasmaTypes.Position) (bool, error) {
+	if plasma.cache != nil {
+		var ethBlockNum *big.Int
+		if plasmaBlockHeight != nil {
+			var err error
+			if ethBlockNum, err = plasma.ethBlockPeg(plasmaBlockHeight, blockTime); err != nil {
+				// censor spends until the error is fixed
+				logger.Error(fmt.Sprintf("could not get associated ETH Block for plasma block %s: %s", plasmaBlockHeight, err))
+				return true, err
+			}
+		}
+
+		exited, err := plasma.cache.hasExited(ethBlockNum, position)
+		if err != nil {
+			// censor spends until the error is fixed
+			logger.Error(fmt.Sprintf("failed to retrieve exit information about position %s. error: %s", position, err))
+			return true, err
+		}
+
+		return exited, nil
+	}
+
 	type exit struct {
 		Amount       *big.Int
 		CommittedFee *big.Int
@@ -223,7 +354,7 @@ func (plasma *Plasma) HasTxExited(plasmaBlockHeight *big.Int, position plasmaTyp
 
 	// synchronize with the correct ethereum state
 	if plasmaBlockHeight != nil {
-		ethBlockNum, err := plasma.ethBlockPeg(plasmaBlockHeight)
+		ethBlockNum, err := plasma.ethBlockPeg(plasmaBlockHeight, blockTime)
 		if err != nil {
 			// censore spends until the error is fixed
 			logger.Error(fmt.Sprintf("could not get associated ETH Block for plasma block %s: %s", plasmaBlockHeight, err))
@@ -237,20 +368,34 @@ func (plasma *Plasma) HasTxExited(plasmaBlockHeight *big.Int, position plasmaTyp
 	return exited, nil
 }
 
-// Return the Ethereum Block that sidechain should use to synchronize current block tx's with rootchain state
-func (plasma *Plasma) ethBlockPeg(plasmaBlockHeight *big.Int) (*big.Int, error) {
+// Return the Ethereum Block that sidechain should use to synchronize current block tx's with rootchain state.
+// Blocks from the time peg activation height are pegged by `blockTime`, agreed upon by the validators, and the
+// block submissions recorded on the rootchain, so every node resolves the same block regardless of how far it has
+// synced. Earlier blocks keep the peg of `legacyPeg`
+func (plasma *Plasma) ethBlockPeg(plasmaBlockHeight *big.Int, blockTime time.Time) (*big.Int, error) {
+	if plasma.timePegHeight != nil && plasmaBlockHeight != nil && plasmaBlockHeight.Cmp(plasma.timePegHeight) >= 0 {
+		return plasma.cache.timePeg(plasmaBlockHeight, blockTime)
+	}
+
+	if plasma.cache != nil {
+		return plasma.cache.legacyPeg(plasmaBlockHeight)
+	}
+
+	return plasma.legacyPeg(plasmaBlockHeight)
+}
+
+// legacyPeg returns the ethereum block in which the plasma block preceding `plasmaBlockHeight`, or the last
+// committed block if earlier, was submitted. The latest ethereum block is used if no blocks were submitted
+func (plasma *Plasma) legacyPeg(plasmaBlockHeight *big.Int) (*big.Int, error) {
 	lastCommittedBlock, err := plasma.LastCommittedBlock(nil)
 	if err != nil {
 		return nil, err
 	}
 	// If no blocks submitted, use latestBlock as peg
 	if lastCommittedBlock.Sign() == 0 {
-		latestBlock, err := plasma.client.LatestBlockNum()
-		if err != nil {
-			return nil, err
-		}
-		return latestBlock, nil
+		return plasma.client.LatestBlockNum()
 	}
+
 	var blockIndex *big.Int
 	prevBlock := new(big.Int).Sub(plasmaBlockHeight, big.NewInt(1))
 	// For syncing nodes, peg to EthBlock at plasmaBlock-1 submission
@@ -260,9 +405,11 @@ func (plasma *Plasma) ethBlockPeg(plasmaBlockHeight *big.Int) (*big.Int, error)
 	} else {
 		blockIndex = lastCommittedBlock
 	}
+
 	submittedBlock, err := plasma.PlasmaChain(nil, blockIndex)
 	if err != nil {
 		return nil, err
 	}
+
 	return submittedBlock.EthBlockNum, nil
 }
@@ -105,7 +105,7 @@ func TestDepositFinalityBound(t *testing.T) {
 	plasmaContract, _ = plasmaContract.WithOperatorSession(privKey, commitmentRate)
 
 	// mine a block so that the headers channel is filled with a block
-	err := client.rpc.Call(nil, "evm_mine")
+	err := client.conn.rpc.Call(nil, "evm_mine")
 	require.NoError(t, err, "error mining a block")
 	time.Sleep(1 * time.Second)
 
@@ -144,14 +144,15 @@ func TestDepositFinalityBound(t *testing.T) {
 	require.NoError(t, err, "block submission error")
 
 	// Try to retrieve deposit from before peg
-	_, threshold, ok := plasmaContract.GetDeposit(big.NewInt(2), nonce)
+	_, threshold, ok, err := plasmaContract.GetDeposit(big.NewInt(2), pegToLatestBlock(t, client), nonce)
+	require.NoError(t, err, "error retrieving the deposit")
 	require.False(t, ok, "retrieved a deposit that occurred after pegged block")
 	require.Equal(t, big.NewInt(3), threshold, "Finality threshold calculated incorrectly. Should still need to wait two more blocks")
 
 	/* Mine 3 blocks for finality bound */
 	for i := 0; i < 3; i++ {
 		// mine another block so that the deposit falls outside the finality bound
-		err = client.rpc.Call(nil, "evm_mine")
+		err = client.conn.rpc.Call(nil, "evm_mine")
 		require.NoError(t, err, "error mining a block")
 		time.Sleep(1 * time.Second)
 	}
@@ -168,7 +169,8 @@ func TestDepositFinalityBound(t *testing.T) {
 	require.NoError(t, err, "block submission error")
 
 	// Try to retrieve deposit once peg has advanced AND finality bound reached.
-	deposit, threshold, ok := plasmaContract.GetDeposit(big.NewInt(4), nonce)
+	deposit, threshold, ok, err := plasmaContract.GetDeposit(big.NewInt(4), pegToLatestBlock(t, client), nonce)
+	require.NoError(t, err, "error retrieving the deposit")
 	require.True(t, ok, "could not retrieve a deposit that was deemed final")
 
 	require.Equal(t, uint64(10), deposit.Amount.Uint64(), "deposit amount mismatch")
@@ -0,0 +1,199 @@
+package eth
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// number of blocks beyond the finality bound whose hashes are recorded. Reorgs
+// deeper than the finality bound plus this window are not detected
+const reorgWindow = 256
+
+// Reorg describes a reorganization of the rootchain that replaced blocks the
+// sidechain relied upon
+type Reorg struct {
+	BlockNum   *big.Int    `json:"block_num"` // first replaced block
+	OldHash    common.Hash `json:"old_hash"`  // hash of the replaced block
+	NewHash    common.Hash `json:"new_hash"`  // hash of the canonical block. Zero if the chain no longer reaches BlockNum
+	Depth      uint64      `json:"depth"`     // number of recorded blocks replaced, at least
+	DetectedAt time.Time   `json:"detected_at"`
+}
+
+// ReorgStatus describes the reorgs of the rootchain detected by this node
+type ReorgStatus struct {
+	Halted bool    `json:"halted"` // deposit inclusion is halted after a reorg deeper than the finality bound
+	Reorgs []Reorg `json:"reorgs"` // every reorg detected since the node started. The most recent is last
+}
+
+// reorgTracker records the hashes of the recent canonical blocks, which cover
+// the blocks of the deposits that may be included, and detects when they are
+// replaced by a reorganization of the rootchain. Hashes are only retrieved when
+// syncing with the rootchain, never when a deposit is retrieved. Reorgs deeper than the finality bound may
+// have removed deposits that were already included, so deposit inclusion is
+// halted until the operator has reviewed the reorg and restarted the node
+type reorgTracker struct {
+	headerByNumber func(number *big.Int) (*types.Header, error)
+	finalityBound  uint64
+
+	mtx    sync.Mutex
+	hashes map[uint64]common.Hash // ethereum block number -> hash at the time it was recorded
+	head   *big.Int               // latest recorded block. nil until the first check
+	status ReorgStatus
+}
+
+func newReorgTracker(headerByNumber func(*big.Int) (*types.Header, error), finalityBound uint64) *reorgTracker {
+	return &reorgTracker{
+		headerByNumber: headerByNumber,
+		finalityBound:  finalityBound,
+		hashes:         make(map[uint64]common.Hash),
+	}
+}
+
+// check compares the recorded hashes against the canonical chain and records
+// the blocks up to `latestBlock`. The detected reorg is returned, nil if none
+func (t *reorgTracker) check(latestBlock *big.Int) (*Reorg, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	reorg, err := t.detect()
+	if err != nil {
+		return nil, err
+	}
+
+	if reorg != nil {
+		t.status.Reorgs = append(t.status.Reorgs, *reorg)
+		metrics.RootchainReorgs.Inc()
+
+		if reorg.Depth > t.finalityBound {
+			t.status.Halted = true
+			metrics.DepositsHalted.Set(1)
+			logger.Error(fmt.Sprintf("ALERT: rootchain reorg of %d blocks starting at block %s exceeds the finality bound of %d blocks. "+
+				"Deposits included into the sidechain may no longer exist. Deposit inclusion is halted until the node is restarted",
+				reorg.Depth, reorg.BlockNum, t.finalityBound))
+		} else {
+			logger.Info(fmt.Sprintf("rootchain reorg of %d blocks starting at block %s", reorg.Depth, reorg.BlockNum))
+		}
+	}
+
+	return reorg, t.record(latestBlock)
+}
+
+// detect walks the recorded blocks from the most recent one until a block that
+// is still canonical. Its ancestors are canonical as well. Replaced blocks are
+// forgotten so that they are recorded again from the canonical chain
+func (t *reorgTracker) detect() (*Reorg, error) {
+	numbers := make([]uint64, 0, len(t.hashes))
+	for number := range t.hashes {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] > numbers[j] })
+
+	var reorg *Reorg
+	for _, number := range numbers {
+		header, err := t.headerByNumber(new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, err
+		}
+
+		var hash common.Hash
+		if header != nil && header.Number.Uint64() == number {
+			hash = header.Hash()
+		}
+		if hash == t.hashes[number] {
+			break
+		}
+
+		reorg = &Reorg{
+			BlockNum: new(big.Int).SetUint64(number),
+			OldHash:  t.hashes[number],
+			NewHash:  hash,
+		}
+		delete(t.hashes, number)
+	}
+
+	if reorg == nil {
+		return nil, nil
+	}
+
+	reorg.Depth = numbers[0] - reorg.BlockNum.Uint64() + 1
+	reorg.DetectedAt = time.Now()
+	if t.head != nil && t.head.Cmp(reorg.BlockNum) >= 0 {
+		t.head = new(big.Int).Sub(reorg.BlockNum, big.NewInt(1))
+	}
+
+	return reorg, nil
+}
+
+// record adds the canonical blocks after the latest recorded block up to
+// `latestBlock` and forgets the blocks that have fallen out of the window.
+// The first check records the whole window so that the blocks of deposits
+// made before the node started are covered
+func (t *reorgTracker) record(latestBlock *big.Int) error {
+	latest := latestBlock.Uint64()
+	var oldest uint64
+	if latest > t.finalityBound+reorgWindow {
+		oldest = latest - t.finalityBound - reorgWindow
+	}
+
+	start := oldest
+	if t.head != nil && t.head.Uint64()+1 > oldest {
+		start = t.head.Uint64() + 1
+	}
+
+	for number := start; number <= latest; number++ {
+		header, err := t.headerByNumber(new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		} else if header == nil {
+			break
+		}
+
+		t.hashes[number] = header.Hash()
+		t.head = new(big.Int).SetUint64(number)
+	}
+
+	for number := range t.hashes {
+		if number < oldest {
+			delete(t.hashes, number)
+		}
+	}
+
+	return nil
+}
+
+// halted reports if a reorg deeper than the finality bound has been detected
+func (t *reorgTracker) halted() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return t.status.Halted
+}
+
+// reorgsSince returns the reorgs detected after the first `seen` along with
+// the total number of detected reorgs
+func (t *reorgTracker) reorgsSince(seen int) ([]Reorg, int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if seen >= len(t.status.Reorgs) {
+		return nil, len(t.status.Reorgs)
+	}
+
+	return append([]Reorg{}, t.status.Reorgs[seen:]...), len(t.status.Reorgs)
+}
+
+// getStatus returns a copy of the status
+func (t *reorgTracker) getStatus() ReorgStatus {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	status := t.status
+	status.Reorgs = append([]Reorg{}, t.status.Reorgs...)
+	return status
+}
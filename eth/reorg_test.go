@@ -0,0 +1,107 @@
+package eth
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// simulatedChain returns a simulated rootchain of `length` blocks. Forks of
+// the chain share the blocks before `forkAt` with the chain itself
+func simulatedChain(t *testing.T, length, forkAt uint64, fork bool) *backends.SimulatedBackend {
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{}, 8000000)
+	for number := uint64(1); number <= length; number++ {
+		if fork && number == forkAt {
+			require.NoError(t, backend.AdjustTime(time.Second))
+		}
+		backend.Commit()
+	}
+
+	return backend
+}
+
+// simulatedRootchain switches the backend the tracker reads from to simulate a reorg
+type simulatedRootchain struct {
+	backend *backends.SimulatedBackend
+}
+
+func (chain *simulatedRootchain) headerByNumber(number *big.Int) (*types.Header, error) {
+	return chain.backend.HeaderByNumber(context.Background(), number)
+}
+
+func (chain *simulatedRootchain) latestBlock() *big.Int {
+	header, _ := chain.backend.HeaderByNumber(context.Background(), nil)
+	return header.Number
+}
+
+func TestReorgDetection(t *testing.T) {
+	cases := []struct {
+		finalityBound uint64
+		halted        bool
+	}{
+		// replaced blocks 4-6 were presumed final
+		{2, true},
+		// replaced blocks 4-6 had not finalized
+		{3, false},
+	}
+
+	for i, c := range cases {
+		rootchain := &simulatedRootchain{simulatedChain(t, 1, 4, false)}
+		tracker := newReorgTracker(rootchain.headerByNumber, c.finalityBound)
+
+		// the tracker follows the canonical chain
+		for number := 1; number <= 6; number++ {
+			if number > 1 {
+				rootchain.backend.Commit()
+			}
+			reorg, err := tracker.check(rootchain.latestBlock())
+			require.NoError(t, err, "case %d", i)
+			require.Nil(t, reorg, "case %d: reorg detected on the canonical chain", i)
+		}
+		require.False(t, tracker.halted(), "case %d", i)
+
+		// a longer fork replaces blocks 4-6
+		rootchain.backend = simulatedChain(t, 7, 4, true)
+		reorg, err := tracker.check(rootchain.latestBlock())
+		require.NoError(t, err, "case %d", i)
+		require.NotNil(t, reorg, "case %d: reorg not detected", i)
+		require.Equal(t, big.NewInt(4), reorg.BlockNum, "case %d: wrong fork point", i)
+		require.Equal(t, uint64(3), reorg.Depth, "case %d: wrong reorg depth", i)
+		require.Equal(t, c.halted, tracker.halted(), "case %d", i)
+
+		// the fork is followed from now on
+		rootchain.backend.Commit()
+		reorg, err = tracker.check(rootchain.latestBlock())
+		require.NoError(t, err, "case %d", i)
+		require.Nil(t, reorg, "case %d: reorg detected twice", i)
+
+		reorgs, seen := tracker.reorgsSince(0)
+		require.Len(t, reorgs, 1, "case %d", i)
+		require.Equal(t, 1, seen, "case %d", i)
+		require.Equal(t, c.halted, tracker.getStatus().Halted, "case %d", i)
+	}
+}
+
+// Blocks of deposits made before the tracker started following the rootchain
+// are recorded by the first check
+func TestReorgBeforeFirstCheck(t *testing.T) {
+	rootchain := &simulatedRootchain{simulatedChain(t, 10, 3, false)}
+	tracker := newReorgTracker(rootchain.headerByNumber, 5)
+
+	_, err := tracker.check(rootchain.latestBlock())
+	require.NoError(t, err)
+	require.Len(t, tracker.hashes, 11, "blocks within the window not recorded")
+
+	// the deposit block is replaced by a fork
+	rootchain.backend = simulatedChain(t, 11, 3, true)
+	reorg, err := tracker.check(rootchain.latestBlock())
+	require.NoError(t, err)
+	require.NotNil(t, reorg, "reorg of the deposit block not detected")
+	require.Equal(t, big.NewInt(3), reorg.BlockNum)
+	require.True(t, tracker.halted(), "deposit inclusion not halted")
+}
@@ -0,0 +1,402 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	contracts "github.com/FourthState/plasma-mvp-sidechain/contracts/wrappers"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// gas estimates are padded by this percentage since the contract state
+	// may change between the estimate and the inclusion of the submission
+	gasLimitMargin = 20
+
+	// minimum percentage increase of the gas price required by ethereum
+	// nodes to replace a pending transaction with the same nonce
+	MinGasPriceBump = 10
+)
+
+// GasPriceStrategy determines the gas price of a block submission
+type GasPriceStrategy func(client Client) (*big.Int, error)
+
+// FixedGasPrice always uses `price`
+func FixedGasPrice(price *big.Int) GasPriceStrategy {
+	return func(client Client) (*big.Int, error) {
+		return new(big.Int).Set(price), nil
+	}
+}
+
+// SuggestedGasPrice uses the gas price suggested by the ethereum node. The price
+// is capped at `maxPrice` unless `maxPrice` is nil or zero
+func SuggestedGasPrice(maxPrice *big.Int) GasPriceStrategy {
+	return func(client Client) (*big.Int, error) {
+		price, err := client.ec.SuggestGasPrice(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("rpc: %s", err)
+		}
+
+		if maxPrice != nil && maxPrice.Sign() > 0 && price.Cmp(maxPrice) > 0 {
+			price = new(big.Int).Set(maxPrice)
+		}
+
+		return price, nil
+	}
+}
+
+// SubmissionConfig configures how the operator submits plasma blocks
+type SubmissionConfig struct {
+	GasPrice GasPriceStrategy
+
+	// percentage increase of the gas price when resubmitting a stuck submission
+	GasPriceBump uint64
+
+	// duration a submission may stay pending before it is resubmitted
+	Timeout time.Duration
+
+	// maximum number of plasma headers committed per submission. Later
+	// headers are committed by the following submissions
+	MaxBlocks int
+}
+
+// DefaultSubmissionConfig uses the suggested gas price, resubmits
+// submissions that have been pending for 5 minutes and commits up to 100
+// headers per submission
+func DefaultSubmissionConfig() SubmissionConfig {
+	return SubmissionConfig{
+		GasPrice:     SuggestedGasPrice(nil),
+		GasPriceBump: MinGasPriceBump,
+		Timeout:      5 * time.Minute,
+		MaxBlocks:    100,
+	}
+}
+
+// SubmissionStatus describes the latest block submission of the operator
+type SubmissionStatus struct {
+	Pending    bool        `json:"pending"`     // submission has not been mined yet
+	TxHash     common.Hash `json:"tx_hash"`     // hash of the latest attempt
+	Nonce      uint64      `json:"nonce"`       // nonce shared by every attempt
+	GasPrice   *big.Int    `json:"gas_price"`   // gas price of the latest attempt
+	FirstBlock *big.Int    `json:"first_block"` // first plasma block in the submission
+	NumBlocks  int         `json:"num_blocks"`  // number of plasma blocks in the submission
+	Attempts   int         `json:"attempts"`    // number of times the submission has been sent
+	SentAt     time.Time   `json:"sent_at"`     // time the latest attempt was sent
+	MinedAt    time.Time   `json:"mined_at"`    // time the submission was found to be mined
+	LastError  string      `json:"last_error"`
+}
+
+// submission holds a set of plasma headers and every transaction sent to commit them
+type submission struct {
+	firstBlock   *big.Int
+	headers      [][32]byte
+	txnsPerBlock []*big.Int
+	feesPerBlock []*big.Int
+
+	nonce    uint64
+	gasLimit uint64
+	gasPrice *big.Int
+	txHashes []common.Hash // every attempt. The latest attempt is last
+	sentAt   time.Time
+}
+
+// newSubmission collects the headers of up to `maxBlocks` consecutive plasma blocks in `ds`
+// starting at `firstBlock`. nil is returned if `firstBlock` has not been stored. The second
+// return value reports if later blocks were left out
+func newSubmission(ctx sdk.Context, ds store.DataStore, firstBlock *big.Int, maxBlocks int) (*submission, bool) {
+	s := &submission{firstBlock: new(big.Int).Set(firstBlock)}
+	blockNum := new(big.Int).Set(firstBlock)
+	block, ok := ds.GetBlock(ctx, blockNum)
+	if !ok {
+		return nil, false
+	}
+
+	for ok {
+		if len(s.headers) == maxBlocks {
+			return s, true
+		}
+
+		s.headers = append(s.headers, block.Header)
+		s.txnsPerBlock = append(s.txnsPerBlock, big.NewInt(int64(block.TxnCount)))
+		s.feesPerBlock = append(s.feesPerBlock, block.FeeAmount)
+
+		blockNum = blockNum.Add(blockNum, utils.Big1)
+		block, ok = ds.GetBlock(ctx, blockNum)
+	}
+
+	return s, false
+}
+
+// truncate keeps the first `n` headers of the submission
+func (s *submission) truncate(n int) {
+	s.headers = s.headers[:n]
+	s.txnsPerBlock = s.txnsPerBlock[:n]
+	s.feesPerBlock = s.feesPerBlock[:n]
+}
+
+// submissionManager commits plasma headers to the contract and tracks the
+// submission until it is mined. Submissions that are dropped or are stuck in
+// the mempool are resubmitted with a bumped gas price.
+type submissionManager struct {
+	contract     *contracts.PlasmaMVP
+	contractAddr common.Address
+	contractABI  abi.ABI
+	client       Client
+	from         common.Address
+	signer       bind.SignerFn
+
+	config              SubmissionConfig
+	commitmentRate      time.Duration
+	lastBlockSubmission time.Time
+	verified            bool // `from` has been verified to be the operator of the contract
+	backlog             bool // the latest submission left out uncommitted headers
+
+	mtx     sync.Mutex
+	pending *submission
+	status  SubmissionStatus
+}
+
+func newSubmissionManager(contract *contracts.PlasmaMVP, contractAddr common.Address, client Client,
+	auth *bind.TransactOpts, commitmentRate time.Duration) (*submissionManager, error) {
+
+	contractABI, err := abi.JSON(strings.NewReader(contracts.PlasmaMVPABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &submissionManager{
+		contract:            contract,
+		contractAddr:        contractAddr,
+		contractABI:         contractABI,
+		client:              client,
+		from:                auth.From,
+		signer:              auth.Signer,
+		config:              DefaultSubmissionConfig(),
+		commitmentRate:      commitmentRate,
+		lastBlockSubmission: time.Now(),
+	}, nil
+}
+
+// commit checks on the pending submission and, once the commitment rate has
+// passed since the last mined submission, submits the uncommitted headers in
+// batches of at most `MaxBlocks`. The batches are committed in order, each
+// once the previous one is mined, without waiting for the commitment rate
+func (m *submissionManager) commit(ctx sdk.Context, ds store.DataStore) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	err := m.tryCommit(ctx, ds)
+	if err != nil {
+		m.status.LastError = err.Error()
+	}
+
+	return err
+}
+
+func (m *submissionManager) tryCommit(ctx sdk.Context, ds store.DataStore) error {
+	if m.pending != nil {
+		if mined, err := m.checkPending(); err != nil || !mined {
+			return err
+		}
+	}
+
+	if !m.backlog && time.Since(m.lastBlockSubmission) < m.commitmentRate {
+		return nil
+	}
+
+	if !m.verified {
+		operator, err := m.contract.Operator(nil)
+		if err != nil {
+			return fmt.Errorf("unable to verify the operator address: %s", err)
+		}
+		if operator != m.from {
+			return fmt.Errorf("operator address mismatch. Got 0x%x. Expected:0x%x", m.from, operator)
+		}
+		m.verified = true
+	}
+
+	logger.Info("attempting to commit plasma headers...")
+
+	lastCommittedBlock, err := m.contract.LastCommittedBlock(nil)
+	if err != nil {
+		logger.Error("error retrieving the last committed block number")
+		return err
+	}
+
+	s, backlog := newSubmission(ctx, ds, new(big.Int).Add(lastCommittedBlock, utils.Big1), m.config.MaxBlocks)
+	if s == nil { // no blocks to submit
+		logger.Info("no plasma blocks to commit")
+		m.backlog = false
+		return nil
+	}
+
+	// a batch exceeding the block gas limit fails the estimation. It is split until it fits
+	for {
+		if s.gasLimit, err = m.estimateGas(s); err == nil {
+			break
+		} else if len(s.headers) == 1 {
+			return err
+		}
+
+		logger.Info(fmt.Sprintf("splitting the submission of %d plasma blocks: %s", len(s.headers), err))
+		s.truncate(len(s.headers) / 2)
+		backlog = true
+	}
+	m.backlog = backlog
+
+	if s.nonce, err = m.client.ec.PendingNonceAt(context.Background(), m.from); err != nil {
+		return fmt.Errorf("rpc: %s", err)
+	}
+	if s.gasPrice, err = m.config.GasPrice(m.client); err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("committing %d plasma blocks. first block num: %s", len(s.headers), s.firstBlock))
+	if err := m.send(s); err != nil {
+		logger.Error(fmt.Sprintf("error committing headers %s", err))
+		return err
+	}
+
+	m.pending = s
+	return nil
+}
+
+// checkPending looks for a receipt of any attempt of the pending submission. The
+// submission is resubmitted if it has not been mined within the timeout
+func (m *submissionManager) checkPending() (bool, error) {
+	p := m.pending
+
+	// retrieve the nonce before the receipts so that a mined attempt is never
+	// mistaken for the nonce being used by another transaction
+	nonce, err := m.client.ec.NonceAt(context.Background(), m.from, nil)
+	if err != nil {
+		return false, fmt.Errorf("rpc: %s", err)
+	}
+
+	for _, hash := range p.txHashes {
+		receipt, err := m.client.ec.TransactionReceipt(context.Background(), hash)
+		if err == ethereum.NotFound {
+			continue
+		} else if err != nil {
+			return false, fmt.Errorf("rpc: %s", err)
+		}
+
+		m.pending = nil
+		m.status.Pending = false
+		m.status.TxHash = hash
+		if receipt.Status == types.ReceiptStatusFailed {
+			// the headers are resubmitted based on the contract state
+			return true, fmt.Errorf("block submission 0x%x reverted", hash)
+		}
+
+		logger.Info(fmt.Sprintf("committed %d plasma blocks in tx 0x%x", len(p.headers), hash))
+		m.lastBlockSubmission = time.Now()
+		m.status.MinedAt = m.lastBlockSubmission
+		m.status.LastError = ""
+		return true, nil
+	}
+
+	// the nonce has been used by a transaction other than the submission
+	if nonce > p.nonce {
+		logger.Error(fmt.Sprintf("nonce %d of the block submission was used by another transaction", p.nonce))
+		m.pending = nil
+		m.status.Pending = false
+		return true, nil
+	}
+
+	if time.Since(p.sentAt) < m.config.Timeout {
+		return false, nil
+	}
+
+	// the submission was dropped or is stuck. Replace it with a higher gas price
+	price, err := m.config.GasPrice(m.client)
+	if err != nil {
+		return false, err
+	}
+	bumped := bumpGasPrice(p.gasPrice, m.config.GasPriceBump)
+	if price.Cmp(bumped) < 0 {
+		price = bumped
+	}
+	p.gasPrice = price
+
+	logger.Info(fmt.Sprintf("resubmitting plasma headers with nonce %d and gas price %s", p.nonce, p.gasPrice))
+	return false, m.send(p)
+}
+
+// send signs and sends an attempt of the submission
+func (m *submissionManager) send(s *submission) error {
+	opts := &bind.TransactOpts{
+		From:     m.from,
+		Signer:   m.signer,
+		Nonce:    new(big.Int).SetUint64(s.nonce),
+		GasPrice: s.gasPrice,
+		GasLimit: s.gasLimit,
+	}
+
+	tx, err := m.contract.SubmitBlock(opts, s.headers, s.txnsPerBlock, s.feesPerBlock, s.firstBlock)
+	if err != nil {
+		return err
+	}
+
+	s.txHashes = append(s.txHashes, tx.Hash())
+	s.sentAt = time.Now()
+
+	m.status = SubmissionStatus{
+		Pending:    true,
+		TxHash:     tx.Hash(),
+		Nonce:      s.nonce,
+		GasPrice:   s.gasPrice,
+		FirstBlock: s.firstBlock,
+		NumBlocks:  len(s.headers),
+		Attempts:   len(s.txHashes),
+		SentAt:     s.sentAt,
+		MinedAt:    m.status.MinedAt,
+	}
+
+	return nil
+}
+
+// estimateGas estimates the gas used by the submission padded by `gasLimitMargin`
+func (m *submissionManager) estimateGas(s *submission) (uint64, error) {
+	data, err := m.contractABI.Pack("submitBlock", s.headers, s.txnsPerBlock, s.feesPerBlock, s.firstBlock)
+	if err != nil {
+		return 0, fmt.Errorf("abi: %s", err)
+	}
+
+	msg := ethereum.CallMsg{From: m.from, To: &m.contractAddr, Data: data}
+	gas, err := m.client.ec.EstimateGas(context.Background(), msg)
+	if err != nil {
+		return 0, fmt.Errorf("gas estimation: %s", err)
+	}
+
+	return gas + gas*gasLimitMargin/100, nil
+}
+
+// getStatus returns a copy of the status of the latest submission
+func (m *submissionManager) getStatus() SubmissionStatus {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.status
+}
+
+// bumpGasPrice increases `price` by `percentage`. The increase is at least 1 wei
+func bumpGasPrice(price *big.Int, percentage uint64) *big.Int {
+	increase := new(big.Int).Mul(price, new(big.Int).SetUint64(percentage))
+	increase = increase.Div(increase, big.NewInt(100))
+	if increase.Sign() == 0 {
+		increase = utils.Big1
+	}
+
+	return new(big.Int).Add(price, increase)
+}
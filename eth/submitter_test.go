@@ -0,0 +1,126 @@
+package eth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestGasPriceBump(t *testing.T) {
+	cases := []struct {
+		price, bump, expected int64
+	}{
+		{100, 10, 110},
+		{1000000000, 25, 1250000000},
+		// the price is increased by at least 1 wei
+		{5, 10, 6},
+		{0, 10, 1},
+	}
+
+	for i, c := range cases {
+		bumped := bumpGasPrice(big.NewInt(c.price), uint64(c.bump))
+		require.Equal(t, big.NewInt(c.expected), bumped, "case %d: incorrect bumped gas price", i)
+	}
+}
+
+// Uncommitted headers are batched in order with at most `maxBlocks` per submission
+func TestNewSubmission(t *testing.T) {
+	ctx, blockStore := setup()
+
+	s, backlog := newSubmission(ctx, blockStore, big.NewInt(1), 2)
+	require.Nil(t, s, "submission created without stored blocks")
+	require.False(t, backlog)
+
+	for i := int64(1); i <= 5; i++ {
+		blockStore.StoreBlock(ctx, uint64(i), plasma.Block{
+			Header:    sha256.Sum256([]byte(fmt.Sprintf("Block: %d", i))),
+			TxnCount:  uint16(i),
+			FeeAmount: big.NewInt(i),
+		})
+	}
+
+	for first, batches := int64(1), 0; first <= 5; first, batches = first+2, batches+1 {
+		s, backlog = newSubmission(ctx, blockStore, big.NewInt(first), 2)
+		require.NotNil(t, s, "batch %d: no submission", batches)
+		require.Equal(t, big.NewInt(first), s.firstBlock, "batch %d: incorrect first block", batches)
+		require.Equal(t, first+2 <= 5, backlog, "batch %d: incorrect backlog", batches)
+
+		for i, header := range s.headers {
+			num := first + int64(i)
+			require.Equal(t, sha256.Sum256([]byte(fmt.Sprintf("Block: %d", num))), header, "batch %d: headers out of order", batches)
+			require.Equal(t, big.NewInt(num), s.txnsPerBlock[i])
+			require.Equal(t, big.NewInt(num), s.feesPerBlock[i])
+		}
+	}
+	require.Len(t, s.headers, 1, "last batch should contain the remaining header")
+
+	// a split submission keeps its first headers
+	s, backlog = newSubmission(ctx, blockStore, big.NewInt(1), 5)
+	require.False(t, backlog)
+	s.truncate(2)
+	require.Len(t, s.headers, 2)
+	require.Len(t, s.txnsPerBlock, 2)
+	require.Len(t, s.feesPerBlock, 2)
+	require.Equal(t, big.NewInt(2), s.feesPerBlock[1])
+}
+
+// The submission must be tracked until it is mined
+func TestSubmissionTracking(t *testing.T) {
+	client, _ := InitEthConn(clientAddr)
+	privKey, _ := crypto.HexToECDSA(operatorPrivKey)
+	plasmaContract, _ := InitPlasma(common.HexToAddress(plasmaContractAddr), client, 1)
+	plasmaContract, _ = plasmaContract.WithOperatorSession(privKey, commitmentRate)
+
+	_, err := plasmaContract.WithSubmissionConfig(SubmissionConfig{GasPrice: FixedGasPrice(big.NewInt(1)), GasPriceBump: 1, MaxBlocks: 1})
+	require.Error(t, err, "accepted a gas price bump that would not replace a pending transaction")
+	plasmaContract, err = plasmaContract.WithSubmissionConfig(SubmissionConfig{
+		GasPrice:     FixedGasPrice(big.NewInt(20000000000)),
+		GasPriceBump: MinGasPriceBump,
+		Timeout:      time.Minute,
+		MaxBlocks:    100,
+	})
+	require.NoError(t, err)
+
+	ctx, blockStore := setup()
+
+	// restore the blocks submitted by previous tests
+	lastCommittedBlock, err := plasmaContract.LastCommittedBlock(nil)
+	require.NoError(t, err)
+	for i := int64(1); i <= lastCommittedBlock.Int64()+1; i++ {
+		blockStore.StoreBlock(ctx, uint64(i), plasma.Block{
+			Header:    sha256.Sum256([]byte(fmt.Sprintf("Block: %d", i))),
+			TxnCount:  uint16(1),
+			FeeAmount: big.NewInt(0),
+		})
+	}
+
+	time.Sleep(2 * time.Second)
+
+	err = plasmaContract.CommitPlasmaHeaders(ctx, blockStore)
+	require.NoError(t, err, "block submission error")
+
+	status := plasmaContract.SubmissionStatus()
+	require.True(t, status.Pending, "submission not tracked")
+	require.Equal(t, 1, status.Attempts)
+	require.Equal(t, 1, status.NumBlocks)
+	require.Equal(t, new(big.Int).Add(lastCommittedBlock, big.NewInt(1)), status.FirstBlock)
+	require.Equal(t, big.NewInt(20000000000), status.GasPrice)
+
+	// the receipt is picked up by the next commitment
+	err = plasmaContract.CommitPlasmaHeaders(ctx, blockStore)
+	require.NoError(t, err, "block submission error")
+
+	status = plasmaContract.SubmissionStatus()
+	require.False(t, status.Pending, "mined submission still pending")
+	require.False(t, status.MinedAt.IsZero(), "mined time not set")
+
+	committedBlock, err := plasmaContract.LastCommittedBlock(nil)
+	require.NoError(t, err)
+	require.Equal(t, status.FirstBlock, committedBlock, "submission not mined")
+}
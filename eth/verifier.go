@@ -0,0 +1,156 @@
+package eth
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/metrics"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"math/big"
+	"sync"
+)
+
+// number of mismatches kept in the verification status. Older mismatches are dropped
+const maxRecordedMismatches = 100
+
+// key of the last verified block in the database of the verifier
+var lastVerifiedBlockKey = []byte("lastVerifiedBlock")
+
+// CommittedBlock is the header information of a plasma block
+type CommittedBlock struct {
+	Header    common.Hash `json:"header"`
+	NumTxns   *big.Int    `json:"num_txns"`
+	FeeAmount *big.Int    `json:"fee_amount"`
+}
+
+// HeaderMismatch describes a plasma block committed to the rootchain that does
+// not match the block in the local store
+type HeaderMismatch struct {
+	BlockNumber *big.Int       `json:"block_number"`
+	Committed   CommittedBlock `json:"committed"`
+	Local       CommittedBlock `json:"local"`
+}
+
+// VerificationStatus describes the verification of the committed plasma blocks
+type VerificationStatus struct {
+	LastVerifiedBlock *big.Int         `json:"last_verified_block"`
+	Mismatches        []HeaderMismatch `json:"mismatches"` // latest mismatches. The most recent is last
+	LastError         string           `json:"last_error"`
+}
+
+// HeaderVerifier checks that the plasma blocks committed to the rootchain by
+// the operator match the blocks agreed upon by the validators. The last
+// verified block is persisted so that verification resumes from it after a
+// restart
+type HeaderVerifier struct {
+	lastCommittedBlock func() (*big.Int, error)
+	committedBlock     func(blockNum *big.Int) (CommittedBlock, error)
+	db                 dbm.DB
+
+	mtx    sync.Mutex
+	next   *big.Int // next block to verify
+	status VerificationStatus
+}
+
+// NewHeaderVerifier returns a verifier of the blocks committed to the plasma contract. Progress is
+// persisted in `db`, local to this node
+func (plasma *Plasma) NewHeaderVerifier(db dbm.DB) *HeaderVerifier {
+	return newHeaderVerifier(db,
+		func() (*big.Int, error) {
+			return plasma.LastCommittedBlock(nil)
+		},
+		func(blockNum *big.Int) (CommittedBlock, error) {
+			block, err := plasma.PlasmaChain(nil, blockNum)
+			if err != nil {
+				return CommittedBlock{}, err
+			}
+
+			return CommittedBlock{block.Header, block.NumTxns, block.FeeAmount}, nil
+		})
+}
+
+func newHeaderVerifier(db dbm.DB, lastCommittedBlock func() (*big.Int, error), committedBlock func(*big.Int) (CommittedBlock, error)) *HeaderVerifier {
+	lastVerifiedBlock := big.NewInt(0)
+	if data := db.Get(lastVerifiedBlockKey); data != nil {
+		lastVerifiedBlock.SetBytes(data)
+	}
+
+	return &HeaderVerifier{
+		lastCommittedBlock: lastCommittedBlock,
+		committedBlock:     committedBlock,
+		db:                 db,
+		next:               new(big.Int).Add(lastVerifiedBlock, utils.Big1),
+		status:             VerificationStatus{LastVerifiedBlock: lastVerifiedBlock},
+	}
+}
+
+// Verify compares the committed blocks that have not been verified yet against
+// the blocks in `ds`, starting after the last verified block. Blocks that have
+// not been stored locally are verified by a later call. The mismatches found
+// are returned
+func (v *HeaderVerifier) Verify(ctx sdk.Context, ds store.DataStore) ([]HeaderMismatch, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	mismatches, err := v.verify(ctx, ds)
+	v.db.SetSync(lastVerifiedBlockKey, v.status.LastVerifiedBlock.Bytes())
+	if err != nil {
+		v.status.LastError = err.Error()
+	} else {
+		v.status.LastError = ""
+	}
+
+	v.status.Mismatches = append(v.status.Mismatches, mismatches...)
+	if n := len(v.status.Mismatches); n > maxRecordedMismatches {
+		v.status.Mismatches = v.status.Mismatches[n-maxRecordedMismatches:]
+	}
+
+	return mismatches, err
+}
+
+func (v *HeaderVerifier) verify(ctx sdk.Context, ds store.DataStore) ([]HeaderMismatch, error) {
+	lastCommittedBlock, err := v.lastCommittedBlock()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving the last committed block: %s", err)
+	}
+
+	var mismatches []HeaderMismatch
+	for ; v.next.Cmp(lastCommittedBlock) <= 0; v.next = new(big.Int).Add(v.next, utils.Big1) {
+		block, ok := ds.GetBlock(ctx, v.next)
+		if !ok { // the local store has not caught up
+			break
+		}
+
+		committed, err := v.committedBlock(v.next)
+		if err != nil {
+			return mismatches, fmt.Errorf("retrieving committed block %s: %s", v.next, err)
+		}
+
+		local := CommittedBlock{
+			Header:    block.Header,
+			NumTxns:   big.NewInt(int64(block.TxnCount)),
+			FeeAmount: block.FeeAmount,
+		}
+		if committed.Header != local.Header || committed.NumTxns.Cmp(local.NumTxns) != 0 || committed.FeeAmount.Cmp(local.FeeAmount) != 0 {
+			mismatches = append(mismatches, HeaderMismatch{new(big.Int).Set(v.next), committed, local})
+			metrics.HeaderMismatches.Inc()
+		}
+
+		v.status.LastVerifiedBlock = new(big.Int).Set(v.next)
+		metrics.LastVerifiedBlock.Set(float64(v.next.Int64()))
+	}
+
+	return mismatches, nil
+}
+
+// Status returns the status of the verification
+func (v *HeaderVerifier) Status() VerificationStatus {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	status := v.status
+	status.Mismatches = append([]HeaderMismatch{}, v.status.Mismatches...)
+	return status
+}
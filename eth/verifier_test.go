@@ -0,0 +1,117 @@
+package eth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"math/big"
+	"testing"
+)
+
+// Committed blocks must be compared against the local store as it catches up
+func TestHeaderVerification(t *testing.T) {
+	ctx, ds := setup()
+
+	header := func(i int64) [32]byte {
+		return sha256.Sum256([]byte(fmt.Sprintf("Block: %d", i)))
+	}
+	for i := int64(1); i <= 3; i++ {
+		ds.StoreBlock(ctx, uint64(i), plasma.Block{Header: header(i), TxnCount: 1, FeeAmount: big.NewInt(i)})
+	}
+
+	// the operator committed 4 blocks, the 3rd of which is not the local block
+	committed := map[int64]CommittedBlock{}
+	for i := int64(1); i <= 4; i++ {
+		committed[i] = CommittedBlock{header(i), big.NewInt(1), big.NewInt(i)}
+	}
+	committed[3] = CommittedBlock{header(5), big.NewInt(1), big.NewInt(3)}
+
+	// nothing had been committed when the verifier started
+	var rootchainErr error
+	lastCommittedBlock := big.NewInt(0)
+	lastCommitted := func() (*big.Int, error) {
+		return lastCommittedBlock, rootchainErr
+	}
+	committedBlock := func(blockNum *big.Int) (CommittedBlock, error) {
+		return committed[blockNum.Int64()], nil
+	}
+	db := dbm.NewMemDB()
+	verifier := newHeaderVerifier(db, lastCommitted, committedBlock)
+
+	mismatches, err := verifier.Verify(ctx, ds)
+	require.NoError(t, err)
+	require.Empty(t, mismatches)
+
+	lastCommittedBlock = big.NewInt(4)
+	mismatches, err = verifier.Verify(ctx, ds)
+	require.NoError(t, err)
+	require.Len(t, mismatches, 1)
+	require.Equal(t, big.NewInt(3), mismatches[0].BlockNumber)
+	require.Equal(t, committed[3], mismatches[0].Committed)
+
+	// the 4th block is verified once it is stored
+	status := verifier.Status()
+	require.Equal(t, big.NewInt(3), status.LastVerifiedBlock)
+	require.Len(t, status.Mismatches, 1)
+
+	ds.StoreBlock(ctx, 4, plasma.Block{Header: header(4), TxnCount: 1, FeeAmount: big.NewInt(4)})
+	mismatches, err = verifier.Verify(ctx, ds)
+	require.NoError(t, err)
+	require.Empty(t, mismatches, "verified blocks compared again")
+	require.Equal(t, big.NewInt(4), verifier.Status().LastVerifiedBlock)
+
+	// rootchain errors are recorded
+	rootchainErr = errors.New("unavailable")
+	_, err = verifier.Verify(ctx, ds)
+	require.Error(t, err)
+	require.NotEmpty(t, verifier.Status().LastError)
+}
+
+// A restarted verifier must resume after the last verified block, including the blocks committed while it was offline
+func TestHeaderVerificationRestart(t *testing.T) {
+	ctx, ds := setup()
+
+	header := func(i int64) [32]byte {
+		return sha256.Sum256([]byte(fmt.Sprintf("Block: %d", i)))
+	}
+	committed := map[int64]CommittedBlock{}
+	for i := int64(1); i <= 5; i++ {
+		ds.StoreBlock(ctx, uint64(i), plasma.Block{Header: header(i), TxnCount: 1, FeeAmount: big.NewInt(i)})
+		committed[i] = CommittedBlock{header(i), big.NewInt(1), big.NewInt(i)}
+	}
+	// the 4th block is committed with a different header while the node is offline
+	committed[4] = CommittedBlock{header(6), big.NewInt(1), big.NewInt(4)}
+
+	lastCommittedBlock := big.NewInt(2)
+	lastCommitted := func() (*big.Int, error) {
+		return lastCommittedBlock, nil
+	}
+	var compared []int64
+	committedBlock := func(blockNum *big.Int) (CommittedBlock, error) {
+		compared = append(compared, blockNum.Int64())
+		return committed[blockNum.Int64()], nil
+	}
+
+	db := dbm.NewMemDB()
+	verifier := newHeaderVerifier(db, lastCommitted, committedBlock)
+	mismatches, err := verifier.Verify(ctx, ds)
+	require.NoError(t, err)
+	require.Empty(t, mismatches)
+	require.Equal(t, []int64{1, 2}, compared)
+
+	// blocks 3 to 5 are committed before the node restarts
+	lastCommittedBlock = big.NewInt(5)
+	compared = nil
+	verifier = newHeaderVerifier(db, lastCommitted, committedBlock)
+	require.Equal(t, big.NewInt(2), verifier.Status().LastVerifiedBlock, "last verified block not restored")
+
+	mismatches, err = verifier.Verify(ctx, ds)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 4, 5}, compared, "verified blocks compared again or committed blocks skipped")
+	require.Len(t, mismatches, 1)
+	require.Equal(t, big.NewInt(4), mismatches[0].BlockNumber)
+	require.Equal(t, big.NewInt(5), verifier.Status().LastVerifiedBlock)
+}
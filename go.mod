@@ -16,11 +16,13 @@ require (
 	github.com/fortytw2/leaktest v1.3.0 // indirect
 	github.com/google/uuid v1.1.1 // indirect
 	github.com/gorilla/mux v1.7.0
+	github.com/gorilla/websocket v1.4.1-0.20190629185528-ae1634f6a989
 	github.com/jmhodges/levigo v1.0.0 // indirect
 	github.com/mattn/go-colorable v0.1.1 // indirect
 	github.com/mattn/go-isatty v0.0.6 // indirect
 	github.com/pborman/uuid v0.0.0-20180906182336-adf5a7427709 // indirect
 	github.com/pelletier/go-toml v1.6.0 // indirect
+	github.com/prometheus/client_golang v0.9.3
 	github.com/rakyll/statik v0.1.6 // indirect
 	github.com/rcrowley/go-metrics v0.0.0-20180503174638-e2704e165165 // indirect
 	github.com/rjeczalik/notify v0.9.2 // indirect
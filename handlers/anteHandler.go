@@ -13,30 +13,95 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"math/big"
+	"time"
 )
 
 // the reason for an interface is to allow the connection object
 // to be cooked when testing the ante handler
 type plasmaConn interface {
-	GetDeposit(*big.Int, *big.Int) (plasma.Deposit, *big.Int, bool)
-	HasTxExited(*big.Int, plasma.Position) (bool, error)
+	GetDeposit(*big.Int, time.Time, *big.Int) (plasma.Deposit, *big.Int, bool, error)
+	HasTxExited(*big.Int, time.Time, plasma.Position) (bool, error)
+	RootchainAvailable() bool
+	DepositsHalted() bool
+	ContractAddress() common.Address
 }
 
-// NewAnteHandler returns an ante handler capable of handling include_deposit
-// and spend_utxo Msgs.
-func NewAnteHandler(ds store.DataStore, client plasmaConn) sdk.AnteHandler {
+// NewAnteHandler returns an ante handler capable of handling include_deposit,
+// spend_utxo, update_fee_address and update_validator Msgs. Spends entering the mempool must pay the fee required
+// by the current policy of `fees`, if not nil. Spends and deposits are admitted within the limits of
+// `limiter`, if not nil.
+func NewAnteHandler(ds store.DataStore, client plasmaConn, fees *FeeSchedule, limiter *MempoolLimiter) sdk.AnteHandler {
 	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (newCtx sdk.Context, res sdk.Result, abort bool) {
 		msg := tx.GetMsgs()[0] // tx should only have one msg
-		switch mtype := msg.Type(); mtype {
-		case "include_deposit":
-			depositMsg := msg.(msgs.IncludeDepositMsg)
-			return includeDepositAnteHandler(ctx, ds, depositMsg, client)
-		case "spend_utxo":
-			spendMsg := msg.(msgs.SpendMsg)
-			return spendMsgAnteHandler(ctx, ds, spendMsg, client)
+		mtype := msg.Type()
+		switch mtype {
+		case "include_deposit", "spend_utxo", "update_fee_address", "update_validator":
 		default:
-			return ctx, ErrInvalidTransaction("msg is not of type SpendMsg or IncludeDepositMsg").Result(), true
+			return ctx, ErrInvalidTransaction("msg is not of type SpendMsg, IncludeDepositMsg, UpdateFeeAddressMsg or UpdateValidatorMsg").Result(), true
+		}
+
+		// msgs are validated against the deposit and exit state of the rootchain. The
+		// availability of the rootchain is local to this node, so it only gates the
+		// admission into the mempool. A committed block fails with ErrRootchainUnavailable
+		// only if the rootchain state has not been mirrored yet, and is delivered again
+		if ctx.IsCheckTx() && !client.RootchainAvailable() {
+			return ctx, ErrRootchainUnavailable("rootchain unavailable. Resubmit the transaction once the connection is restored").Result(), true
+		}
+
+		if mtype != "spend_utxo" {
+			validate := func() (sdk.Context, sdk.Result, bool) {
+				switch msg := msg.(type) {
+				case msgs.IncludeDepositMsg:
+					return includeDepositAnteHandler(ctx, ds, msg, client)
+				case msgs.UpdateFeeAddressMsg:
+					return feeAddressAnteHandler(ctx, ds, msg, client)
+				default:
+					return validatorAnteHandler(ctx, ds, msg.(msgs.UpdateValidatorMsg), client)
+				}
+			}
+			if !ctx.IsCheckTx() || limiter == nil {
+				return validate()
+			}
+
+			// deposits, fee address and validator updates take up a position in the block as well
+			if err := limiter.checkCapacity(); err != nil {
+				return ctx, err.Result(), true
+			}
+			newCtx, res, abort = validate()
+			if !abort {
+				limiter.add(nil)
+			}
+			return newCtx, res, abort
+		}
+
+		spendMsg := msg.(msgs.SpendMsg)
+		if !ctx.IsCheckTx() {
+			return spendMsgAnteHandler(ctx, ds, spendMsg, client)
+		}
+
+		// mempool admission
+		if fees != nil {
+			if err := checkFee(fees.Policy(), spendMsg.Fee, ctx.TxBytes()); err != nil {
+				return ctx, err.Result(), true
+			}
+		}
+		if limiter == nil {
+			return spendMsgAnteHandler(ctx, ds, spendMsg, client)
+		}
+
+		var signers []common.Address
+		for _, signer := range spendMsg.GetSigners() {
+			signers = append(signers, common.BytesToAddress(signer))
+		}
+		if err := limiter.check(signers, spendMsg.Fee); err != nil {
+			return ctx, err.Result(), true
 		}
+
+		newCtx, res, abort = spendMsgAnteHandler(ctx, ds, spendMsg, client)
+		if !abort {
+			limiter.add(signers)
+		}
+		return newCtx, res, abort
 	}
 }
 
@@ -100,9 +165,9 @@ func validateInput(ctx sdk.Context, ds store.DataStore, input plasma.Input, sign
 	if inputUTXO.Spent {
 		return nil, ErrInvalidInput("input, %v, already spent", input.Position).Result()
 	}
-	exited, err := client.HasTxExited(ds.PlasmaBlockHeight(ctx), input.Position)
+	exited, err := client.HasTxExited(ds.PlasmaBlockHeight(ctx), ctx.BlockHeader().Time, input.Position)
 	if err != nil {
-		return nil, ErrInvalidInput("failed to retrieve exit information on input, %v", input.Position).Result()
+		return nil, ErrRootchainUnavailable("failed to retrieve exit information on input, %v: %s", input.Position, err).Result()
 	} else if exited {
 		return nil, ErrExitedInput("input, %v, utxo has exitted", input.Position).Result()
 	}
@@ -121,9 +186,9 @@ func validateInput(ctx sdk.Context, ds store.DataStore, input plasma.Input, sign
 
 		// check if the parent utxo has exited
 		for _, in := range tx.Transaction.Inputs {
-			exited, err = client.HasTxExited(ds.PlasmaBlockHeight(ctx), in.Position)
+			exited, err = client.HasTxExited(ds.PlasmaBlockHeight(ctx), ctx.BlockHeader().Time, in.Position)
 			if err != nil {
-				return nil, ErrInvalidInput(fmt.Sprintf("failed to retrieve exit information on input, %v", in.Position)).Result()
+				return nil, ErrRootchainUnavailable("failed to retrieve exit information on input, %v: %s", in.Position, err).Result()
 			} else if exited {
 				return nil, ErrExitedInput(fmt.Sprintf("a parent of the input has exited. Position: %v", in.Position)).Result()
 			}
@@ -162,7 +227,15 @@ func includeDepositAnteHandler(ctx sdk.Context, ds store.DataStore, msg msgs.Inc
 	if ds.HasDeposit(ctx, msg.DepositNonce) {
 		return ctx, ErrInvalidTransaction("deposit, %s, already exists in store", msg.DepositNonce.String()).Result(), true
 	}
-	deposit, threshold, ok := client.GetDeposit(ds.PlasmaBlockHeight(ctx), msg.DepositNonce)
+	// the halt is local to this node, so it only keeps deposits out of the mempool
+	if ctx.IsCheckTx() && client.DepositsHalted() {
+		ctx.Logger().Error(fmt.Sprintf("ALERT: deposit %s rejected. deposit inclusion is halted after a rootchain reorg deeper than the finality bound", msg.DepositNonce))
+		return ctx, ErrRootchainUnavailable("deposit inclusion halted after a rootchain reorg deeper than the finality bound").Result(), true
+	}
+	deposit, threshold, ok, err := client.GetDeposit(ds.PlasmaBlockHeight(ctx), ctx.BlockHeader().Time, msg.DepositNonce)
+	if err != nil {
+		return ctx, ErrRootchainUnavailable("failed to retrieve deposit, %s: %s", msg.DepositNonce, err).Result(), true
+	}
 	if !ok && threshold == nil {
 		return ctx, ErrInvalidTransaction("deposit, %s, does not exist.", msg.DepositNonce.String()).Result(), true
 	}
@@ -174,9 +247,9 @@ func includeDepositAnteHandler(ctx sdk.Context, ds store.DataStore, msg msgs.Inc
 	}
 
 	depositPosition := plasma.NewPosition(big.NewInt(0), 0, 0, msg.DepositNonce)
-	exited, err := client.HasTxExited(ds.PlasmaBlockHeight(ctx), depositPosition)
+	exited, err := client.HasTxExited(ds.PlasmaBlockHeight(ctx), ctx.BlockHeader().Time, depositPosition)
 	if err != nil {
-		return ctx, ErrInvalidTransaction("failed to retrieve deposit information for deposit, %s", msg.DepositNonce.String()).Result(), true
+		return ctx, ErrRootchainUnavailable("failed to retrieve exit information for deposit, %s: %s", msg.DepositNonce, err).Result(), true
 	} else if exited {
 		return ctx, ErrInvalidTransaction("deposit, %s, has already exitted from rootchain", msg.DepositNonce.String()).Result(), true
 	}
@@ -185,3 +258,74 @@ func includeDepositAnteHandler(ctx sdk.Context, ds store.DataStore, msg msgs.Inc
 	}
 	return ctx, sdk.Result{}, false
 }
+
+// validates that the fee address update is signed by the current fee address
+// over the nonce of the next update
+func feeAddressAnteHandler(ctx sdk.Context, ds store.DataStore, msg msgs.UpdateFeeAddressMsg, client plasmaConn) (newCtx sdk.Context, res sdk.Result, abort bool) {
+	if contract := client.ContractAddress(); msg.PlasmaContract != contract {
+		return ctx, ErrInvalidTransaction("fee address update is for the contract 0x%x. Expected: 0x%x", msg.PlasmaContract, contract).Result(), true
+	}
+	if nonce := ds.GetFeeAddressNonce(ctx); msg.Nonce != nonce {
+		return ctx, ErrInvalidTransaction(fmt.Sprintf("fee address update has nonce %d. Resubmit with nonce %d", msg.Nonce, nonce)).Result(), true
+	}
+
+	signers := msg.GetSigners()
+	if len(signers) == 0 {
+		return ctx, ErrInvalidTransaction("failed recovering signer").Result(), true
+	}
+
+	// the fee address is persisted in genesis, so the rootchain is never consulted
+	validator, _ := ds.GetValidator(ctx)
+	authority := validator.FeeAddress
+	if signer := common.BytesToAddress(signers[0]); signer != authority {
+		return ctx, ErrSignatureVerificationFailure(fmt.Sprintf("fee address update not signed by the fee address. Got: 0x%x. Expected: 0x%x", signer, authority)).Result(), true
+	}
+
+	return ctx, sdk.Result{}, false
+}
+
+// validates that the validator update is signed by the operator over the nonce
+// of the next update and leaves a valid validator set
+func validatorAnteHandler(ctx sdk.Context, ds store.DataStore, msg msgs.UpdateValidatorMsg, client plasmaConn) (newCtx sdk.Context, res sdk.Result, abort bool) {
+	if contract := client.ContractAddress(); msg.PlasmaContract != contract {
+		return ctx, ErrInvalidTransaction("validator update is for the contract 0x%x. Expected: 0x%x", msg.PlasmaContract, contract).Result(), true
+	}
+	if nonce := ds.GetValidatorUpdateNonce(ctx); msg.Nonce != nonce {
+		return ctx, ErrInvalidTransaction(fmt.Sprintf("validator update has nonce %d. Resubmit with nonce %d", msg.Nonce, nonce)).Result(), true
+	}
+
+	signers := msg.GetSigners()
+	if len(signers) == 0 {
+		return ctx, ErrInvalidTransaction("failed recovering signer").Result(), true
+	}
+
+	// the operator is persisted in genesis, so the rootchain is never consulted
+	operator, _ := ds.GetOperator(ctx)
+	if signer := common.BytesToAddress(signers[0]); signer != operator {
+		return ctx, ErrSignatureVerificationFailure(fmt.Sprintf("validator update not signed by the operator. Got: 0x%x. Expected: 0x%x", signer, operator)).Result(), true
+	}
+
+	// the power of the updated set
+	consPubKey := msg.AminoConsPubKey()
+	total, members, found := msg.Power, 0, false
+	for _, validator := range ds.GetValidatorSet(ctx) {
+		if bytes.Equal(validator.ConsPubKey, consPubKey) {
+			found = true
+			continue
+		}
+		total += validator.Power
+		members++
+	}
+
+	if msg.Power == 0 && !found {
+		return ctx, ErrInvalidTransaction("removed validator is not in the validator set").Result(), true
+	}
+	if msg.Power == 0 && members == 0 {
+		return ctx, ErrInvalidTransaction("the last validator cannot be removed").Result(), true
+	}
+	if total > msgs.MaxValidatorPower {
+		return ctx, ErrInvalidTransaction(fmt.Sprintf("total voting power of the validator set must not exceed %d", uint64(msgs.MaxValidatorPower))).Result(), true
+	}
+
+	return ctx, sdk.Result{}, false
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/msgs"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
@@ -10,9 +11,11 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 	"math/big"
 	"testing"
+	"time"
 )
 
 var (
@@ -21,6 +24,8 @@ var (
 	// bad keys to check against the deposit
 	badPrivKey, _ = crypto.GenerateKey()
 	badAddr       = crypto.PubkeyToAddress(badPrivKey.PublicKey)
+	// address of the rootchain contract the cooked connections are bound to
+	contractAddr = common.HexToAddress("0x5cae340fb2c2bb0a2f194a95cda8a1ffdc9d2f85")
 )
 
 type Tx struct {
@@ -47,15 +52,20 @@ type Output struct {
 type conn struct{}
 
 // all deposits should be in an amount of 10eth owner by addr(defined above)
-func (p conn) GetDeposit(tmBlock *big.Int, nonce *big.Int) (plasma.Deposit, *big.Int, bool) {
+func (p conn) GetDeposit(tmBlock *big.Int, tmTime time.Time, nonce *big.Int) (plasma.Deposit, *big.Int, bool, error) {
 	dep := plasma.Deposit{
 		Owner:       addr,
 		Amount:      big.NewInt(10),
 		EthBlockNum: utils.Big0,
 	}
-	return dep, big.NewInt(-2), true
+	return dep, big.NewInt(-2), true, nil
 }
-func (p conn) HasTxExited(tmBlock *big.Int, pos plasma.Position) (bool, error) { return false, nil }
+func (p conn) HasTxExited(tmBlock *big.Int, tmTime time.Time, pos plasma.Position) (bool, error) {
+	return false, nil
+}
+func (p conn) RootchainAvailable() bool        { return true }
+func (p conn) DepositsHalted() bool            { return false }
+func (p conn) ContractAddress() common.Address { return contractAddr }
 
 var _ plasmaConn = conn{}
 
@@ -63,22 +73,25 @@ var _ plasmaConn = conn{}
 type exitConn struct{}
 
 // all deposits should be in an amount of 10eth owner by addr(defined above)
-func (p exitConn) GetDeposit(tmBlock *big.Int, nonce *big.Int) (plasma.Deposit, *big.Int, bool) {
+func (p exitConn) GetDeposit(tmBlock *big.Int, tmTime time.Time, nonce *big.Int) (plasma.Deposit, *big.Int, bool, error) {
 	dep := plasma.Deposit{
 		Owner:       addr,
 		Amount:      big.NewInt(10),
 		EthBlockNum: utils.Big0,
 	}
-	return dep, big.NewInt(-2), true
+	return dep, big.NewInt(-2), true, nil
 }
-func (p exitConn) HasTxExited(tmBlock *big.Int, pos plasma.Position) (bool, error) {
+func (p exitConn) HasTxExited(tmBlock *big.Int, tmTime time.Time, pos plasma.Position) (bool, error) {
 	return true, nil
 }
+func (p exitConn) RootchainAvailable() bool        { return true }
+func (p exitConn) DepositsHalted() bool            { return false }
+func (p exitConn) ContractAddress() common.Address { return contractAddr }
 
 func TestAnteChecks(t *testing.T) {
 	// setup
 	ctx, ds := setup()
-	handler := NewAnteHandler(ds, conn{})
+	handler := NewAnteHandler(ds, conn{}, nil, nil)
 
 	feePosition := getPosition("(100.65535.0.0)")
 	// cook up some input deposits
@@ -218,7 +231,7 @@ func TestAnteChecks(t *testing.T) {
 func TestAnteExitedInputs(t *testing.T) {
 	// setup
 	ctx, ds := setup()
-	handler := NewAnteHandler(ds, exitConn{})
+	handler := NewAnteHandler(ds, exitConn{}, nil, nil)
 
 	// place inputs in store
 	inputs := Tx{
@@ -254,7 +267,7 @@ func TestAnteExitedInputs(t *testing.T) {
 func TestAnteInvalidConfirmSig(t *testing.T) {
 	// setup
 	ctx, ds := setup()
-	handler := NewAnteHandler(ds, conn{})
+	handler := NewAnteHandler(ds, conn{}, nil, nil)
 
 	// place inputs in store
 	inputs := []Deposit{
@@ -316,7 +329,7 @@ func TestAnteInvalidConfirmSig(t *testing.T) {
 func TestAnteValidTx(t *testing.T) {
 	// setup
 	ctx, ds := setup()
-	handler := NewAnteHandler(ds, conn{})
+	handler := NewAnteHandler(ds, conn{}, nil, nil)
 
 	// place inputs in store
 	inputs := []Deposit{
@@ -388,7 +401,7 @@ func TestAnteValidTx(t *testing.T) {
 func TestAnteDeposit(t *testing.T) {
 	// setup
 	ctx, ds := setup()
-	handler := NewAnteHandler(ds, conn{})
+	handler := NewAnteHandler(ds, conn{}, nil, nil)
 
 	// place input in store
 	inputs := []Deposit{
@@ -418,34 +431,42 @@ func TestAnteDeposit(t *testing.T) {
 
 type unfinalConn struct{}
 
-func (u unfinalConn) GetDeposit(tmBlock *big.Int, nonce *big.Int) (plasma.Deposit, *big.Int, bool) {
+func (u unfinalConn) GetDeposit(tmBlock *big.Int, tmTime time.Time, nonce *big.Int) (plasma.Deposit, *big.Int, bool, error) {
 	dep := plasma.Deposit{
 		Owner:       addr,
 		Amount:      big.NewInt(10),
 		EthBlockNum: big.NewInt(50),
 	}
-	return dep, big.NewInt(10), false
+	return dep, big.NewInt(10), false, nil
 }
 
-func (u unfinalConn) HasTxExited(tmBlock *big.Int, pos plasma.Position) (bool, error) {
+func (u unfinalConn) HasTxExited(tmBlock *big.Int, tmTime time.Time, pos plasma.Position) (bool, error) {
 	return false, nil
 }
 
+func (u unfinalConn) RootchainAvailable() bool        { return true }
+func (u unfinalConn) DepositsHalted() bool            { return false }
+func (u unfinalConn) ContractAddress() common.Address { return contractAddr }
+
 type dneConn struct{}
 
-func (d dneConn) GetDeposit(tmBlock *big.Int, nonce *big.Int) (plasma.Deposit, *big.Int, bool) {
-	return plasma.Deposit{}, nil, false
+func (d dneConn) GetDeposit(tmBlock *big.Int, tmTime time.Time, nonce *big.Int) (plasma.Deposit, *big.Int, bool, error) {
+	return plasma.Deposit{}, nil, false, nil
 }
 
-func (d dneConn) HasTxExited(tmBlock *big.Int, pos plasma.Position) (bool, error) {
+func (d dneConn) HasTxExited(tmBlock *big.Int, tmTime time.Time, pos plasma.Position) (bool, error) {
 	return false, nil
 }
 
+func (d dneConn) RootchainAvailable() bool        { return true }
+func (d dneConn) DepositsHalted() bool            { return false }
+func (d dneConn) ContractAddress() common.Address { return contractAddr }
+
 func TestAnteDepositUnfinal(t *testing.T) {
 	// setup
 	ctx, ds := setup()
 	// connection always returns unfinalized deposits
-	handler := NewAnteHandler(ds, unfinalConn{})
+	handler := NewAnteHandler(ds, unfinalConn{}, nil, nil)
 
 	msg := msgs.IncludeDepositMsg{
 		DepositNonce: big.NewInt(3),
@@ -463,7 +484,7 @@ func TestAnteDepositExitted(t *testing.T) {
 	// setup
 	ctx, ds := setup()
 	// connection always returns exitted deposits
-	handler := NewAnteHandler(ds, exitConn{})
+	handler := NewAnteHandler(ds, exitConn{}, nil, nil)
 
 	msg := msgs.IncludeDepositMsg{
 		DepositNonce: big.NewInt(3),
@@ -481,7 +502,7 @@ func TestAnteDepositDNE(t *testing.T) {
 	// setup
 	ctx, ds := setup()
 	// connection always returns exitted deposits
-	handler := NewAnteHandler(ds, dneConn{})
+	handler := NewAnteHandler(ds, dneConn{}, nil, nil)
 
 	msg := msgs.IncludeDepositMsg{
 		DepositNonce: big.NewInt(3),
@@ -505,7 +526,7 @@ func TestAnteDepositWrongOwner(t *testing.T) {
 	// setup
 	ctx, ds := setup()
 	// connection always returns valid deposits
-	handler := NewAnteHandler(ds, conn{})
+	handler := NewAnteHandler(ds, conn{}, nil, nil)
 
 	// Try to include with wrong owner
 	msg := msgs.IncludeDepositMsg{
@@ -519,6 +540,132 @@ func TestAnteDepositWrongOwner(t *testing.T) {
 	require.True(t, abort, "Wrong owner deposit inclusion did not abort")
 }
 
+// cook up a plasma connection whose rootchain cannot be reached
+type unavailableConn struct{ conn }
+
+func (u unavailableConn) RootchainAvailable() bool { return false }
+
+func TestAnteRootchainUnavailable(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	handler := NewAnteHandler(ds, unavailableConn{}, nil, nil)
+
+	setupDeposits(ctx, ds, Deposit{addr, utils.Big1, big.NewInt(50), big.NewInt(10)})
+
+	spendMsg := msgs.SpendMsg{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(plasma.NewPosition(nil, 0, 0, utils.Big1), [65]byte{}, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(10))},
+			Fee:     utils.Big0,
+		},
+	}
+	txHash := utils.ToEthSignedMessageHash(spendMsg.TxHash())
+	sig, _ := crypto.Sign(txHash, privKey)
+	copy(spendMsg.Inputs[0].Signature[:], sig[:])
+
+	depositMsg := msgs.IncludeDepositMsg{
+		DepositNonce: big.NewInt(3),
+		Owner:        addr,
+	}
+
+	checkCtx := ctx.WithIsCheckTx(true)
+	for _, tx := range []sdk.Tx{spendMsg, depositMsg} {
+		_, res, abort := handler(checkCtx, tx, false)
+		require.True(t, abort, "did not abort %s while the rootchain is unavailable", tx.GetMsgs()[0].Type())
+		require.Equal(t, CodeRootchainUnavailable, res.Code, "wrong error code for %s", tx.GetMsgs()[0].Type())
+	}
+
+	// the availability of the rootchain does not gate the execution of a block
+	_, res, abort := handler(ctx, spendMsg, false)
+	require.True(t, res.IsOK(), res.Log)
+	require.False(t, abort)
+
+	// the same transactions are admitted once the rootchain is reachable
+	handler = NewAnteHandler(ds, conn{}, nil, nil)
+	_, res, abort = handler(checkCtx, spendMsg, false)
+	require.True(t, res.IsOK(), res.Log)
+	require.False(t, abort)
+}
+
+// cook up a plasma connection that cannot retrieve the rootchain state
+type failedConn struct{ unavailableConn }
+
+func (f failedConn) GetDeposit(tmBlock *big.Int, tmTime time.Time, nonce *big.Int) (plasma.Deposit, *big.Int, bool, error) {
+	return plasma.Deposit{}, nil, false, errors.New("rootchain unreachable")
+}
+func (f failedConn) HasTxExited(tmBlock *big.Int, tmTime time.Time, pos plasma.Position) (bool, error) {
+	return true, errors.New("rootchain unreachable")
+}
+
+func TestDeliverRootchainUnavailable(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	handler := NewAnteHandler(ds, failedConn{}, nil, nil)
+
+	setupDeposits(ctx, ds, Deposit{addr, utils.Big1, big.NewInt(50), big.NewInt(10)})
+
+	spendMsg := msgs.SpendMsg{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(plasma.NewPosition(nil, 0, 0, utils.Big1), [65]byte{}, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(10))},
+			Fee:     utils.Big0,
+		},
+	}
+	txHash := utils.ToEthSignedMessageHash(spendMsg.TxHash())
+	sig, _ := crypto.Sign(txHash, privKey)
+	copy(spendMsg.Inputs[0].Signature[:], sig[:])
+
+	depositMsg := msgs.IncludeDepositMsg{
+		DepositNonce: big.NewInt(3),
+		Owner:        addr,
+	}
+
+	// the result of delivering the transactions is unknown, which must halt the node
+	for _, tx := range []sdk.Tx{spendMsg, depositMsg} {
+		_, res, abort := handler(ctx, tx, false)
+		require.True(t, abort, "delivered %s without the rootchain state", tx.GetMsgs()[0].Type())
+		require.True(t, IsRootchainUnavailable(string(res.Codespace), uint32(res.Code)), "%s did not fail with ErrRootchainUnavailable: %s", tx.GetMsgs()[0].Type(), res.Log)
+	}
+
+	res := NewDepositHandler(ds, nextTxIndex, failedConn{})(ctx, depositMsg)
+	require.True(t, IsRootchainUnavailable(string(res.Codespace), uint32(res.Code)), res.Log)
+	_, ok := ds.GetDeposit(ctx, depositMsg.DepositNonce)
+	require.False(t, ok, "deposit stored without the rootchain state")
+
+	// other failures are not mistaken for an unavailable rootchain
+	_, res, _ = NewAnteHandler(ds, dneConn{}, nil, nil)(ctx, depositMsg, false)
+	require.False(t, IsRootchainUnavailable(string(res.Codespace), uint32(res.Code)), res.Log)
+}
+
+// cook up a plasma connection that detected a reorg deeper than the finality bound
+type haltedConn struct{ conn }
+
+func (h haltedConn) DepositsHalted() bool { return true }
+
+func TestAnteDepositsHalted(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	handler := NewAnteHandler(ds, haltedConn{}, nil, nil)
+
+	depositMsg := msgs.IncludeDepositMsg{
+		DepositNonce: big.NewInt(3),
+		Owner:        addr,
+	}
+
+	_, res, abort := handler(ctx.WithIsCheckTx(true), depositMsg, false)
+	require.True(t, abort, "deposit admitted into the mempool while deposits are halted")
+	require.Equal(t, CodeRootchainUnavailable, res.Code, res.Log)
+
+	// the halt is local to the node, so blocks agreed upon by the validators are delivered
+	_, res, abort = handler(ctx, depositMsg, false)
+	require.False(t, abort, res.Log)
+
+	// deposits are admitted once the node is restarted
+	handler = NewAnteHandler(ds, conn{}, nil, nil)
+	_, res, abort = handler(ctx.WithIsCheckTx(true), depositMsg, false)
+	require.False(t, abort, res.Log)
+}
+
 func setupDeposits(ctx sdk.Context, ds store.DataStore, inputs ...Deposit) {
 	for _, i := range inputs {
 		deposit := plasma.Deposit{
@@ -542,3 +689,60 @@ func setupTxs(ctx sdk.Context, ds store.DataStore, inputs ...Tx) {
 		ds.StoreTx(ctx, tx)
 	}
 }
+
+func TestAnteFeePolicy(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	policy := FeePolicy{MinFee: big.NewInt(2), FeePerByte: big.NewInt(1)}
+	fees := NewFeeSchedule(policy)
+	handler := NewAnteHandler(ds, conn{}, fees, nil)
+
+	setupDeposits(ctx, ds, Deposit{addr, utils.Big1, big.NewInt(50), big.NewInt(1000)})
+
+	spend := func(fee int64) (msgs.SpendMsg, []byte) {
+		spendMsg := msgs.SpendMsg{
+			Transaction: plasma.Transaction{
+				Inputs:  []plasma.Input{plasma.NewInput(plasma.NewPosition(nil, 0, 0, utils.Big1), [65]byte{}, nil)},
+				Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(1000-fee))},
+				Fee:     big.NewInt(fee),
+			},
+		}
+		sig, _ := crypto.Sign(utils.ToEthSignedMessageHash(spendMsg.TxHash()), privKey)
+		copy(spendMsg.Inputs[0].Signature[:], sig[:])
+		txBytes, err := rlp.EncodeToBytes(&spendMsg)
+		require.NoError(t, err)
+		return spendMsg, txBytes
+	}
+
+	// a zero fee is rejected from the mempool
+	spendMsg, txBytes := spend(0)
+	_, res, abort := handler(ctx.WithIsCheckTx(true).WithTxBytes(txBytes), spendMsg, false)
+	require.True(t, abort, "accepted a spend without a fee")
+	require.Equal(t, CodeFeeBelowMinimum, res.Code)
+
+	// the fee policy is local to the node and not enforced on delivery
+	_, res, abort = handler(ctx.WithTxBytes(txBytes), spendMsg, false)
+	require.True(t, res.IsOK(), res.Log)
+	require.False(t, abort)
+
+	// the required fee scales with the size of the transaction
+	required := policy.RequiredFee(len(txBytes)).Int64()
+	require.Equal(t, int64(len(txBytes))+2, required)
+
+	spendMsg, txBytes = spend(required - 1)
+	_, res, abort = handler(ctx.WithIsCheckTx(true).WithTxBytes(txBytes), spendMsg, false)
+	require.True(t, abort, "accepted a spend below the required fee")
+	require.Equal(t, CodeFeeBelowMinimum, res.Code)
+
+	spendMsg, txBytes = spend(required)
+	_, res, abort = handler(ctx.WithIsCheckTx(true).WithTxBytes(txBytes), spendMsg, false)
+	require.True(t, res.IsOK(), res.Log)
+	require.False(t, abort)
+
+	// an updated policy applies to the spends admitted afterwards
+	fees.SetPolicy(FeePolicy{MinFee: big.NewInt(1)})
+	spendMsg, txBytes = spend(1)
+	_, res, abort = handler(ctx.WithIsCheckTx(true).WithTxBytes(txBytes), spendMsg, false)
+	require.True(t, res.IsOK(), res.Log)
+	require.False(t, abort)
+}
@@ -14,10 +14,18 @@ func NewDepositHandler(ds store.DataStore, nextTxIndex NextTxIndex, client plasm
 			panic("Msg does not implement IncludeDepositMsg")
 		}
 
-		// Increment txIndex so that it doesn't collide with SpendMsg
-		nextTxIndex()
+		deposit, _, ok, err := client.GetDeposit(ds.PlasmaBlockHeight(ctx), ctx.BlockHeader().Time, depositMsg.DepositNonce)
+		if err != nil {
+			return ErrRootchainUnavailable("failed to retrieve deposit, %s: %s", depositMsg.DepositNonce, err).Result()
+		}
+		if !ok {
+			return ErrInvalidTransaction("deposit, %s, does not exist or has not finalized", depositMsg.DepositNonce).Result()
+		}
 
-		deposit, _, _ := client.GetDeposit(ds.PlasmaBlockHeight(ctx), depositMsg.DepositNonce)
+		// Increment txIndex so that it doesn't collide with SpendMsg
+		if _, ok := nextTxIndex(); !ok {
+			return ErrBlockFull("plasma block is full. Resubmit the deposit in the next block").Result()
+		}
 
 		ds.StoreDeposit(ctx, depositMsg.DepositNonce, deposit)
 
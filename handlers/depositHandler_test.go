@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
 	"github.com/stretchr/testify/require"
 	"math/big"
 	"testing"
@@ -28,3 +30,42 @@ func TestIncludeDeposit(t *testing.T) {
 	require.Equal(t, big.NewInt(10), deposit.Deposit.Amount, "deposit has wrong amount")
 	require.False(t, deposit.Spent, "Deposit is incorrectly marked as spent")
 }
+
+func TestIncludeDepositBlockFull(t *testing.T) {
+	ctx, ds := setup()
+
+	// fill a block to the default limit
+	next, txIndex := blockTxIndex(store.DefaultMaxTxsPerBlock)
+	depositHandler := NewDepositHandler(ds, next, conn{})
+	for i := int64(1); i <= int64(store.DefaultMaxTxsPerBlock); i++ {
+		res := depositHandler(ctx, msgs.IncludeDepositMsg{DepositNonce: big.NewInt(i), Owner: addr})
+		require.Truef(t, res.IsOK(), "failed to include deposit %d: %s", i, res)
+	}
+	require.Equal(t, store.DefaultMaxTxsPerBlock, *txIndex)
+
+	// the last index handed out precedes the fee position
+	require.False(t, plasma.NewPosition(big.NewInt(1), *txIndex-1, 0, nil).IsFee(), "tx index collides with the fee position")
+
+	nonce := big.NewInt(int64(store.DefaultMaxTxsPerBlock) + 1)
+	res := depositHandler(ctx, msgs.IncludeDepositMsg{DepositNonce: nonce, Owner: addr})
+	require.Equal(t, CodeBlockFull, res.Code, "deposit beyond the block limit not rejected")
+
+	_, ok := ds.GetDeposit(ctx, nonce)
+	require.False(t, ok, "deposit included in a full block")
+	require.Equal(t, store.DefaultMaxTxsPerBlock, *txIndex, "rejected deposit consumed a tx index")
+}
+
+func TestIncludeDepositNotFound(t *testing.T) {
+	ctx, ds := setup()
+
+	// the deposit cannot be retrieved from the rootchain
+	next, txIndex := blockTxIndex(store.DefaultMaxTxsPerBlock)
+	depositHandler := NewDepositHandler(ds, next, dneConn{})
+
+	res := depositHandler(ctx, msgs.IncludeDepositMsg{DepositNonce: big.NewInt(1), Owner: addr})
+	require.Equal(t, CodeInvalidTransaction, res.Code, "deposit that could not be retrieved not rejected")
+
+	_, ok := ds.GetDeposit(ctx, big.NewInt(1))
+	require.False(t, ok, "deposit that could not be retrieved was stored")
+	require.Equal(t, uint16(0), *txIndex, "rejected deposit consumed a tx index")
+}
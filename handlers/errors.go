@@ -14,35 +14,67 @@ const (
 	CodeInvalidTransaction           sdk.CodeType = 4
 	CodeInvalidSignature             sdk.CodeType = 5
 	CodeInvalidInput                 sdk.CodeType = 6
+	CodeRootchainUnavailable         sdk.CodeType = 7
+	CodeFeeBelowMinimum              sdk.CodeType = 8
+	CodeRateLimited                  sdk.CodeType = 9
+	CodeBlockFull                    sdk.CodeType = 10
 )
 
 // ErrInsufficientFee error for an insufficient fee
 func ErrInsufficientFee(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeInsufficientFee, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeInsufficientFee, msg, args...)
 }
 
 // ErrExitedInput error for if the input has already exited
 func ErrExitedInput(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeExitedInput, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeExitedInput, msg, args...)
 }
 
 // ErrSignatureVerficiationFailure error for signature verifcation failing to
 // complete
 func ErrSignatureVerificationFailure(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeSignatureVerificationFailure, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeSignatureVerificationFailure, msg, args...)
 }
 
 // ErrInvalidTransaction error for an invalid transaction
 func ErrInvalidTransaction(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeInvalidTransaction, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeInvalidTransaction, msg, args...)
 }
 
 // ErrInvalidSignature error for an incorrect signature
 func ErrInvalidSignature(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeInvalidSignature, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeInvalidSignature, msg, args...)
 }
 
 // ErrInvalidInput
 func ErrInvalidInput(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeInvalidInput, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeInvalidInput, msg, args...)
+}
+
+// ErrRootchainUnavailable error for a transaction that cannot be validated
+// because the rootchain cannot be reached
+func ErrRootchainUnavailable(msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeRootchainUnavailable, msg, args...)
+}
+
+// IsRootchainUnavailable reports if a result with `codespace` and `code` is an
+// ErrRootchainUnavailable. A delivered transaction only fails with it if its
+// result could not be determined by this node
+func IsRootchainUnavailable(codespace string, code uint32) bool {
+	return codespace == string(DefaultCodespace) && code == uint32(CodeRootchainUnavailable)
+}
+
+// ErrFeeBelowMinimum error for a fee below the minimum of the fee policy
+func ErrFeeBelowMinimum(msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeFeeBelowMinimum, msg, args...)
+}
+
+// ErrRateLimited error for a signer with too many spends pending in the mempool
+func ErrRateLimited(msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeRateLimited, msg, args...)
+}
+
+// ErrBlockFull error for a transaction that does not fit in the current block
+func ErrBlockFull(msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeBlockFull, msg, args...)
 }
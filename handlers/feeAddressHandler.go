@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewFeeAddressHandler updates the address fees are collected to. The update
+// applies to the fees of the block it is included in
+func NewFeeAddressHandler(ds store.DataStore, nextTxIndex NextTxIndex) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		feeAddressMsg, ok := msg.(msgs.UpdateFeeAddressMsg)
+		if !ok {
+			panic("Msg does not implement UpdateFeeAddressMsg")
+		}
+
+		// the msg is a leaf of the block's merkle tree, so it takes up a tx index
+		if _, ok := nextTxIndex(); !ok {
+			return ErrBlockFull("plasma block is full. Resubmit the update in the next block").Result()
+		}
+
+		ds.UpdateFeeAddress(ctx, feeAddressMsg.FeeAddress)
+
+		return sdk.Result{}
+	}
+}
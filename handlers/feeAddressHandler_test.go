@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUpdateFeeAddress(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	anteHandler := NewAnteHandler(ds, conn{}, nil, nil)
+	feeAddressHandler := NewFeeAddressHandler(ds, nextTxIndex)
+
+	update := func(feeAddress common.Address, nonce uint64, signer int) msgs.UpdateFeeAddressMsg {
+		msg := msgs.UpdateFeeAddressMsg{FeeAddress: feeAddress, Nonce: nonce, PlasmaContract: contractAddr}
+		key := privKey
+		if signer != 0 {
+			key = badPrivKey
+		}
+		sig, err := crypto.Sign(utils.ToEthSignedMessageHash(msg.GetSignBytes()), key)
+		require.NoError(t, err)
+		copy(msg.Signature[:], sig)
+		return msg
+	}
+
+	// genesis persists the fee address, the operator if none was set
+	ds.StoreValidator(ctx, store.Validator{FeeAddress: addr})
+
+	_, res, abort := anteHandler(ctx, update(badAddr, 0, 1), false)
+	require.True(t, abort, "update not signed by the fee address accepted")
+	require.Equal(t, CodeSignatureVerificationFailure, res.Code, res.Log)
+
+	// updates signed for another sidechain are rejected
+	msg := update(badAddr, 0, 0)
+	msg.PlasmaContract = addr
+	sig, err := crypto.Sign(utils.ToEthSignedMessageHash(msg.GetSignBytes()), privKey)
+	require.NoError(t, err)
+	copy(msg.Signature[:], sig)
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.True(t, abort, "update for another contract accepted")
+	require.Equal(t, CodeInvalidTransaction, res.Code, res.Log)
+
+	msg = update(badAddr, 0, 0)
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.False(t, abort, res.Log)
+	require.True(t, feeAddressHandler(ctx, msg).IsOK())
+
+	validator, _ := ds.GetValidator(ctx)
+	require.Equal(t, badAddr, validator.FeeAddress)
+	require.Equal(t, uint64(1), ds.GetFeeAddressNonce(ctx))
+
+	// the update cannot be replayed
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.True(t, abort, "replayed update accepted")
+	require.Equal(t, CodeInvalidTransaction, res.Code, res.Log)
+
+	// the new fee address authorizes the next update, not the previous one
+	_, res, abort = anteHandler(ctx, update(addr, 1, 0), false)
+	require.True(t, abort, "update signed by the previous fee address accepted")
+	require.Equal(t, CodeSignatureVerificationFailure, res.Code, res.Log)
+
+	msg = update(addr, 1, 1)
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.False(t, abort, res.Log)
+	require.True(t, feeAddressHandler(ctx, msg).IsOK())
+
+	validator, _ = ds.GetValidator(ctx)
+	require.Equal(t, addr, validator.FeeAddress)
+	require.Equal(t, uint64(2), ds.GetFeeAddressNonce(ctx))
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"math/big"
+	"sync"
+)
+
+const (
+	// FeeQuerierRouteName to mount the fee querier
+	FeeQuerierRouteName = "fees"
+
+	// QueryFeePolicy retrieves the fee policy of the node
+	QueryFeePolicy = "policy"
+)
+
+// FeePolicy is the minimum fee a node requires of spends before they enter its
+// mempool. Each node sets its own policy, so it is only enforced in CheckTx
+// and never when a block is delivered
+type FeePolicy struct {
+	MinFee     *big.Int // minimum fee of every spend
+	FeePerByte *big.Int // charged per byte of the encoded transaction on top of the minimum
+}
+
+// RequiredFee returns the minimum fee of a spend encoded in `txSize` bytes
+func (p FeePolicy) RequiredFee(txSize int) *big.Int {
+	fee := new(big.Int)
+	if p.FeePerByte != nil {
+		fee.Mul(p.FeePerByte, big.NewInt(int64(txSize)))
+	}
+	if p.MinFee != nil {
+		fee.Add(fee, p.MinFee)
+	}
+	return fee
+}
+
+// IsZero returns true if the policy accepts spends without a fee
+func (p FeePolicy) IsZero() bool {
+	return (p.MinFee == nil || p.MinFee.Sign() == 0) && (p.FeePerByte == nil || p.FeePerByte.Sign() == 0)
+}
+
+// FeeSchedule holds the fee policy of the node. The policy may be replaced while
+// the node is running, so the operator can adjust fees without a restart
+type FeeSchedule struct {
+	mtx    sync.RWMutex
+	policy FeePolicy
+}
+
+// NewFeeSchedule returns a schedule starting with `policy`
+func NewFeeSchedule(policy FeePolicy) *FeeSchedule {
+	return &FeeSchedule{policy: policy}
+}
+
+// Policy returns the current fee policy
+func (s *FeeSchedule) Policy() FeePolicy {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.policy
+}
+
+// SetPolicy replaces the fee policy. Spends already in the mempool are held to the
+// new policy when tendermint rechecks them after the next block
+func (s *FeeSchedule) SetPolicy(policy FeePolicy) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.policy = policy
+}
+
+// validates the fee of a spend encoded in `txBytes` against the policy
+func checkFee(policy FeePolicy, fee *big.Int, txBytes []byte) sdk.Error {
+	if policy.IsZero() {
+		return nil
+	}
+
+	required := policy.RequiredFee(len(txBytes))
+	if fee.Cmp(required) < 0 {
+		return ErrFeeBelowMinimum("fee of %s is below the minimum fee of %s required for a transaction of %d bytes", fee, required, len(txBytes))
+	}
+	return nil
+}
+
+// NewFeeQuerier returns an SDK querier publishing the current fee policy of the node
+func NewFeeQuerier(fees *FeeSchedule) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) == 0 {
+			return nil, sdk.ErrUnknownRequest("path not specified")
+		}
+
+		switch path[0] {
+		case QueryFeePolicy:
+			policy := fees.Policy()
+			if policy.MinFee == nil {
+				policy.MinFee = big.NewInt(0)
+			}
+			if policy.FeePerByte == nil {
+				policy.FeePerByte = big.NewInt(0)
+			}
+			data, err := json.Marshal(policy)
+			if err != nil {
+				return nil, sdk.ErrInternal(fmt.Sprintf("json: %s", err))
+			}
+			return data, nil
+		default:
+			return nil, sdk.ErrUnknownRequest("unregistered query path")
+		}
+	}
+}
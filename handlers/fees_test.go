@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"math/big"
+	"testing"
+)
+
+func TestFeePolicy(t *testing.T) {
+	require.True(t, FeePolicy{}.IsZero())
+	require.Equal(t, big.NewInt(0), FeePolicy{}.RequiredFee(100))
+	require.Nil(t, checkFee(FeePolicy{}, big.NewInt(0), make([]byte, 100)), "zero policy rejected a spend without a fee")
+
+	policy := FeePolicy{MinFee: big.NewInt(100), FeePerByte: big.NewInt(2)}
+	require.False(t, policy.IsZero())
+	require.Equal(t, big.NewInt(100), policy.RequiredFee(0))
+	require.Equal(t, big.NewInt(300), policy.RequiredFee(100))
+	require.Equal(t, big.NewInt(4), FeePolicy{FeePerByte: big.NewInt(2)}.RequiredFee(2))
+
+	err := checkFee(policy, big.NewInt(299), make([]byte, 100))
+	require.NotNil(t, err)
+	require.Equal(t, CodeFeeBelowMinimum, err.Code())
+	require.Contains(t, err.Error(), "fee of 299 is below the minimum fee of 300 required for a transaction of 100 bytes")
+	require.Nil(t, checkFee(policy, big.NewInt(300), make([]byte, 100)))
+}
+
+func TestFeeQuerier(t *testing.T) {
+	ctx, _ := setup()
+
+	fees := NewFeeSchedule(FeePolicy{MinFee: big.NewInt(100)})
+	querier := NewFeeQuerier(fees)
+	res, err := querier(ctx, []string{QueryFeePolicy}, abci.RequestQuery{})
+	require.Nil(t, err)
+
+	var policy FeePolicy
+	require.NoError(t, json.Unmarshal(res, &policy))
+	require.Equal(t, big.NewInt(100), policy.MinFee)
+	require.Equal(t, big.NewInt(0), policy.FeePerByte, "unset per byte fee not published as zero")
+
+	// an updated policy is published
+	fees.SetPolicy(FeePolicy{MinFee: big.NewInt(50), FeePerByte: big.NewInt(1)})
+	res, err = querier(ctx, []string{QueryFeePolicy}, abci.RequestQuery{})
+	require.Nil(t, err)
+	require.NoError(t, json.Unmarshal(res, &policy))
+	require.Equal(t, big.NewInt(50), policy.MinFee)
+	require.Equal(t, big.NewInt(1), policy.FeePerByte)
+
+	_, err = querier(ctx, []string{"unknown"}, abci.RequestQuery{})
+	require.NotNil(t, err)
+	_, err = querier(ctx, []string{}, abci.RequestQuery{})
+	require.NotNil(t, err)
+}
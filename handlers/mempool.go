@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"sync"
+)
+
+// MempoolLimits bound the spends a node accepts into its mempool between two
+// blocks. Under load, spends below a fee floor are rejected. Admitted spends
+// are not reordered by fee and stay in tendermint's first in first out order.
+// Prioritizing by fee is not supported, since the mempool of tendermint v0.28
+// cannot be reordered or have a spend evicted in favor of a later one
+type MempoolLimits struct {
+	MaxPending         int      // spends and deposits admitted until the next block. 0 disables the limit
+	MaxSpendsPerSigner int      // pending spends a single signer may have. 0 disables the limit
+	FeeFloorThreshold  int      // pending spends after which only spends paying FeeFloor are admitted. 0 disables the fee floor
+	FeeFloor           *big.Int // minimum fee of spends admitted above FeeFloorThreshold
+}
+
+// MempoolLimiter enforces MempoolLimits in CheckTx. Counts are reset when a
+// block is committed, after which tendermint rechecks the spends left in the
+// mempool and they are counted again
+type MempoolLimiter struct {
+	limits MempoolLimits
+
+	mtx     sync.Mutex
+	pending int
+	signers map[common.Address]int
+}
+
+// NewMempoolLimiter returns a limiter enforcing the limits
+func NewMempoolLimiter(limits MempoolLimits) *MempoolLimiter {
+	return &MempoolLimiter{
+		limits:  limits,
+		signers: make(map[common.Address]int),
+	}
+}
+
+// Pending returns the number of spends admitted since the last block
+func (l *MempoolLimiter) Pending() int {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.pending
+}
+
+// SetMaxPending sets the number of transactions admitted until the next
+// block, typically the capacity of a plasma block
+func (l *MempoolLimiter) SetMaxPending(max int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.limits.MaxPending = max
+}
+
+// Reset clears the counts. Called once a block is committed
+func (l *MempoolLimiter) Reset() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.pending = 0
+	l.signers = make(map[common.Address]int)
+}
+
+// checkCapacity returns an error if a block worth of transactions has been admitted
+func (l *MempoolLimiter) checkCapacity() sdk.Error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.full()
+}
+
+// check returns an error if a spend by `signers` paying `fee` cannot be admitted
+func (l *MempoolLimiter) check(signers []common.Address, fee *big.Int) sdk.Error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if err := l.full(); err != nil {
+		return err
+	}
+
+	if l.limits.FeeFloorThreshold > 0 && l.pending >= l.limits.FeeFloorThreshold && l.limits.FeeFloor != nil && fee.Cmp(l.limits.FeeFloor) < 0 {
+		return ErrFeeBelowMinimum("%d spends are pending. Spends must pay a fee of at least %s until the next block", l.pending, l.limits.FeeFloor)
+	}
+
+	if l.limits.MaxSpendsPerSigner > 0 {
+		for _, signer := range signers {
+			if l.signers[signer] >= l.limits.MaxSpendsPerSigner {
+				return ErrRateLimited("0x%x has %d pending spends. Resubmit after the next block", signer, l.signers[signer])
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *MempoolLimiter) full() sdk.Error {
+	if l.limits.MaxPending > 0 && l.pending >= l.limits.MaxPending {
+		return ErrBlockFull("%d transactions are pending, filling the next plasma block. Resubmit after the next block", l.pending)
+	}
+	return nil
+}
+
+// add counts an admitted transaction by `signers`. A signer of multiple inputs
+// is counted once and deposits have no signers
+func (l *MempoolLimiter) add(signers []common.Address) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.pending++
+	counted := make(map[common.Address]bool)
+	for _, signer := range signers {
+		if !counted[signer] {
+			l.signers[signer]++
+			counted[signer] = true
+		}
+	}
+}
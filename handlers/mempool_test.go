@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestMempoolLimiter(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	limiter := NewMempoolLimiter(MempoolLimits{
+		MaxSpendsPerSigner: 2,
+		FeeFloorThreshold:  3,
+		FeeFloor:           big.NewInt(5),
+	})
+	handler := NewAnteHandler(ds, conn{}, nil, limiter)
+	checkCtx := ctx.WithIsCheckTx(true)
+
+	otherPrivKey, _ := crypto.GenerateKey()
+	otherAddr := crypto.PubkeyToAddress(otherPrivKey.PublicKey)
+	setupDeposits(ctx, ds,
+		Deposit{addr, big.NewInt(1), big.NewInt(50), big.NewInt(100)},
+		Deposit{otherAddr, big.NewInt(2), big.NewInt(50), big.NewInt(100)},
+	)
+
+	// spends of the deposit owned by the signer. The ante handler does not
+	// spend the deposit so it can be spent repeatedly
+	spend := func(nonce int64, fee int64) msgs.SpendMsg {
+		key := privKey
+		if nonce == 2 {
+			key = otherPrivKey
+		}
+		spendMsg := msgs.SpendMsg{
+			Transaction: plasma.Transaction{
+				Inputs:  []plasma.Input{plasma.NewInput(plasma.NewPosition(nil, 0, 0, big.NewInt(nonce)), [65]byte{}, nil)},
+				Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(100-fee))},
+				Fee:     big.NewInt(fee),
+			},
+		}
+		sig, _ := crypto.Sign(utils.ToEthSignedMessageHash(spendMsg.TxHash()), key)
+		copy(spendMsg.Inputs[0].Signature[:], sig[:])
+		return spendMsg
+	}
+
+	// invalid spends are not counted
+	invalid := spend(1, 0)
+	invalid.Inputs[0].Signature = [65]byte{}
+	_, _, abort := handler(checkCtx, invalid, false)
+	require.True(t, abort)
+	require.Equal(t, 0, limiter.Pending())
+
+	// a signer is limited to 2 pending spends
+	for i := 0; i < 2; i++ {
+		_, res, abort := handler(checkCtx, spend(1, 0), false)
+		require.True(t, res.IsOK(), res.Log)
+		require.False(t, abort)
+	}
+	_, res, abort := handler(checkCtx, spend(1, 0), false)
+	require.True(t, abort, "admitted a spend above the signer limit")
+	require.Equal(t, CodeRateLimited, res.Code)
+
+	// other signers are not affected
+	_, res, abort = handler(checkCtx, spend(2, 0), false)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, 3, limiter.Pending())
+
+	// above the threshold, only spends paying the fee floor are admitted
+	_, res, abort = handler(checkCtx, spend(2, 4), false)
+	require.True(t, abort, "admitted a spend below the fee floor")
+	require.Equal(t, CodeFeeBelowMinimum, res.Code)
+	_, res, abort = handler(checkCtx, spend(2, 5), false)
+	require.True(t, res.IsOK(), res.Log)
+
+	// limits only apply to the mempool
+	_, res, abort = handler(ctx, spend(1, 0), false)
+	require.True(t, res.IsOK(), res.Log)
+	require.False(t, abort)
+
+	// counts are cleared once a block is committed
+	limiter.Reset()
+	require.Equal(t, 0, limiter.Pending())
+	_, res, abort = handler(checkCtx, spend(1, 0), false)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+// a signer of multiple inputs counts once
+func TestMempoolLimiterSigners(t *testing.T) {
+	limiter := NewMempoolLimiter(MempoolLimits{MaxSpendsPerSigner: 1})
+	signer := common.BytesToAddress([]byte("signer"))
+
+	require.Nil(t, limiter.check([]common.Address{signer, signer}, utils.Big0))
+	limiter.add([]common.Address{signer, signer})
+	require.Equal(t, 1, limiter.Pending())
+
+	err := limiter.check([]common.Address{signer}, utils.Big0)
+	require.NotNil(t, err)
+	require.Equal(t, CodeRateLimited, err.Code())
+}
+
+func TestMempoolLimiterCapacity(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	limiter := NewMempoolLimiter(MempoolLimits{})
+	limiter.SetMaxPending(int(store.DefaultMaxTxsPerBlock))
+	handler := NewAnteHandler(ds, conn{}, nil, limiter)
+	checkCtx := ctx.WithIsCheckTx(true)
+
+	setupDeposits(ctx, ds, Deposit{addr, big.NewInt(1), big.NewInt(50), big.NewInt(100)})
+	spendMsg := msgs.SpendMsg{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(plasma.NewPosition(nil, 0, 0, big.NewInt(1)), [65]byte{}, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(100))},
+			Fee:     utils.Big0,
+		},
+	}
+	sig, _ := crypto.Sign(utils.ToEthSignedMessageHash(spendMsg.TxHash()), privKey)
+	copy(spendMsg.Inputs[0].Signature[:], sig[:])
+
+	// deposits and spends both take up a position in the block
+	_, res, _ := handler(checkCtx, msgs.IncludeDepositMsg{DepositNonce: big.NewInt(2), Owner: addr}, false)
+	require.True(t, res.IsOK(), res.Log)
+	_, res, _ = handler(checkCtx, spendMsg, false)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, 2, limiter.Pending())
+
+	// fill the block
+	for limiter.Pending() < int(store.DefaultMaxTxsPerBlock) {
+		require.Nil(t, limiter.check(nil, utils.Big0))
+		limiter.add(nil)
+	}
+
+	_, res, abort := handler(checkCtx, spendMsg, false)
+	require.True(t, abort, "admitted a spend beyond the block capacity")
+	require.Equal(t, CodeBlockFull, res.Code)
+	_, res, abort = handler(checkCtx, msgs.IncludeDepositMsg{DepositNonce: big.NewInt(3), Owner: addr}, false)
+	require.True(t, abort, "admitted a deposit beyond the block capacity")
+	require.Equal(t, CodeBlockFull, res.Code)
+	require.Equal(t, int(store.DefaultMaxTxsPerBlock), limiter.Pending())
+
+	// deferred transactions are admitted once the block is committed
+	limiter.Reset()
+	_, res, _ = handler(checkCtx, spendMsg, false)
+	require.True(t, res.IsOK(), res.Log)
+}
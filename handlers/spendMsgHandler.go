@@ -9,8 +9,10 @@ import (
 	"math/big"
 )
 
-// returns the next tx index in the current block
-type NextTxIndex func() uint16
+// returns the next tx index in the current block. False is returned, without
+// consuming an index, if the block already contains the maximum number of
+// transactions
+type NextTxIndex func() (uint16, bool)
 
 // FeeUpdater updates the aggregate fee amount in a block
 type FeeUpdater func(amt *big.Int) sdk.Error
@@ -24,7 +26,10 @@ func NewSpendHandler(ds store.DataStore, nextTxIndex NextTxIndex, feeUpdater Fee
 			panic("Msg does not implement SpendMsg")
 		}
 
-		txIndex := nextTxIndex()
+		txIndex, ok := nextTxIndex()
+		if !ok {
+			return ErrBlockFull("plasma block is full. Resubmit the transaction in the next block").Result()
+		}
 		nextBlockHeight := ds.NextPlasmaBlockHeight(ctx)
 
 		// construct the confirmation hash
@@ -35,9 +40,12 @@ func NewSpendHandler(ds store.DataStore, nextTxIndex NextTxIndex, feeUpdater Fee
 		/* Spend Inputs */
 		for _, input := range spendMsg.Inputs {
 			var res sdk.Result
-			if input.Position.IsDeposit() {
+			switch {
+			case input.Position.IsDeposit():
 				res = ds.SpendDeposit(ctx, input.Position.DepositNonce, spendMsg.TxHash())
-			} else {
+			case input.Position.IsFee():
+				res = ds.SpendFee(ctx, input.Position, spendMsg.TxHash())
+			default:
 				res = ds.SpendOutput(ctx, input.Position, spendMsg.TxHash())
 			}
 
@@ -12,9 +12,21 @@ import (
 	"testing"
 )
 
-var nextTxIndex = func() uint16 { return 0 }
+var nextTxIndex = func() (uint16, bool) { return 0, true }
 var feeUpdater = func(num *big.Int) sdk.Error { return nil }
 
+// returns a tx index counter for a block containing at most `max` transactions
+func blockTxIndex(max uint16) (NextTxIndex, *uint16) {
+	txIndex := new(uint16)
+	return func() (uint16, bool) {
+		if *txIndex >= max {
+			return 0, false
+		}
+		*txIndex++
+		return *txIndex - 1, true
+	}, txIndex
+}
+
 func TestSpend(t *testing.T) {
 	// blockStore is at next block height 1
 	ctx, ds := setup()
@@ -69,3 +81,48 @@ func TestSpend(t *testing.T) {
 	require.False(t, utxo.Spent, "new output marked as spent")
 	require.Equal(t, utxo.Output.Amount, big.NewInt(10), "new output has incorrect amount")
 }
+
+func TestSpendBlockFull(t *testing.T) {
+	// blockStore is at next block height 1
+	ctx, ds := setup()
+	privKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privKey.PublicKey)
+
+	const maxTxs = 5
+	next, txIndex := blockTxIndex(maxTxs)
+	spendHandler := NewSpendHandler(ds, next, feeUpdater)
+
+	spend := func(nonce int64) msgs.SpendMsg {
+		pos := plasma.NewPosition(utils.Big0, 0, 0, big.NewInt(nonce))
+		ds.StoreDeposit(ctx, pos.DepositNonce, plasma.NewDeposit(addr, big.NewInt(10), big.NewInt(1000)))
+		msg := msgs.SpendMsg{
+			Transaction: plasma.Transaction{
+				Inputs:  []plasma.Input{plasma.NewInput(pos, [65]byte{}, nil)},
+				Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(10))},
+				Fee:     utils.Big0,
+			},
+		}
+		sig, _ := crypto.Sign(utils.ToEthSignedMessageHash(msg.TxHash()), privKey)
+		copy(msg.Inputs[0].Signature[:], sig)
+		return msg
+	}
+
+	// fill the block
+	for i := int64(1); i <= maxTxs; i++ {
+		res := spendHandler(ctx, spend(i))
+		require.Truef(t, res.IsOK(), "failed to handle spend %d: %s", i, res)
+		_, ok := ds.GetOutput(ctx, plasma.NewPosition(utils.Big1, uint16(i-1), 0, nil))
+		require.Truef(t, ok, "output of spend %d was not created", i)
+	}
+	require.Equal(t, uint16(maxTxs), *txIndex)
+
+	// spends beyond the limit are rejected without changing state
+	res := spendHandler(ctx, spend(maxTxs+1))
+	require.Equal(t, CodeBlockFull, res.Code, "spend beyond the block limit not rejected")
+	dep, ok := ds.GetDeposit(ctx, big.NewInt(maxTxs+1))
+	require.True(t, ok)
+	require.False(t, dep.Spent, "input of a rejected spend marked as spent")
+	_, ok = ds.GetOutput(ctx, plasma.NewPosition(utils.Big1, maxTxs, 0, nil))
+	require.False(t, ok, "output created by a rejected spend")
+	require.Equal(t, uint16(maxTxs), *txIndex, "rejected spend consumed a tx index")
+}
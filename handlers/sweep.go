@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+)
+
+// FeeSweep is an unsigned transaction consolidating fee outputs into a single
+// output. If the output of the previous sweep is spent, its input requires
+// confirm signatures by the owner over ConfirmationHash
+type FeeSweep struct {
+	Transaction      plasma.Transaction
+	ConfirmationHash []byte
+}
+
+// NewFeeSweep builds a sweep of the fee outputs of `owner`, spending the output
+// of the previous sweep along with the next fee output, or the next two fee
+// outputs if there is no previous sweep. The rootchain contract only decodes
+// transactions with 2 inputs, so sweeps are chained to consolidate more outputs. The sweep pays the fee required by `policy`. Outputs for which
+// `exited` returns true are skipped. False is returned if there are fewer than
+// two outputs to consolidate or they do not cover the fee.
+func NewFeeSweep(fees store.FeeOutputs, owner common.Address, policy FeePolicy, exited func(plasma.Position) bool) (FeeSweep, bool) {
+	if exited == nil {
+		exited = func(plasma.Position) bool { return false }
+	}
+
+	var sweep FeeSweep
+	var inputs []plasma.Input
+	var amounts []*big.Int
+	if fees.Sweep != nil && fees.SweepInputs <= plasma.MaxTxInputs && !exited(fees.Sweep.Position) {
+		// placeholders for the confirm signatures filled in by Sign
+		inputs = append(inputs, plasma.NewInput(fees.Sweep.Position, [65]byte{}, make([][65]byte, fees.SweepInputs)))
+		amounts = append(amounts, fees.Sweep.Output.Amount)
+		sweep.ConfirmationHash = fees.Sweep.ConfirmationHash
+	}
+	for _, fee := range fees.Fees {
+		if len(inputs) == plasma.MaxTxInputs {
+			break
+		}
+		if !exited(fee.Position) {
+			inputs = append(inputs, plasma.NewInput(fee.Position, [65]byte{}, nil))
+			amounts = append(amounts, fee.Output.Amount)
+		}
+	}
+	if len(inputs) < 2 {
+		return FeeSweep{}, false
+	}
+
+	// the first input pays the fee, so the largest output is spent first
+	total, largest := new(big.Int), 0
+	for i, amount := range amounts {
+		total.Add(total, amount)
+		if amount.Cmp(amounts[largest]) > 0 {
+			largest = i
+		}
+	}
+	inputs[0], inputs[largest] = inputs[largest], inputs[0]
+
+	sweep.Transaction = plasma.Transaction{
+		Inputs:  inputs,
+		Outputs: []plasma.Output{plasma.NewOutput(owner, total)},
+		Fee:     big.NewInt(0),
+	}
+
+	// the required fee depends on the size of the transaction, which grows
+	// with the encoding of the fee. Input signatures are fixed width so the
+	// unsigned size matches the signed size
+	for {
+		txBytes, err := rlp.EncodeToBytes(&msgs.SpendMsg{Transaction: sweep.Transaction})
+		if err != nil {
+			return FeeSweep{}, false
+		}
+		required := policy.RequiredFee(len(txBytes))
+		if sweep.Transaction.Fee.Cmp(required) >= 0 {
+			break
+		}
+		if required.Cmp(amounts[largest]) > 0 || required.Cmp(total) >= 0 {
+			return FeeSweep{}, false
+		}
+		sweep.Transaction.Fee = required
+		sweep.Transaction.Outputs[0].Amount = new(big.Int).Sub(total, required)
+	}
+
+	return sweep, true
+}
+
+// Sign returns the signed sweep. `sign` signs over the ethereum signed message
+// hash of the given hash with the key of the owner and is used for both the
+// confirm signatures of the previous sweep and the input signatures
+func (s FeeSweep) Sign(sign func(hash []byte) ([]byte, error)) (plasma.Transaction, error) {
+	tx := s.Transaction
+	tx.Inputs = append([]plasma.Input{}, s.Transaction.Inputs...)
+
+	// the confirm signatures are part of the signed transaction
+	for i, input := range tx.Inputs {
+		if len(input.ConfirmSignatures) == 0 {
+			continue
+		}
+
+		sig, err := sign(s.ConfirmationHash)
+		if err != nil {
+			return tx, fmt.Errorf("failed to sign the confirmation hash: %s", err)
+		}
+		confirmSigs := make([][65]byte, len(input.ConfirmSignatures))
+		for j := range confirmSigs {
+			copy(confirmSigs[j][:], sig)
+		}
+		tx.Inputs[i].ConfirmSignatures = confirmSigs
+	}
+
+	sig, err := sign(tx.TxHash())
+	if err != nil {
+		return tx, fmt.Errorf("failed to sign the transaction: %s", err)
+	}
+	for i := range tx.Inputs {
+		copy(tx.Inputs[i].Signature[:], sig)
+	}
+
+	return tx, nil
+}
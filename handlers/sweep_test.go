@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func signWithKey(hash []byte) ([]byte, error) {
+	return crypto.Sign(utils.ToEthSignedMessageHash(hash), privKey)
+}
+
+func TestFeeSweep(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	policy := FeePolicy{MinFee: big.NewInt(1), FeePerByte: big.NewInt(1)}
+	anteHandler := NewAnteHandler(ds, conn{}, NewFeeSchedule(policy), nil)
+	next, _ := blockTxIndex(20)
+	spendHandler := NewSpendHandler(ds, next, feeUpdater)
+
+	// fees collected by the operator over 20 blocks
+	for i := int64(1); i <= 20; i++ {
+		ds.StoreFee(ctx, big.NewInt(i), plasma.NewOutput(addr, big.NewInt(10000)))
+	}
+
+	// delivers the signed sweep
+	deliver := func(sweep FeeSweep) {
+		tx, err := sweep.Sign(signWithKey)
+		require.NoError(t, err)
+		msg := msgs.SpendMsg{Transaction: tx}
+		require.NoError(t, msg.ValidateBasic())
+
+		txBytes, err := rlp.EncodeToBytes(&msg)
+		require.NoError(t, err)
+		require.True(t, tx.Fee.Cmp(policy.RequiredFee(len(txBytes))) >= 0, "sweep does not pay the required fee")
+
+		_, res, abort := anteHandler(ctx.WithIsCheckTx(true).WithTxBytes(txBytes), msg, false)
+		require.False(t, abort, res.Log)
+		res = spendHandler(ctx, msg)
+		require.True(t, res.IsOK(), res.Log)
+	}
+
+	// fees that have exited are skipped
+	exited := func(pos plasma.Position) bool { return pos.IsFee() && pos.BlockNum.Int64() == 1 }
+
+	// the first sweep spends two fees
+	sweep, ok := NewFeeSweep(ds.GetFeeOutputs(ctx, addr), addr, policy, exited)
+	require.True(t, ok)
+	require.Nil(t, sweep.ConfirmationHash)
+	require.Len(t, sweep.Transaction.Inputs, 2)
+	require.Equal(t, "(2.65535.0.0)", sweep.Transaction.Inputs[0].Position.String())
+	total := big.NewInt(10000 * 2)
+	require.Equal(t, total, new(big.Int).Add(sweep.Transaction.Outputs[0].Amount, sweep.Transaction.Fee))
+	deliver(sweep)
+
+	// the next sweep spends the previous sweep along with the next fee
+	fees := ds.GetFeeOutputs(ctx, addr)
+	require.NotNil(t, fees.Sweep)
+	require.Equal(t, 2, fees.SweepInputs)
+	sweep, ok = NewFeeSweep(fees, addr, policy, exited)
+	require.True(t, ok)
+	require.Equal(t, fees.Sweep.Position, sweep.Transaction.Inputs[0].Position)
+	require.Equal(t, fees.Sweep.ConfirmationHash, sweep.ConfirmationHash)
+	require.Len(t, sweep.Transaction.Inputs[0].ConfirmSignatures, 2)
+	require.Len(t, sweep.Transaction.Inputs, 2)
+	deliver(sweep)
+
+	// sweeps are chained until the remaining fees are consolidated
+	for i := 0; i < 16; i++ {
+		sweep, ok = NewFeeSweep(ds.GetFeeOutputs(ctx, addr), addr, policy, exited)
+		require.True(t, ok)
+		require.Len(t, sweep.Transaction.Inputs, 2)
+		deliver(sweep)
+	}
+
+	fees = ds.GetFeeOutputs(ctx, addr)
+	require.Len(t, fees.Fees, 1, "exited fee included in the sweep")
+	require.Equal(t, "(1.65535.0.0)", fees.Fees[0].Position.String())
+
+	// nothing is left to consolidate
+	_, ok = NewFeeSweep(fees, addr, policy, exited)
+	require.False(t, ok, "swept a single output")
+
+	// sweeps must cover the fee
+	fees = ds.GetFeeOutputs(ctx, addr)
+	_, ok = NewFeeSweep(fees, addr, FeePolicy{MinFee: fees.Total}, nil)
+	require.False(t, ok, "built a sweep that does not cover the fee")
+}
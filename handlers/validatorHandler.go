@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ValidatorUpdater is called with the amino encoded consensus public key and
+// the new voting power of every validator update
+type ValidatorUpdater func(consPubKey []byte, power uint64)
+
+// NewValidatorHandler updates the voting power of a validator. The update is
+// passed to `updateValidator` so that it can be returned to tendermint at the
+// end of the block
+func NewValidatorHandler(ds store.DataStore, nextTxIndex NextTxIndex, updateValidator ValidatorUpdater) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		validatorMsg, ok := msg.(msgs.UpdateValidatorMsg)
+		if !ok {
+			panic("Msg does not implement UpdateValidatorMsg")
+		}
+
+		// the msg is a leaf of the block's merkle tree, so it takes up a tx index
+		if _, ok := nextTxIndex(); !ok {
+			return ErrBlockFull("plasma block is full. Resubmit the update in the next block").Result()
+		}
+
+		consPubKey := validatorMsg.AminoConsPubKey()
+		ds.SetValidatorPower(ctx, consPubKey, validatorMsg.Power)
+		ds.SetValidatorUpdateNonce(ctx, ds.GetValidatorUpdateNonce(ctx)+1)
+		updateValidator(consPubKey, validatorMsg.Power)
+
+		return sdk.Result{}
+	}
+}
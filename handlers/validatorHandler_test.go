@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/msgs"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUpdateValidator(t *testing.T) {
+	// setup
+	ctx, ds := setup()
+	anteHandler := NewAnteHandler(ds, conn{}, nil, nil)
+
+	updates := make(map[string]uint64)
+	validatorHandler := NewValidatorHandler(ds, nextTxIndex, func(consPubKey []byte, power uint64) {
+		updates[string(consPubKey)] = power
+	})
+
+	update := func(consPubKey byte, power, nonce uint64, signer int) msgs.UpdateValidatorMsg {
+		msg := msgs.UpdateValidatorMsg{Power: power, Nonce: nonce, PlasmaContract: contractAddr}
+		msg.ConsPubKey[0] = consPubKey
+		key := privKey
+		if signer != 0 {
+			key = badPrivKey
+		}
+		sig, err := crypto.Sign(utils.ToEthSignedMessageHash(msg.GetSignBytes()), key)
+		require.NoError(t, err)
+		copy(msg.Signature[:], sig)
+		return msg
+	}
+
+	// genesis persists the operator
+	ds.SetOperator(ctx, addr)
+
+	// only the operator updates the validator set
+	_, res, abort := anteHandler(ctx, update(1, 10, 0, 1), false)
+	require.True(t, abort, "update not signed by the operator accepted")
+	require.Equal(t, CodeSignatureVerificationFailure, res.Code, res.Log)
+
+	// validators outside the set cannot be removed
+	_, res, abort = anteHandler(ctx, update(1, 0, 0, 0), false)
+	require.True(t, abort, "removal of a validator not in the set accepted")
+	require.Equal(t, CodeInvalidTransaction, res.Code, res.Log)
+
+	// updates signed for another sidechain are rejected
+	msg := update(1, 10, 0, 0)
+	msg.PlasmaContract = addr
+	sig, err := crypto.Sign(utils.ToEthSignedMessageHash(msg.GetSignBytes()), privKey)
+	require.NoError(t, err)
+	copy(msg.Signature[:], sig)
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.True(t, abort, "update for another contract accepted")
+	require.Equal(t, CodeInvalidTransaction, res.Code, res.Log)
+
+	msg = update(1, 10, 0, 0)
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.False(t, abort, res.Log)
+	require.True(t, validatorHandler(ctx, msg).IsOK())
+
+	set := ds.GetValidatorSet(ctx)
+	require.Len(t, set, 1)
+	require.Equal(t, msg.AminoConsPubKey(), set[0].ConsPubKey)
+	require.Equal(t, uint64(10), set[0].Power)
+	require.Equal(t, uint64(10), updates[string(msg.AminoConsPubKey())])
+	require.Equal(t, uint64(1), ds.GetValidatorUpdateNonce(ctx))
+
+	// the update cannot be replayed
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.True(t, abort, "replayed update accepted")
+	require.Equal(t, CodeInvalidTransaction, res.Code, res.Log)
+
+	_, res, abort = anteHandler(ctx, update(1, 0, 1, 0), false)
+	require.True(t, abort, "removal of the last validator accepted")
+	require.Equal(t, CodeInvalidTransaction, res.Code, res.Log)
+
+	// the total power is bounded
+	_, res, abort = anteHandler(ctx, update(2, msgs.MaxValidatorPower, 1, 0), false)
+	require.True(t, abort, "update exceeding the maximum power accepted")
+	require.Equal(t, CodeInvalidTransaction, res.Code, res.Log)
+
+	// add and then remove a second validator
+	msg = update(2, 5, 1, 0)
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.False(t, abort, res.Log)
+	require.True(t, validatorHandler(ctx, msg).IsOK())
+	require.Len(t, ds.GetValidatorSet(ctx), 2)
+
+	msg = update(1, 0, 2, 0)
+	_, res, abort = anteHandler(ctx, msg, false)
+	require.False(t, abort, res.Log)
+	require.True(t, validatorHandler(ctx, msg).IsOK())
+
+	set = ds.GetValidatorSet(ctx)
+	require.Len(t, set, 1)
+	require.Equal(t, uint64(5), set[0].Power)
+	require.Equal(t, uint64(0), updates[string(msg.AminoConsPubKey())])
+	require.Equal(t, uint64(3), ds.GetValidatorUpdateNonce(ctx))
+}
@@ -0,0 +1,127 @@
+// Package metrics provides the prometheus metrics of the plasma chain.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const namespace = "plasma"
+
+var (
+	// PlasmaBlockHeight is the height of the latest plasma block
+	PlasmaBlockHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "block_height",
+		Help:      "Height of the latest plasma block.",
+	})
+
+	// LastCommittedBlock is the latest plasma block committed to the rootchain
+	LastCommittedBlock = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_committed_block",
+		Help:      "Latest plasma block committed to the rootchain contract.",
+	})
+
+	// LastVerifiedBlock is the latest committed plasma block verified against the local store
+	LastVerifiedBlock = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_verified_block",
+		Help:      "Latest plasma block committed to the rootchain that was verified against the local store.",
+	})
+
+	// HeaderMismatches counts the committed plasma headers that do not match the local store
+	HeaderMismatches = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "header_mismatches_total",
+		Help:      "Plasma blocks committed to the rootchain that do not match the local store.",
+	})
+
+	// RootchainReorgs counts the reorgs of the rootchain that replaced blocks the sidechain relied upon
+	RootchainReorgs = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "eth",
+		Name:      "reorgs_total",
+		Help:      "Reorgs of the rootchain that replaced blocks relied upon by the sidechain.",
+	})
+
+	// DepositsHalted is 1 once deposit inclusion is halted by a reorg deeper than the finality bound
+	DepositsHalted = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "deposits_halted",
+		Help:      "Deposit inclusion is halted after a rootchain reorg deeper than the finality bound.",
+	})
+
+	// TxsPerBlock is the number of transactions included in each plasma block
+	TxsPerBlock = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "txs_per_block",
+		Help:      "Number of transactions included in a plasma block.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 17),
+	})
+
+	// Fees is the aggregate amount of fees collected in plasma blocks
+	Fees = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fees_total",
+		Help:      "Aggregate amount of fees, in wei, collected in plasma blocks.",
+	})
+
+	// AnteRejections counts the transactions rejected by the ante handler by error code
+	AnteRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ante_rejections_total",
+		Help:      "Transactions rejected by the ante handler.",
+	}, []string{"codespace", "code"})
+
+	// EthRPCLatency is the latency of ethereum rpc calls by method
+	EthRPCLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "eth",
+		Name:      "rpc_latency_seconds",
+		Help:      "Latency of rpc calls to the ethereum node.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// EthRPCErrors counts the failed ethereum rpc calls by method
+	EthRPCErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "eth",
+		Name:      "rpc_errors_total",
+		Help:      "Failed rpc calls to the ethereum node.",
+	}, []string{"method"})
+
+	// ExitQueueLength is the number of pending exits in the deposit and transaction exit queues
+	ExitQueueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exit_queue_length",
+		Help:      "Number of exits in the exit queues of the rootchain contract.",
+	}, []string{"queue"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		PlasmaBlockHeight,
+		LastCommittedBlock,
+		LastVerifiedBlock,
+		HeaderMismatches,
+		RootchainReorgs,
+		DepositsHalted,
+		TxsPerBlock,
+		Fees,
+		AnteRejections,
+		EthRPCLatency,
+		EthRPCErrors,
+		ExitQueueLength,
+	)
+}
+
+// NewServer returns a server exposing the metrics at `/metrics` on `addr`.
+// The server must be started by the caller
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &http.Server{Addr: addr, Handler: mux}
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// The registered metrics must be served on the metrics endpoint
+func TestMetricsServer(t *testing.T) {
+	PlasmaBlockHeight.Set(7)
+	AnteRejections.WithLabelValues("handlers", "2").Inc()
+	ExitQueueLength.WithLabelValues("deposit").Set(3)
+
+	server := httptest.NewServer(NewServer("").Handler)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	expected := []string{
+		"plasma_block_height 7",
+		`plasma_ante_rejections_total{code="2",codespace="handlers"} 1`,
+		`plasma_exit_queue_length{queue="deposit"} 3`,
+	}
+	for _, metric := range expected {
+		require.True(t, strings.Contains(string(body), metric), "metric not served: %s", metric)
+	}
+}
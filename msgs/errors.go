@@ -8,8 +8,10 @@ import (
 const (
 	DefaultCodespace sdk.CodespaceType = "msgs"
 
-	CodeInvalidSpendMsg          sdk.CodeType = 1
-	CodeInvalidIncludeDepositMsg sdk.CodeType = 2
+	CodeInvalidSpendMsg            sdk.CodeType = 1
+	CodeInvalidIncludeDepositMsg   sdk.CodeType = 2
+	CodeInvalidUpdateFeeAddressMsg sdk.CodeType = 3
+	CodeInvalidUpdateValidatorMsg  sdk.CodeType = 4
 )
 
 // ErrInvalidSpendMsg error for an invalid spend msg
@@ -21,3 +23,13 @@ func ErrInvalidSpendMsg(codespace sdk.CodespaceType, msg string, args ...interfa
 func ErrInvalidIncludeDepositMsg(codespace sdk.CodespaceType, msg string, args ...interface{}) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidIncludeDepositMsg, msg, args...)
 }
+
+// ErrInvalidUpdateFeeAddressMsg error for an invalid update fee address msg
+func ErrInvalidUpdateFeeAddressMsg(codespace sdk.CodespaceType, msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidUpdateFeeAddressMsg, msg, args...)
+}
+
+// ErrInvalidUpdateValidatorMsg error for an invalid update validator msg
+func ErrInvalidUpdateValidatorMsg(codespace sdk.CodespaceType, msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidUpdateValidatorMsg, msg, args...)
+}
@@ -0,0 +1,72 @@
+package msgs
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// FeeAddressMsgRoute is used for routing this message.
+	FeeAddressMsgRoute = "feeaddress"
+)
+
+var _ sdk.Tx = UpdateFeeAddressMsg{}
+
+// UpdateFeeAddressMsg changes the address fees are collected to. It must be
+// signed by the current fee address over the nonce of the update so that it
+// cannot be replayed, and over the address of the rootchain contract so that
+// it cannot be replayed against another sidechain.
+type UpdateFeeAddressMsg struct {
+	FeeAddress     common.Address
+	Nonce          uint64
+	PlasmaContract common.Address
+	Signature      [65]byte
+}
+
+// Type returns the message type.
+func (msg UpdateFeeAddressMsg) Type() string { return "update_fee_address" }
+
+// Route returns the route for this message.
+func (msg UpdateFeeAddressMsg) Route() string { return FeeAddressMsgRoute }
+
+// GetSigners returns the address recovered from the signature.
+// CONTRACT: a nil slice is returned if recovery fails
+func (msg UpdateFeeAddressMsg) GetSigners() []sdk.AccAddress {
+	hash := utils.ToEthSignedMessageHash(msg.GetSignBytes())
+	pubKey, err := crypto.SigToPub(hash, msg.Signature[:])
+	if err != nil {
+		return nil
+	}
+
+	return []sdk.AccAddress{sdk.AccAddress(crypto.PubkeyToAddress(*pubKey).Bytes())}
+}
+
+// GetSignBytes returns the Keccak256 hash of the update without the signature.
+func (msg UpdateFeeAddressMsg) GetSignBytes() []byte {
+	bytes, err := rlp.EncodeToBytes([]interface{}{msg.Type(), msg.FeeAddress, msg.Nonce, msg.PlasmaContract})
+	if err != nil {
+		panic(err)
+	}
+
+	return crypto.Keccak256(bytes)
+}
+
+// ValidateBasic asserts that a signer can be recovered and that fees are not
+// collected to the zero address.
+func (msg UpdateFeeAddressMsg) ValidateBasic() sdk.Error {
+	if utils.IsZeroAddress(msg.FeeAddress) {
+		return ErrInvalidUpdateFeeAddressMsg(DefaultCodespace, "fees cannot be collected to the zero address")
+	}
+	if len(msg.GetSigners()) == 0 {
+		return ErrInvalidUpdateFeeAddressMsg(DefaultCodespace, "failed recovering the signer")
+	}
+	return nil
+}
+
+// GetMsgs implements the sdk.Tx interface
+func (msg UpdateFeeAddressMsg) GetMsgs() []sdk.Msg {
+	return []sdk.Msg{msg}
+}
@@ -0,0 +1,70 @@
+package msgs
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestFeeAddressMsgSignature(t *testing.T) {
+	msg := UpdateFeeAddressMsg{
+		FeeAddress: ethcmn.HexToAddress("1"),
+		Nonce:      1,
+	}
+	require.Error(t, msg.ValidateBasic(), "validated an unsigned msg")
+
+	sig, err := crypto.Sign(utils.ToEthSignedMessageHash(msg.GetSignBytes()), privKey)
+	require.NoError(t, err)
+	copy(msg.Signature[:], sig)
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, addr.Bytes(), []byte(msg.GetSigners()[0]))
+
+	// fees cannot be collected to the zero address
+	zero := UpdateFeeAddressMsg{Nonce: 1}
+	sig, err = crypto.Sign(utils.ToEthSignedMessageHash(zero.GetSignBytes()), privKey)
+	require.NoError(t, err)
+	copy(zero.Signature[:], sig)
+	require.Error(t, zero.ValidateBasic(), "validated an update to the zero address")
+
+	// the signature covers the fee address, the nonce and the plasma contract
+	replayed := msg
+	replayed.Nonce = 2
+	require.NotEqual(t, addr.Bytes(), []byte(replayed.GetSigners()[0]), "signature valid for another nonce")
+	replayed = msg
+	replayed.FeeAddress = ethcmn.HexToAddress("2")
+	require.NotEqual(t, addr.Bytes(), []byte(replayed.GetSigners()[0]), "signature valid for another fee address")
+	replayed = msg
+	replayed.PlasmaContract = ethcmn.HexToAddress("3")
+	require.NotEqual(t, addr.Bytes(), []byte(replayed.GetSigners()[0]), "signature valid for another plasma contract")
+}
+
+func TestFeeAddressMsgSerialization(t *testing.T) {
+	msg := UpdateFeeAddressMsg{
+		FeeAddress:     addr,
+		Nonce:          3,
+		PlasmaContract: ethcmn.HexToAddress("2"),
+		Signature:      [65]byte{1},
+	}
+
+	bytes, err := rlp.EncodeToBytes(&msg)
+	require.NoError(t, err, "serialization error")
+	tx, err := TxDecoder(bytes)
+	require.NoError(t, err, "deserialization error")
+	require.True(t, reflect.DeepEqual(msg, tx), "serialized and deserialized msgs not equal")
+
+	// deposit msgs are not mistaken for fee address updates
+	depositMsg := IncludeDepositMsg{
+		DepositNonce: new(big.Int).SetBytes(addr.Bytes()),
+		Owner:        addr,
+	}
+	bytes, err = rlp.EncodeToBytes(&depositMsg)
+	require.NoError(t, err, "serialization error")
+	tx, err = TxDecoder(bytes)
+	require.NoError(t, err, "deserialization error")
+	require.IsType(t, IncludeDepositMsg{}, tx)
+}
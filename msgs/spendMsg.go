@@ -31,16 +31,8 @@ func (msg SpendMsg) GetSigners() []sdk.AccAddress {
 	txHash := utils.ToEthSignedMessageHash(msg.TxHash())
 	var addrs []sdk.AccAddress
 
-	// recover first owner
-	pubKey, err := crypto.SigToPub(txHash, msg.Inputs[0].Signature[:])
-	if err != nil {
-		return nil
-	}
-	addrs = append(addrs, sdk.AccAddress(crypto.PubkeyToAddress(*pubKey).Bytes()))
-
-	if len(msg.Inputs) > 1 {
-		// recover the second owner
-		pubKey, err = crypto.SigToPub(txHash, msg.Inputs[1].Signature[:])
+	for _, input := range msg.Inputs {
+		pubKey, err := crypto.SigToPub(txHash, input.Signature[:])
 		if err != nil {
 			return nil
 		}
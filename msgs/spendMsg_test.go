@@ -1,11 +1,14 @@
 package msgs
 
 import (
+	"crypto/ecdsa"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/FourthState/plasma-mvp-sidechain/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -28,3 +31,29 @@ func TestSpendMsgSerialization(t *testing.T) {
 
 	require.True(t, reflect.DeepEqual(msg, recoveredMsg), "serialized and deserialized msgs not equal")
 }
+
+func TestSpendMsgSigners(t *testing.T) {
+	var keys []*ecdsa.PrivateKey
+	tx := plasma.Transaction{
+		Outputs: []plasma.Output{plasma.NewOutput(common.HexToAddress("1"), utils.Big1)},
+		Fee:     utils.Big0,
+	}
+	for i := 1; i <= plasma.MaxTxInputs; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		tx.Inputs = append(tx.Inputs, plasma.NewInput(plasma.NewPosition(nil, 0, 0, big.NewInt(int64(i))), [65]byte{}, nil))
+	}
+
+	txHash := utils.ToEthSignedMessageHash(tx.TxHash())
+	for i, key := range keys {
+		sig, err := crypto.Sign(txHash, key)
+		require.NoError(t, err)
+		copy(tx.Inputs[i].Signature[:], sig)
+	}
+
+	signers := SpendMsg{tx}.GetSigners()
+	require.Len(t, signers, len(keys), "signer not recovered for every input")
+	for i, key := range keys {
+		require.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Bytes(), []byte(signers[i]), "signer mismatch for input %d", i)
+	}
+}
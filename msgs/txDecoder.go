@@ -6,15 +6,24 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
-// TxDecoder attempts to RLP decode the transaction bytes into a SpendMsg first
-// then to a IncludeDepositMsg otherwise returns an error.
+// TxDecoder attempts to RLP decode the transaction bytes into a SpendMsg first,
+// then to a IncludeDepositMsg, a UpdateFeeAddressMsg and a UpdateValidatorMsg
+// otherwise returns an error.
 func TxDecoder(txBytes []byte) (sdk.Tx, sdk.Error) {
 	var spendMsg SpendMsg
 	if err := rlp.DecodeBytes(txBytes, &spendMsg); err != nil {
 		var depositMsg IncludeDepositMsg
 		if err2 := rlp.DecodeBytes(txBytes, &depositMsg); err2 != nil {
-			return nil, sdk.ErrTxDecode(fmt.Sprintf("decode to SpendMsg: %s. Decode to DepositMsg: %s",
-				err.Error(), err2.Error()))
+			var feeAddressMsg UpdateFeeAddressMsg
+			if err3 := rlp.DecodeBytes(txBytes, &feeAddressMsg); err3 != nil {
+				var validatorMsg UpdateValidatorMsg
+				if err4 := rlp.DecodeBytes(txBytes, &validatorMsg); err4 != nil {
+					return nil, sdk.ErrTxDecode(fmt.Sprintf("decode to SpendMsg: %s. Decode to DepositMsg: %s. Decode to FeeAddressMsg: %s. Decode to ValidatorMsg: %s",
+						err.Error(), err2.Error(), err3.Error(), err4.Error()))
+				}
+				return validatorMsg, nil
+			}
+			return feeAddressMsg, nil
 		}
 		return depositMsg, nil
 	}
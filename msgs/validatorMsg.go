@@ -0,0 +1,86 @@
+package msgs
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"math"
+)
+
+const (
+	// ValidatorMsgRoute is used for routing this message.
+	ValidatorMsgRoute = "validator"
+
+	// MaxValidatorPower is the maximum total voting power of the validator
+	// set accepted by tendermint
+	MaxValidatorPower = math.MaxInt64 / 8
+)
+
+var _ sdk.Tx = UpdateValidatorMsg{}
+
+// UpdateValidatorMsg sets the voting power of the validator with the ed25519
+// consensus public key ConsPubKey. A power of zero removes the validator. It
+// must be signed by the operator of the rootchain contract over the nonce of
+// the update so that it cannot be replayed, and over the address of the
+// rootchain contract so that it cannot be replayed against another sidechain.
+type UpdateValidatorMsg struct {
+	ConsPubKey     [32]byte
+	Power          uint64
+	Nonce          uint64
+	PlasmaContract common.Address
+	Signature      [65]byte
+}
+
+// Type returns the message type.
+func (msg UpdateValidatorMsg) Type() string { return "update_validator" }
+
+// Route returns the route for this message.
+func (msg UpdateValidatorMsg) Route() string { return ValidatorMsgRoute }
+
+// GetSigners returns the address recovered from the signature.
+// CONTRACT: a nil slice is returned if recovery fails
+func (msg UpdateValidatorMsg) GetSigners() []sdk.AccAddress {
+	hash := utils.ToEthSignedMessageHash(msg.GetSignBytes())
+	pubKey, err := crypto.SigToPub(hash, msg.Signature[:])
+	if err != nil {
+		return nil
+	}
+
+	return []sdk.AccAddress{sdk.AccAddress(crypto.PubkeyToAddress(*pubKey).Bytes())}
+}
+
+// GetSignBytes returns the Keccak256 hash of the update without the signature.
+func (msg UpdateValidatorMsg) GetSignBytes() []byte {
+	bytes, err := rlp.EncodeToBytes([]interface{}{msg.Type(), msg.ConsPubKey, msg.Power, msg.Nonce, msg.PlasmaContract})
+	if err != nil {
+		panic(err)
+	}
+
+	return crypto.Keccak256(bytes)
+}
+
+// ValidateBasic asserts that the power is within the bounds of tendermint and
+// that a signer can be recovered.
+func (msg UpdateValidatorMsg) ValidateBasic() sdk.Error {
+	if msg.Power > MaxValidatorPower {
+		return ErrInvalidUpdateValidatorMsg(DefaultCodespace, "Power must not exceed %d", uint64(MaxValidatorPower))
+	}
+	if len(msg.GetSigners()) == 0 {
+		return ErrInvalidUpdateValidatorMsg(DefaultCodespace, "failed recovering the signer")
+	}
+	return nil
+}
+
+// AminoConsPubKey returns the amino encoded consensus public key, as it is
+// stored in the validator set.
+func (msg UpdateValidatorMsg) AminoConsPubKey() []byte {
+	return ed25519.PubKeyEd25519(msg.ConsPubKey).Bytes()
+}
+
+// GetMsgs implements the sdk.Tx interface
+func (msg UpdateValidatorMsg) GetMsgs() []sdk.Msg {
+	return []sdk.Msg{msg}
+}
@@ -0,0 +1,66 @@
+package msgs
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestValidatorMsgValidate(t *testing.T) {
+	sign := func(msg UpdateValidatorMsg) UpdateValidatorMsg {
+		sig, err := crypto.Sign(utils.ToEthSignedMessageHash(msg.GetSignBytes()), privKey)
+		require.NoError(t, err)
+		copy(msg.Signature[:], sig)
+		return msg
+	}
+
+	msg := UpdateValidatorMsg{ConsPubKey: [32]byte{1}, Power: 10}
+	require.Error(t, msg.ValidateBasic(), "validated an unsigned msg")
+
+	msg = sign(msg)
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, addr.Bytes(), []byte(msg.GetSigners()[0]))
+
+	// the signature covers the key, power, nonce and plasma contract
+	for _, replayed := range []UpdateValidatorMsg{
+		{ConsPubKey: [32]byte{2}, Power: 10, Signature: msg.Signature},
+		{ConsPubKey: [32]byte{1}, Power: 11, Signature: msg.Signature},
+		{ConsPubKey: [32]byte{1}, Power: 10, Nonce: 1, Signature: msg.Signature},
+		{ConsPubKey: [32]byte{1}, Power: 10, PlasmaContract: addr, Signature: msg.Signature},
+	} {
+		require.NotEqual(t, addr.Bytes(), []byte(replayed.GetSigners()[0]), "signature valid for %v", replayed)
+	}
+
+	// removals are valid
+	require.NoError(t, sign(UpdateValidatorMsg{ConsPubKey: [32]byte{1}}).ValidateBasic())
+
+	tooLarge := sign(UpdateValidatorMsg{ConsPubKey: [32]byte{1}, Power: MaxValidatorPower + 1})
+	require.Error(t, tooLarge.ValidateBasic(), "validated a power exceeding the maximum")
+}
+
+func TestValidatorMsgSerialization(t *testing.T) {
+	msg := UpdateValidatorMsg{
+		ConsPubKey:     [32]byte{1},
+		Power:          10,
+		Nonce:          2,
+		PlasmaContract: addr,
+		Signature:      [65]byte{1},
+	}
+
+	bytes, err := rlp.EncodeToBytes(&msg)
+	require.NoError(t, err, "serialization error")
+	tx, err := TxDecoder(bytes)
+	require.NoError(t, err, "deserialization error")
+	require.True(t, reflect.DeepEqual(msg, tx), "serialized and deserialized msgs not equal")
+
+	// fee address updates are not mistaken for validator updates
+	feeAddressMsg := UpdateFeeAddressMsg{FeeAddress: addr, Nonce: 3}
+	bytes, err = rlp.EncodeToBytes(&feeAddressMsg)
+	require.NoError(t, err, "serialization error")
+	tx, err = TxDecoder(bytes)
+	require.NoError(t, err, "deserialization error")
+	require.IsType(t, UpdateFeeAddressMsg{}, tx)
+}
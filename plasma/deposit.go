@@ -31,6 +31,12 @@ func NewDeposit(owner common.Address, amount *big.Int, ethBlockNum *big.Int) Dep
 
 // EncodeRLP satisfies the rlp interface for Deposit.
 func (d *Deposit) EncodeRLP(w io.Writer) error {
+	if err := checkUint256("amount", d.Amount); err != nil {
+		return err
+	}
+	if err := checkUint256("eth block number", d.EthBlockNum); err != nil {
+		return err
+	}
 	deposit := &deposit{d.Owner, d.Amount.Bytes(), d.EthBlockNum.Bytes()}
 
 	return rlp.Encode(w, deposit)
@@ -43,9 +49,18 @@ func (d *Deposit) DecodeRLP(s *rlp.Stream) error {
 		return err
 	}
 
+	amount, err := decodeUint256("amount", dep.Amount)
+	if err != nil {
+		return err
+	}
+	ethBlockNum, err := decodeUint256("eth block number", dep.EthBlockNum)
+	if err != nil {
+		return err
+	}
+
 	d.Owner = dep.Owner
-	d.Amount = new(big.Int).SetBytes(dep.Amount)
-	d.EthBlockNum = new(big.Int).SetBytes(dep.EthBlockNum)
+	d.Amount = amount
+	d.EthBlockNum = ethBlockNum
 
 	return nil
 }
@@ -53,8 +53,8 @@ func (i Input) ValidateBasic() error {
 				return fmt.Errorf("deposit or fee inputs must not include confirm signatures")
 			}
 		} else {
-			if confSigLen != 1 && confSigLen != 2 {
-				return fmt.Errorf("transaction inputs must specify 1 or 2 confirm signatures")
+			if confSigLen < 1 || confSigLen > MaxTxInputs {
+				return fmt.Errorf("transaction inputs must specify between 1 and %d confirm signatures", MaxTxInputs)
 			}
 
 			for _, sig := range i.ConfirmSignatures {
@@ -72,8 +72,8 @@ func (i Input) String() string {
 	if len(i.ConfirmSignatures) != 0 {
 		str := fmt.Sprintf("Position: %s, Signature: 0x%x, Confirm Signatures: 0x%x",
 			i.Position, i.Signature, i.ConfirmSignatures[0])
-		if len(i.ConfirmSignatures) > 1 {
-			str = str + fmt.Sprintf(", 0x%x", i.ConfirmSignatures[1])
+		for _, sig := range i.ConfirmSignatures[1:] {
+			str = str + fmt.Sprintf(", 0x%x", sig)
 		}
 
 		return str
@@ -32,6 +32,9 @@ func NewOutput(owner common.Address, amount *big.Int) Output {
 }
 
 func (o *Output) EncodeRLP(w io.Writer) error {
+	if err := checkUint256("amount", o.Amount); err != nil {
+		return err
+	}
 	output := output{o.Owner, o.Amount.Bytes()}
 
 	return rlp.Encode(w, output)
@@ -43,8 +46,13 @@ func (o *Output) DecodeRLP(s *rlp.Stream) error {
 		return err
 	}
 
+	amount, err := decodeUint256("amount", output.Amount)
+	if err != nil {
+		return err
+	}
+
 	o.Owner = output.Owner
-	o.Amount = new(big.Int).SetBytes(output.Amount)
+	o.Amount = amount
 
 	return nil
 }
@@ -3,6 +3,7 @@ package plasma
 import (
 	"fmt"
 	"github.com/ethereum/go-ethereum/rlp"
+	"io"
 	"math/big"
 	"strconv"
 	"strings"
@@ -36,6 +37,38 @@ func NewPosition(blkNum *big.Int, txIndex uint16, oIndex uint8, depositNonce *bi
 	}
 }
 
+// position is encoded with the default rlp encoding of Position
+type position Position
+
+// EncodeRLP satisfies the rlp interface for Position.
+func (p *Position) EncodeRLP(w io.Writer) error {
+	if err := checkUint256("block number", p.BlockNum); err != nil {
+		return err
+	}
+	if err := checkUint256("deposit nonce", p.DepositNonce); err != nil {
+		return err
+	}
+
+	return rlp.Encode(w, (*position)(p))
+}
+
+// DecodeRLP satisfies the rlp interface for Position.
+func (p *Position) DecodeRLP(s *rlp.Stream) error {
+	var pos position
+	if err := s.Decode(&pos); err != nil {
+		return err
+	}
+	if err := checkUint256("block number", pos.BlockNum); err != nil {
+		return err
+	}
+	if err := checkUint256("deposit nonce", pos.DepositNonce); err != nil {
+		return err
+	}
+
+	*p = Position(pos)
+	return nil
+}
+
 func (p Position) Bytes() []byte {
 	bytes, _ := rlp.EncodeToBytes(&p)
 	return bytes
@@ -47,6 +80,12 @@ func (p Position) ValidateBasic() error {
 	if p.IsNilPosition() {
 		return fmt.Errorf("nil position is not a valid position")
 	}
+	if err := checkUint256("block number", p.BlockNum); err != nil {
+		return err
+	}
+	if err := checkUint256("deposit nonce", p.DepositNonce); err != nil {
+		return err
+	}
 
 	// deposit position
 	if p.IsDeposit() {
@@ -57,8 +96,8 @@ func (p Position) ValidateBasic() error {
 		if p.BlockNum.Sign() == 0 {
 			return fmt.Errorf("block numbering starts at 1")
 		}
-		if p.OutputIndex > 1 {
-			return fmt.Errorf("output index must be 0 or 1")
+		if p.OutputIndex >= MaxTxOutputs {
+			return fmt.Errorf("output index must be less than %d", MaxTxOutputs)
 		}
 	}
 
@@ -165,6 +204,8 @@ func FromExitKey(key *big.Int, deposit bool) Position {
 	if deposit {
 		return NewPosition(big.NewInt(0), 0, 0, key)
 	} else {
-		return NewPosition(new(big.Int).Div(key, big.NewInt(blockIndexFactor)), uint16(key.Int64()%blockIndexFactor/txIndexFactor), uint8(key.Int64()%2), big.NewInt(0))
+		blkNum, rem := new(big.Int).DivMod(key, big.NewInt(blockIndexFactor), new(big.Int))
+		txIndex, oIndex := rem.Int64()/txIndexFactor, rem.Int64()%txIndexFactor
+		return NewPosition(blkNum, uint16(txIndex), uint8(oIndex), big.NewInt(0))
 	}
 }
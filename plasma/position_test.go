@@ -80,6 +80,12 @@ func TestPositionFromKey(t *testing.T) {
 	utxo := FromExitKey(utxoKey, false)
 	require.Equal(t, utxo, NewPosition(big.NewInt(133), 14, 0, big.NewInt(0)), "error retrieving correct position from exit key")
 
+	// block numbers beyond 64 bits
+	blkNum := new(big.Int).Lsh(big.NewInt(1), 200)
+	utxoKey = NewPosition(blkNum, 65534, 3, nil).Priority()
+	utxo = FromExitKey(utxoKey, false)
+	require.Equal(t, NewPosition(blkNum, 65534, 3, big.NewInt(0)), utxo, "error retrieving correct position from a large exit key")
+
 	depositKey := big.NewInt(10)
 	deposit := FromExitKey(depositKey, true)
 	require.Equal(t, deposit, NewPosition(big.NewInt(0), 0, 0, depositKey), "error retrieving correct position from deposit exit key")
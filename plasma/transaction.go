@@ -14,6 +14,15 @@ import (
 	"math/big"
 )
 
+const (
+	// MaxTxInputs is the maximum number of inputs, and of confirm signatures per
+	// input, of a transaction. The rootchain contract only decodes transactions
+	// with up to 2 inputs and 2 outputs
+	MaxTxInputs = 2
+	// MaxTxOutputs is the maximum number of outputs of a transaction
+	MaxTxOutputs = 2
+)
+
 // Transaction represents a spend of inputs. Fields should not be accessed directly
 type Transaction struct {
 	Inputs  []Input
@@ -46,7 +55,13 @@ type rawTx struct {
 
 // EncodeRLP satisfies the rlp interface for Transaction
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	t := &rawTx{tx.toTxList(), tx.Sigs()}
+	if err := tx.checkUint256(); err != nil {
+		return err
+	}
+
+	var sigs [2][65]byte
+	copy(sigs[:], tx.Sigs())
+	t := &rawTx{tx.toTxList(), sigs}
 
 	return rlp.Encode(w, t)
 }
@@ -58,29 +73,38 @@ func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 		return err
 	}
 
-	confirmSigs0 := parseSig(t.Tx.Input0ConfirmSigs)
-	confirmSigs1 := parseSig(t.Tx.Input1ConfirmSigs)
+	pos0, err := decodePosition(0, t.Tx.BlkNum0, t.Tx.TxIndex0, t.Tx.OIndex0, t.Tx.DepositNonce0)
+	if err != nil {
+		return err
+	}
+	pos1, err := decodePosition(1, t.Tx.BlkNum1, t.Tx.TxIndex1, t.Tx.OIndex1, t.Tx.DepositNonce1)
+	if err != nil {
+		return err
+	}
 
-	tx.Inputs = append(tx.Inputs, NewInput(NewPosition(big.NewInt(new(big.Int).SetBytes(t.Tx.BlkNum0[:]).Int64()), uint16(new(big.Int).SetBytes(t.Tx.TxIndex0[:]).Int64()), uint8(new(big.Int).SetBytes(t.Tx.OIndex0[:]).Int64()), big.NewInt(new(big.Int).SetBytes(t.Tx.DepositNonce0[:]).Int64())),
-		t.Sigs[0], confirmSigs0))
-	pos := NewPosition(big.NewInt(new(big.Int).SetBytes(t.Tx.BlkNum1[:]).Int64()), uint16(new(big.Int).SetBytes(t.Tx.TxIndex1[:]).Int64()), uint8(new(big.Int).SetBytes(t.Tx.OIndex1[:]).Int64()), big.NewInt(new(big.Int).SetBytes(t.Tx.DepositNonce1[:]).Int64()))
-	if !pos.IsNilPosition() {
-		tx.Inputs = append(tx.Inputs, NewInput(pos, t.Sigs[1], confirmSigs1))
+	tx.Inputs = append(tx.Inputs, NewInput(pos0, t.Sigs[0], parseSig(t.Tx.Input0ConfirmSigs)))
+	if !pos1.IsNilPosition() {
+		tx.Inputs = append(tx.Inputs, NewInput(pos1, t.Sigs[1], parseSig(t.Tx.Input1ConfirmSigs)))
 	}
-	// set signatures if applicable
-	tx.Outputs = append(tx.Outputs, NewOutput(t.Tx.NewOwner0, big.NewInt(new(big.Int).SetBytes(t.Tx.Amount0[:]).Int64())))
+	tx.Outputs = append(tx.Outputs, NewOutput(t.Tx.NewOwner0, fromBytes32(t.Tx.Amount0)))
 	if !utils.IsZeroAddress(t.Tx.NewOwner1) {
-		tx.Outputs = append(tx.Outputs, NewOutput(t.Tx.NewOwner1, big.NewInt(new(big.Int).SetBytes(t.Tx.Amount1[:]).Int64())))
+		tx.Outputs = append(tx.Outputs, NewOutput(t.Tx.NewOwner1, fromBytes32(t.Tx.Amount1)))
 	}
-	tx.Fee = big.NewInt(new(big.Int).SetBytes(t.Tx.Fee[:]).Int64())
+	tx.Fee = fromBytes32(t.Tx.Fee)
 
 	return nil
 }
 
 func (tx Transaction) ValidateBasic() error {
-	// Error if more than 2 inputs or outputs. Will be allowed in future versions
-	if len(tx.Inputs) > 2 || len(tx.Outputs) > 2 {
-		return fmt.Errorf("invalid tx, maximum of 2 input/output allowed")
+	if len(tx.Inputs) == 0 || len(tx.Outputs) == 0 {
+		return fmt.Errorf("invalid tx, at least 1 input and output required")
+	}
+	if len(tx.Inputs) > MaxTxInputs || len(tx.Outputs) > MaxTxOutputs {
+		return fmt.Errorf("invalid tx, maximum of %d inputs and %d outputs allowed", MaxTxInputs, MaxTxOutputs)
+	}
+
+	if err := tx.checkUint256(); err != nil {
+		return fmt.Errorf("invalid tx, %s", err)
 	}
 
 	// validate inputs
@@ -93,10 +117,12 @@ func (tx Transaction) ValidateBasic() error {
 		}
 	}
 
-	if len(tx.Inputs) > 1 {
-		if tx.Inputs[0].Position.String() == tx.Inputs[1].Position.String() {
+	positions := make(map[string]bool)
+	for _, input := range tx.Inputs {
+		if positions[input.Position.String()] {
 			return fmt.Errorf("same position cannot be spent twice")
 		}
+		positions[input.Position.String()] = true
 	}
 
 	// validate outputs
@@ -133,8 +159,8 @@ func (tx Transaction) MerkleHash() []byte {
 
 // Sigs returns the signatures that signed over this transaction
 // These signatures were generated by the inputs
-func (tx Transaction) Sigs() [2][65]byte {
-	var sigs [2][65]byte
+func (tx Transaction) Sigs() [][65]byte {
+	sigs := make([][65]byte, len(tx.Inputs))
 	for i, input := range tx.Inputs {
 		sigs[i] = input.Signature
 	}
@@ -176,6 +202,25 @@ func (tx Transaction) String() string {
 
 /* Helpers */
 
+// every integer of the transaction must fit into its 32 byte encoding
+func (tx Transaction) checkUint256() error {
+	for i, input := range tx.Inputs {
+		if err := checkUint256(fmt.Sprintf("block number of input %d", i), input.BlockNum); err != nil {
+			return err
+		}
+		if err := checkUint256(fmt.Sprintf("deposit nonce of input %d", i), input.DepositNonce); err != nil {
+			return err
+		}
+	}
+	for i, output := range tx.Outputs {
+		if err := checkUint256(fmt.Sprintf("amount of output %d", i), output.Amount); err != nil {
+			return err
+		}
+	}
+
+	return checkUint256("fee", tx.Fee)
+}
+
 func (tx Transaction) toTxList() txList {
 
 	// pointer safety if a transaction
@@ -263,6 +308,30 @@ func (tx Transaction) toTxList() txList {
 }
 
 // Helpers
+// parse a 32 byte big endian integer. zero is returned in its canonical form
+func fromBytes32(bytes [32]byte) *big.Int {
+	num := new(big.Int).SetBytes(bytes[:])
+	if num.Sign() == 0 {
+		return big.NewInt(0)
+	}
+
+	return num
+}
+
+// parse the position of the input at `index` from its 32 byte fields
+func decodePosition(index int, blkNum, txIndex, oIndex, depositNonce [32]byte) (Position, error) {
+	txIdx, err := decodeUint(fmt.Sprintf("tx index of input %d", index), txIndex, 16)
+	if err != nil {
+		return Position{}, err
+	}
+	oIdx, err := decodeUint(fmt.Sprintf("output index of input %d", index), oIndex, 8)
+	if err != nil {
+		return Position{}, err
+	}
+
+	return NewPosition(fromBytes32(blkNum), uint16(txIdx), uint8(oIdx), fromBytes32(depositNonce)), nil
+}
+
 // Convert 130 byte input confirm sigs to 65 byte slices
 func parseSig(sig [130]byte) [][65]byte {
 	if bytes.Equal(sig[:65], make([]byte, 65)) {
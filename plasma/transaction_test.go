@@ -96,6 +96,33 @@ func TestTransactionValidation(t *testing.T) {
 		},
 	}
 
+	var manyInputs []Input
+	var manyOutputs []Output
+	for i := 1; i <= MaxTxInputs+1; i++ {
+		manyInputs = append(manyInputs, NewInput(NewPosition(nil, 0, 0, big.NewInt(int64(i))), sampleSig, nil))
+	}
+	for i := 0; i <= MaxTxOutputs; i++ {
+		manyOutputs = append(manyOutputs, NewOutput(addr, utils.Big1))
+	}
+	invalidTxs = append(invalidTxs,
+		validationCase{
+			reason: "tx with too many inputs",
+			Transaction: Transaction{
+				Inputs:  manyInputs,
+				Outputs: []Output{NewOutput(addr, utils.Big1)},
+				Fee:     utils.Big0,
+			},
+		},
+		validationCase{
+			reason: "tx with too many outputs",
+			Transaction: Transaction{
+				Inputs:  manyInputs[:1],
+				Outputs: manyOutputs,
+				Fee:     utils.Big0,
+			},
+		},
+	)
+
 	for _, tx := range invalidTxs {
 		err := tx.ValidateBasic()
 		require.Error(t, err, tx.reason)
@@ -136,6 +163,15 @@ func TestTransactionValidation(t *testing.T) {
 		},
 	}
 
+	validTxs = append(validTxs, validationCase{
+		reason: "tx with the maximum number of inputs and outputs",
+		Transaction: Transaction{
+			Inputs:  manyInputs[:MaxTxInputs],
+			Outputs: manyOutputs[:MaxTxOutputs],
+			Fee:     utils.Big0,
+		},
+	})
+
 	for _, tx := range validTxs {
 		err := tx.ValidateBasic()
 		require.NoError(t, err, tx.reason)
@@ -0,0 +1,52 @@
+package plasma
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// The rootchain contract represents amounts, block numbers and deposit nonces
+// as uint256. Values are encoded and decoded in full and anything that does
+// not fit into 256 bits is rejected rather than truncated
+
+// checkUint256 returns an error if `num` cannot be represented as a uint256.
+// nil is treated as zero
+func checkUint256(field string, num *big.Int) error {
+	if num == nil {
+		return nil
+	}
+	if num.Sign() < 0 {
+		return fmt.Errorf("%s cannot be negative", field)
+	}
+	if num.BitLen() > 256 {
+		return fmt.Errorf("%s exceeds 256 bits", field)
+	}
+
+	return nil
+}
+
+// decodeUint256 parses a big endian integer of at most 32 bytes. Zero is
+// returned in its canonical form
+func decodeUint256(field string, bytes []byte) (*big.Int, error) {
+	if len(bytes) > 32 {
+		return nil, fmt.Errorf("%s exceeds 256 bits", field)
+	}
+
+	num := new(big.Int).SetBytes(bytes)
+	if num.Sign() == 0 {
+		return big.NewInt(0), nil
+	}
+
+	return num, nil
+}
+
+// decodeUint parses a 32 byte big endian integer that must fit into `bits`
+// bits, such as a tx index or an output index
+func decodeUint(field string, bytes [32]byte, bits uint) (uint64, error) {
+	num := new(big.Int).SetBytes(bytes[:])
+	if uint(num.BitLen()) > bits {
+		return 0, fmt.Errorf("%s exceeds %d bits", field, bits)
+	}
+
+	return num.Uint64(), nil
+}
@@ -0,0 +1,187 @@
+package plasma
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+var (
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	overflow   = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// uint256 from random bytes. The length is drawn as well so that small values
+// and zero are generated as often as full width ones
+func genUint256(bytes [32]byte, length uint8) *big.Int {
+	return fromBytes32(bytesOfLength(bytes, int(length)%33))
+}
+
+func bytesOfLength(bytes [32]byte, length int) [32]byte {
+	var b [32]byte
+	copy(b[32-length:], bytes[32-length:])
+	return b
+}
+
+func TestUint256TransactionRoundTrip(t *testing.T) {
+	roundTrip := func(blkNum, nonce, amount0, amount1, fee [32]byte, lengths [5]uint8, txIndex uint16, oIndex uint8) bool {
+		pos := NewPosition(genUint256(blkNum, lengths[0]), txIndex, oIndex%MaxTxOutputs, nil)
+		if pos.BlockNum.Sign() == 0 {
+			pos = NewPosition(nil, 0, 0, genUint256(nonce, lengths[1]))
+		}
+		if pos.IsNilPosition() {
+			pos.DepositNonce = maxUint256
+		}
+
+		tx := &Transaction{
+			Inputs: []Input{NewInput(pos, [65]byte{1}, nil)},
+			Outputs: []Output{
+				NewOutput(common.HexToAddress("1"), genUint256(amount0, lengths[2])),
+				NewOutput(common.HexToAddress("2"), genUint256(amount1, lengths[3])),
+			},
+			Fee: genUint256(fee, lengths[4]),
+		}
+
+		bytes, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return false
+		}
+		recoveredTx := &Transaction{}
+		if err := rlp.DecodeBytes(bytes, recoveredTx); err != nil {
+			return false
+		}
+
+		return reflect.DeepEqual(tx, recoveredTx)
+	}
+
+	require.NoError(t, quick.Check(roundTrip, nil))
+}
+
+func TestUint256OutputRoundTrip(t *testing.T) {
+	roundTrip := func(amount [32]byte, length uint8) bool {
+		output := NewOutput(common.HexToAddress("1"), genUint256(amount, length))
+		bytes, err := rlp.EncodeToBytes(&output)
+		if err != nil {
+			return false
+		}
+		recoveredOutput := Output{}
+		if err := rlp.DecodeBytes(bytes, &recoveredOutput); err != nil {
+			return false
+		}
+
+		return reflect.DeepEqual(output, recoveredOutput)
+	}
+
+	require.NoError(t, quick.Check(roundTrip, nil))
+}
+
+func TestUint256DepositRoundTrip(t *testing.T) {
+	roundTrip := func(amount, ethBlockNum [32]byte, lengths [2]uint8) bool {
+		deposit := NewDeposit(common.HexToAddress("1"), genUint256(amount, lengths[0]), genUint256(ethBlockNum, lengths[1]))
+		bytes, err := rlp.EncodeToBytes(&deposit)
+		if err != nil {
+			return false
+		}
+		recoveredDeposit := Deposit{}
+		if err := rlp.DecodeBytes(bytes, &recoveredDeposit); err != nil {
+			return false
+		}
+
+		return reflect.DeepEqual(deposit, recoveredDeposit)
+	}
+
+	require.NoError(t, quick.Check(roundTrip, nil))
+}
+
+func TestUint256PositionRoundTrip(t *testing.T) {
+	roundTrip := func(blkNum, nonce [32]byte, lengths [2]uint8, txIndex uint16, oIndex uint8) bool {
+		pos := NewPosition(genUint256(blkNum, lengths[0]), txIndex, oIndex, genUint256(nonce, lengths[1]))
+		bytes, err := rlp.EncodeToBytes(&pos)
+		if err != nil {
+			return false
+		}
+		recoveredPos := Position{}
+		if err := rlp.DecodeBytes(bytes, &recoveredPos); err != nil {
+			return false
+		}
+
+		return pos.BlockNum.Cmp(recoveredPos.BlockNum) == 0 && pos.DepositNonce.Cmp(recoveredPos.DepositNonce) == 0 &&
+			pos.TxIndex == recoveredPos.TxIndex && pos.OutputIndex == recoveredPos.OutputIndex
+	}
+
+	require.NoError(t, quick.Check(roundTrip, nil))
+
+	// the string representation round trips as well
+	pos := NewPosition(maxUint256, 1, 0, nil)
+	recoveredPos, err := FromPositionString(pos.String())
+	require.NoError(t, err)
+	require.Equal(t, pos, recoveredPos)
+}
+
+func TestUint256Oversize(t *testing.T) {
+	negative := big.NewInt(-1)
+	for _, num := range []*big.Int{overflow, negative} {
+		tx := &Transaction{
+			Inputs:  []Input{NewInput(NewPosition(big.NewInt(1), 0, 0, nil), [65]byte{}, nil)},
+			Outputs: []Output{NewOutput(common.HexToAddress("1"), num)},
+			Fee:     big.NewInt(0),
+		}
+		_, err := rlp.EncodeToBytes(tx)
+		require.Errorf(t, err, "encoded a transaction with amount %s", num)
+		require.Error(t, tx.ValidateBasic(), "validated a transaction with amount %s", num)
+
+		tx.Outputs[0].Amount = big.NewInt(1)
+		tx.Fee = num
+		_, err = rlp.EncodeToBytes(tx)
+		require.Errorf(t, err, "encoded a transaction with fee %s", num)
+
+		output := NewOutput(common.HexToAddress("1"), num)
+		_, err = rlp.EncodeToBytes(&output)
+		require.Errorf(t, err, "encoded an output with amount %s", num)
+
+		deposit := NewDeposit(common.HexToAddress("1"), num, big.NewInt(1))
+		_, err = rlp.EncodeToBytes(&deposit)
+		require.Errorf(t, err, "encoded a deposit with amount %s", num)
+
+		pos := NewPosition(num, 0, 0, nil)
+		_, err = rlp.EncodeToBytes(&pos)
+		require.Errorf(t, err, "encoded a position with block number %s", num)
+		require.Error(t, pos.ValidateBasic(), "validated a position with block number %s", num)
+	}
+
+	// 33 byte integers are rejected when decoding
+	bytes, err := rlp.EncodeToBytes(&output{common.HexToAddress("1"), overflow.Bytes()})
+	require.NoError(t, err)
+	require.Error(t, rlp.DecodeBytes(bytes, &Output{}), "decoded an output with an amount exceeding 256 bits")
+
+	bytes, err = rlp.EncodeToBytes(&deposit{common.HexToAddress("1"), big.NewInt(1).Bytes(), overflow.Bytes()})
+	require.NoError(t, err)
+	require.Error(t, rlp.DecodeBytes(bytes, &Deposit{}), "decoded a deposit with an eth block number exceeding 256 bits")
+
+	pos := position{BlockNum: overflow, DepositNonce: big.NewInt(0)}
+	bytes, err = rlp.EncodeToBytes(&pos)
+	require.NoError(t, err)
+	require.Error(t, rlp.DecodeBytes(bytes, &Position{}), "decoded a position with a block number exceeding 256 bits")
+
+	// tx and output indices must fit into their types
+	tx := Transaction{
+		Inputs:  []Input{NewInput(NewPosition(big.NewInt(1), 0, 0, nil), [65]byte{}, nil)},
+		Outputs: []Output{NewOutput(common.HexToAddress("1"), big.NewInt(1))},
+		Fee:     big.NewInt(0),
+	}
+	txList := tx.toTxList()
+	txList.TxIndex0[29] = 1 // 1 << 16
+	bytes, err = rlp.EncodeToBytes(&rawTx{txList, [2][65]byte{}})
+	require.NoError(t, err)
+	require.Error(t, rlp.DecodeBytes(bytes, &Transaction{}), "decoded a tx index exceeding 16 bits")
+
+	txList = tx.toTxList()
+	txList.OIndex0[30] = 1 // 1 << 8
+	bytes, err = rlp.EncodeToBytes(&rawTx{txList, [2][65]byte{}})
+	require.NoError(t, err)
+	require.Error(t, rlp.DecodeBytes(bytes, &Transaction{}), "decoded an output index exceeding 8 bits")
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/FourthState/plasma-mvp-sidechain/utils"
@@ -25,22 +26,126 @@ func (ds DataStore) GetBlock(ctx sdk.Context, blockHeight *big.Int) (Block, bool
 	return block, true
 }
 
+// GetBlockAtTMHeight returns the plasma block committed at the given tendermint height.
+// Blocks imported from genesis were committed by an earlier chain and are not indexed
+func (ds DataStore) GetBlockAtTMHeight(ctx sdk.Context, tmBlockHeight uint64) (Block, bool) {
+	data := ds.Get(ctx, GetTMBlockKey(tmBlockHeight))
+	if data == nil {
+		return Block{}, false
+	}
+
+	return ds.GetBlock(ctx, new(big.Int).SetBytes(data))
+}
+
 // StoreBlock will store the plasma block and return the plasma block number
 // in which it was stored at.
 func (ds DataStore) StoreBlock(ctx sdk.Context, tmBlockHeight uint64, block plasma.Block) *big.Int {
 	blockHeight := ds.NextPlasmaBlockHeight(ctx)
 
-	blockKey := GetBlockKey(blockHeight)
-	blockData, err := rlp.EncodeToBytes(&Block{block, tmBlockHeight})
+	// store the block and updated the height counter
+	ds.setBlock(ctx, blockHeight, Block{block, tmBlockHeight})
+	ds.Set(ctx, GetTMBlockKey(tmBlockHeight), blockHeight.Bytes())
+	ds.Set(ctx, GetBlockHeightKey(), blockHeight.Bytes())
+
+	return blockHeight
+}
+
+// setBlock overwrites the block stored at the given height. The tendermint
+// height is not indexed.
+func (ds DataStore) setBlock(ctx sdk.Context, blockHeight *big.Int, block Block) {
+	blockData, err := rlp.EncodeToBytes(&block)
 	if err != nil {
 		panic(fmt.Sprintf("error rlp encoding block: %s", err))
 	}
 
-	// store the block and updated the height counter
-	ds.Set(ctx, blockKey, blockData)
-	ds.Set(ctx, GetBlockHeightKey(), blockHeight.Bytes())
+	ds.Set(ctx, GetBlockKey(blockHeight), blockData)
+}
 
-	return blockHeight
+// StoreBlockTree persists the merkle leaves of the transactions included in the
+// block at the given height. The leaves must be in the order the transactions
+// were included by tendermint so that the tree matches the block header.
+func (ds DataStore) StoreBlockTree(ctx sdk.Context, blockHeight *big.Int, txs [][]byte) {
+	leaves := make([][]byte, len(txs))
+	for i, tx := range txs {
+		leaves[i] = txLeaf(tx)
+	}
+
+	ds.setBlockTree(ctx, blockHeight, leaves)
+}
+
+// GetBlockTree returns the merkle leaves of the block at the specified height
+func (ds DataStore) GetBlockTree(ctx sdk.Context, blockHeight *big.Int) ([][]byte, bool) {
+	data := ds.Get(ctx, GetBlockTreeKey(blockHeight))
+	if data == nil {
+		return nil, false
+	}
+
+	var leaves [][]byte
+	if err := rlp.DecodeBytes(data, &leaves); err != nil {
+		panic(fmt.Sprintf("block tree store corrupted: %s", err))
+	}
+
+	return leaves, true
+}
+
+// GetTxProof returns the transaction at the given position along with the
+// proof of its inclusion in the plasma block. The rootchain contract checks
+// the proof against the tx index of the position and the number of
+// transactions of the block, so a block with failed transactions among its
+// leaves is not provable on the rootchain and ErrNotProvable is returned
+func (ds DataStore) GetTxProof(ctx sdk.Context, pos plasma.Position) (TxProof, sdk.Error) {
+	// deposits and fees are not included as transactions
+	if pos.IsDeposit() || pos.IsFee() {
+		return TxProof{}, ErrDNE("deposits and fees are not included in the merkle tree of a plasma block")
+	}
+
+	tx, ok := ds.GetTxWithPosition(ctx, pos)
+	if !ok {
+		return TxProof{}, ErrDNE("no transaction exists at position %s", pos)
+	}
+
+	block, ok := ds.GetBlock(ctx, pos.BlockNum)
+	if !ok {
+		return TxProof{}, ErrDNE("plasma block %s does not exist", pos.BlockNum)
+	}
+
+	leaves, ok := ds.GetBlockTree(ctx, pos.BlockNum)
+	if !ok {
+		return TxProof{}, ErrDNE("merkle tree of plasma block %s does not exist", pos.BlockNum)
+	}
+
+	// failed transactions are part of the tree as well. Locate the leaf by its hash
+	txBytes := tx.Transaction.TxBytes()
+	leaf := txLeaf(txBytes)
+	for i := range leaves {
+		if !bytes.Equal(leaves[i], leaf) {
+			continue
+		}
+
+		if i != int(pos.TxIndex) || len(leaves) != int(block.TxnCount) {
+			return TxProof{}, ErrNotProvable("block %s not provable on the rootchain. %d of its %d transactions failed",
+				pos.BlockNum, len(leaves)-int(block.TxnCount), len(leaves))
+		}
+
+		return TxProof{
+			Block:   block,
+			TxBytes: txBytes,
+			Index:   i,
+			Total:   len(leaves),
+			Proof:   merkleProof(leaves, i),
+		}, nil
+	}
+
+	return TxProof{}, ErrDNE("transaction at position %s is not part of the merkle tree of its block", pos)
+}
+
+func (ds DataStore) setBlockTree(ctx sdk.Context, blockHeight *big.Int, leaves [][]byte) {
+	data, err := rlp.EncodeToBytes(leaves)
+	if err != nil {
+		panic(fmt.Sprintf("error rlp encoding block tree: %s", err))
+	}
+
+	ds.Set(ctx, GetBlockTreeKey(blockHeight), data)
 }
 
 // PlasmaBlockHeight returns the current plasma block height. nil if no blocks exist
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
@@ -71,5 +72,84 @@ func TestPlasmaBlockStorage(t *testing.T) {
 		recoveredBlock, ok = store.GetBlock(ctx, blockNum)
 		require.True(t, ok, "error when retrieving block")
 		require.True(t, reflect.DeepEqual(block, recoveredBlock), fmt.Sprintf("mismatch in stored block and retrieved block, iteration %d", i))
+
+		// retrieve by tendermint height
+		recoveredBlock, ok = store.GetBlockAtTMHeight(ctx, uint64(i*1123))
+		require.True(t, ok, "error when retrieving block by tendermint height")
+		require.True(t, reflect.DeepEqual(block, recoveredBlock), fmt.Sprintf("mismatch in stored block and block retrieved by tendermint height, iteration %d", i))
+		_, ok = store.GetBlockAtTMHeight(ctx, uint64(i*1123+1))
+		require.False(t, ok, "retrieved a block at a tendermint height without a plasma block")
+	}
+}
+
+// test that the inclusion proof of a transaction can be retrieved
+func TestTxProof(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+
+	_, sdkErr := ds.GetTxProof(ctx, getPosition("(1.0.0.0)"))
+	require.NotNil(t, sdkErr, "retrieved a proof for a nonexistent block")
+
+	// stores a block of 3 transactions. A failed transaction, part of the
+	// tree but not of the plasma block, is delivered after the first if `failed`
+	storeBlock := func(blockNum int64, failed bool) ([][]byte, [][]byte, [32]byte) {
+		var sig [65]byte
+		sig[0] = byte(1)
+		var txs [][]byte
+		for i := int64(1); i <= 3; i++ {
+			tx := Transaction{
+				Transaction: plasma.Transaction{
+					Inputs:  []plasma.Input{plasma.NewInput(plasma.NewPosition(nil, 0, 0, big.NewInt(blockNum*10+i)), sig, nil)},
+					Outputs: []plasma.Output{plasma.NewOutput(common.HexToAddress("1"), big.NewInt(i))},
+					Fee:     utils.Big0,
+				},
+				Spent:      []bool{false},
+				SpenderTxs: [][]byte{[]byte{}},
+				Position:   plasma.NewPosition(big.NewInt(blockNum), uint16(i-1), 0, nil),
+			}
+			ds.StoreTx(ctx, tx)
+			ds.StoreOutputs(ctx, tx)
+			txs = append(txs, tx.Transaction.TxBytes())
+		}
+		if failed {
+			txs = append(txs[:1], append([][]byte{[]byte("failed transaction")}, txs[1:]...)...)
+		}
+
+		var leaves [][]byte
+		for _, tx := range txs {
+			leaves = append(leaves, txLeaf(tx))
+		}
+		var header [32]byte
+		copy(header[:], merkleRoot(leaves))
+		blockHeight := ds.StoreBlock(ctx, uint64(blockNum*10), plasma.NewBlock(header, 3, utils.Big0, big.NewInt(blockNum)))
+		ds.StoreBlockTree(ctx, blockHeight, txs)
+
+		return txs, leaves, header
+	}
+
+	txs, leaves, header := storeBlock(1, false)
+	recoveredLeaves, ok := ds.GetBlockTree(ctx, big.NewInt(1))
+	require.True(t, ok, "failed to retrieve the block tree")
+	require.Equal(t, leaves, recoveredLeaves, "mismatch in stored and retrieved leaves")
+
+	for i := range txs {
+		proof, sdkErr := ds.GetTxProof(ctx, plasma.NewPosition(big.NewInt(1), uint16(i), 0, nil))
+		require.Nil(t, sdkErr, "failed to retrieve the proof of transaction %d", i)
+		require.Equal(t, txs[i], proof.TxBytes, "mismatch in tx bytes of transaction %d", i)
+		require.Equal(t, i, proof.Index, "mismatch in leaf index of transaction %d", i)
+		require.Equal(t, len(txs), proof.Total)
+		require.Equal(t, header, proof.Block.Header)
+		require.Equal(t, merkleProof(leaves, i), proof.Proof)
+	}
+
+	// the contract checks the proof against the tx index and the number of transactions of the block
+	storeBlock(2, true)
+	for i := 0; i < 3; i++ {
+		_, sdkErr := ds.GetTxProof(ctx, plasma.NewPosition(big.NewInt(2), uint16(i), 0, nil))
+		require.NotNil(t, sdkErr, "retrieved a proof of transaction %d in a block with a failed transaction", i)
+		require.Equal(t, CodeNotProvable, sdkErr.Code(), "unexpected error for transaction %d", i)
 	}
+
+	_, sdkErr = ds.GetTxProof(ctx, getPosition("(0.0.0.1)"))
+	require.NotNil(t, sdkErr, "retrieved a proof for a deposit")
 }
@@ -11,19 +11,34 @@ const (
 	CodeDNE         sdk.CodeType = 1
 	CodeOutputSpent sdk.CodeType = 2
 	CodeInvalidPath sdk.CodeType = 3
+
+	CodeInvalidSignature sdk.CodeType = 4
+	CodeNotProvable      sdk.CodeType = 5
 )
 
 // ErrDNE error for an object that does not exist
 func ErrDNE(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeDNE, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeDNE, msg, args...)
 }
 
 // ErrOutputSpent error for an output that is marked as spent
 func ErrOutputSpent(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeOutputSpent, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeOutputSpent, msg, args...)
 }
 
 // ErrInvalidPath error for an invalid query path
 func ErrInvalidPath(msg string, args ...interface{}) sdk.Error {
-	return sdk.NewError(DefaultCodespace, CodeInvalidPath, msg, args)
+	return sdk.NewError(DefaultCodespace, CodeInvalidPath, msg, args...)
+}
+
+// ErrInvalidSignature error for a confirm signature posted to the mailbox that
+// was not created by the owner of the input
+func ErrInvalidSignature(msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeInvalidSignature, msg, args...)
+}
+
+// ErrNotProvable error for a transaction whose inclusion cannot be proven to
+// the rootchain contract
+func ErrNotProvable(msg string, args ...interface{}) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeNotProvable, msg, args...)
 }
@@ -0,0 +1,68 @@
+package store
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// FeeOutputs are the unspent fee outputs collected by an address. Fee outputs
+// are consolidated by sweep transactions, which spend fee outputs and the
+// output of the previous sweep into a single output owned by the address.
+// Sweep is the unspent output of the latest sweep, if any, and SweepInputs is
+// the number of inputs of the transaction that created it. Total includes the
+// amount of the sweep output
+type FeeOutputs struct {
+	Fees        []TxOutput
+	Sweep       *TxOutput
+	SweepInputs int
+	Total       *big.Int
+}
+
+// GetFeeOutputs returns the unspent fee outputs of the wallet at `addr` in the
+// order they were received along with the output of its latest sweep.
+func (ds DataStore) GetFeeOutputs(ctx sdk.Context, addr common.Address) FeeOutputs {
+	fees := FeeOutputs{Total: big.NewInt(0)}
+	for _, utxo := range ds.GetUnspentForWallet(ctx, addr) {
+		utxo := utxo
+		if utxo.Position.IsFee() {
+			fees.Fees = append(fees.Fees, utxo)
+			fees.Total.Add(fees.Total, utxo.Output.Amount)
+			continue
+		}
+		if utxo.Position.IsDeposit() {
+			continue
+		}
+
+		if inputs, ok := ds.sweepInputs(ctx, addr, utxo.TxHash); ok {
+			if fees.Sweep != nil {
+				fees.Total.Sub(fees.Total, fees.Sweep.Output.Amount)
+			}
+			fees.Sweep, fees.SweepInputs = &utxo, inputs
+			fees.Total.Add(fees.Total, utxo.Output.Amount)
+		}
+	}
+
+	return fees
+}
+
+// returns the number of inputs of the transaction with `txHash` if it is a
+// sweep. A sweep spends at least one fee output, along with other outputs of
+// the same owner, into a single output
+func (ds DataStore) sweepInputs(ctx sdk.Context, addr common.Address, txHash []byte) (int, bool) {
+	tx, ok := ds.GetTx(ctx, txHash)
+	if !ok || len(tx.Transaction.Outputs) != 1 {
+		return 0, false
+	}
+
+	hasFee := false
+	for _, input := range tx.Transaction.Inputs {
+		output, ok := ds.GetOutput(ctx, input.Position)
+		if !ok || output.Output.Owner != addr {
+			return 0, false
+		}
+		hasFee = hasFee || input.Position.IsFee()
+	}
+
+	return len(tx.Transaction.Inputs), hasFee
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"encoding/json"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"math/big"
+	"testing"
+)
+
+// Test that the unspent fee outputs and the latest sweep of an address are retrieved
+func TestFeeOutputs(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+
+	operator := common.BytesToAddress([]byte("operator"))
+	other := common.BytesToAddress([]byte("another address"))
+
+	// spends the inputs into the outputs at `pos`
+	var sig [65]byte
+	spend := func(pos string, inputs []string, outputs ...plasma.Output) {
+		tx := Transaction{
+			Transaction:      plasma.Transaction{Outputs: outputs, Fee: utils.Big0},
+			ConfirmationHash: []byte(pos),
+			Spent:            make([]bool, len(outputs)),
+			SpenderTxs:       make([][]byte, len(outputs)),
+			Position:         getPosition(pos),
+		}
+		for _, input := range inputs {
+			tx.Transaction.Inputs = append(tx.Transaction.Inputs, plasma.NewInput(getPosition(input), sig, nil))
+		}
+		for _, input := range tx.Transaction.Inputs {
+			var res bool
+			switch {
+			case input.Position.IsDeposit():
+				res = ds.SpendDeposit(ctx, input.Position.DepositNonce, tx.Transaction.TxHash()).IsOK()
+			case input.Position.IsFee():
+				res = ds.SpendFee(ctx, input.Position, tx.Transaction.TxHash()).IsOK()
+			default:
+				res = ds.SpendOutput(ctx, input.Position, tx.Transaction.TxHash()).IsOK()
+			}
+			require.Truef(t, res, "failed to spend %s", input.Position)
+		}
+		ds.StoreTx(ctx, tx)
+		ds.StoreOutputs(ctx, tx)
+	}
+
+	for i := int64(1); i <= 4; i++ {
+		ds.StoreFee(ctx, big.NewInt(i), plasma.NewOutput(operator, big.NewInt(i)))
+	}
+	ds.StoreDeposit(ctx, big.NewInt(1), plasma.NewDeposit(other, big.NewInt(100), big.NewInt(1)))
+
+	fees := ds.GetFeeOutputs(ctx, operator)
+	require.Len(t, fees.Fees, 4)
+	require.Nil(t, fees.Sweep)
+	require.Equal(t, big.NewInt(10), fees.Total)
+
+	// outputs received from others are not sweeps
+	spend("(5.0.0.0)", []string{"(0.0.0.1)"}, plasma.NewOutput(operator, big.NewInt(100)))
+	// neither are spends of fees into multiple outputs
+	spend("(5.1.0.0)", []string{"(4.65535.0.0)"}, plasma.NewOutput(operator, big.NewInt(2)), plasma.NewOutput(other, big.NewInt(2)))
+
+	fees = ds.GetFeeOutputs(ctx, operator)
+	require.Len(t, fees.Fees, 3)
+	require.Nil(t, fees.Sweep, "spend returned as a sweep")
+
+	// sweep two fees, then the sweep and the remaining fee
+	spend("(6.0.0.0)", []string{"(1.65535.0.0)", "(2.65535.0.0)"}, plasma.NewOutput(operator, big.NewInt(3)))
+	fees = ds.GetFeeOutputs(ctx, operator)
+	require.Len(t, fees.Fees, 1)
+	require.Equal(t, "(3.65535.0.0)", fees.Fees[0].Position.String())
+	require.NotNil(t, fees.Sweep)
+	require.Equal(t, "(6.0.0.0)", fees.Sweep.Position.String())
+	require.Equal(t, 2, fees.SweepInputs)
+	require.Equal(t, big.NewInt(6), fees.Total)
+
+	spend("(7.0.0.0)", []string{"(6.0.0.0)", "(3.65535.0.0)"}, plasma.NewOutput(operator, big.NewInt(6)))
+	fees = ds.GetFeeOutputs(ctx, operator)
+	require.Len(t, fees.Fees, 0)
+	require.Equal(t, "(7.0.0.0)", fees.Sweep.Position.String())
+	require.Equal(t, []byte("(7.0.0.0)"), fees.Sweep.ConfirmationHash)
+	require.Equal(t, big.NewInt(6), fees.Total)
+
+	// query
+	querier := NewQuerier(ds)
+	data, err := querier(ctx, []string{QueryFees, operator.Hex()}, abci.RequestQuery{})
+	require.NoError(t, err)
+	var queried FeeOutputs
+	require.NoError(t, json.Unmarshal(data, &queried))
+	require.Equal(t, fees.Sweep.Position, queried.Sweep.Position)
+	require.Equal(t, fees.Total, queried.Total)
+
+	_, err = querier(ctx, []string{QueryFees, common.BytesToAddress([]byte("no wallet")).Hex()}, abci.RequestQuery{})
+	require.Error(t, err, "queried the fees of an address without a wallet")
+}
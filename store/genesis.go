@@ -0,0 +1,436 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+	"sort"
+)
+
+// GenesisState holds every record in the data store. Numbers are encoded as
+// decimal strings and byte slices as 0x prefixed hex strings so that the
+// state can be written to and read from a genesis file without loss.
+type GenesisState struct {
+	Blocks   []GenesisBlock   `json:"blocks"`
+	Txs      []GenesisTx      `json:"txs"`
+	Deposits []GenesisDeposit `json:"deposits"`
+	Fees     []GenesisFee     `json:"fees"`
+	Wallets  []GenesisWallet  `json:"wallets"`
+}
+
+// GenesisBlock is the genesis representation of a Block.
+type GenesisBlock struct {
+	Height        string   `json:"height"`
+	Header        string   `json:"header"`
+	TxnCount      uint16   `json:"txn_count"`
+	FeeAmount     string   `json:"fee_amount"`
+	TMBlockHeight uint64   `json:"tm_block_height"`
+	TxLeaves      []string `json:"tx_leaves"`
+}
+
+// GenesisTx is the genesis representation of a Transaction. TxBytes is the
+// rlp encoded plasma transaction.
+type GenesisTx struct {
+	TxBytes          string   `json:"tx_bytes"`
+	Position         string   `json:"position"`
+	ConfirmationHash string   `json:"confirmation_hash"`
+	Spent            []bool   `json:"spent"`
+	SpenderTxs       []string `json:"spender_txs"`
+}
+
+// GenesisDeposit is the genesis representation of a Deposit.
+type GenesisDeposit struct {
+	Nonce       string `json:"nonce"`
+	Owner       string `json:"owner"`
+	Amount      string `json:"amount"`
+	EthBlockNum string `json:"eth_block_num"`
+	Spent       bool   `json:"spent"`
+	SpenderTx   string `json:"spender_tx"`
+}
+
+// GenesisFee is the genesis representation of a fee Output.
+type GenesisFee struct {
+	Position  string `json:"position"`
+	Owner     string `json:"owner"`
+	Amount    string `json:"amount"`
+	Spent     bool   `json:"spent"`
+	SpenderTx string `json:"spender_tx"`
+}
+
+// GenesisWallet is the genesis representation of a Wallet. Outputs are
+// listed in the order the wallet received them.
+type GenesisWallet struct {
+	Address string                `json:"address"`
+	Balance string                `json:"balance"`
+	Outputs []GenesisWalletOutput `json:"outputs"`
+}
+
+// GenesisWalletOutput is the genesis representation of a WalletOutput.
+type GenesisWalletOutput struct {
+	Position  string `json:"position"`
+	Amount    string `json:"amount"`
+	TxHash    string `json:"tx_hash"`
+	CreatedAt string `json:"created_at"`
+	Spent     bool   `json:"spent"`
+	SpenderTx string `json:"spender_tx"`
+	SpentAt   string `json:"spent_at"`
+}
+
+// ExportGenesis iterates over the data store and returns all blocks,
+// transactions, deposits, fees and wallets.
+func (ds DataStore) ExportGenesis(ctx sdk.Context) GenesisState {
+	state := GenesisState{
+		Blocks:   []GenesisBlock{},
+		Txs:      []GenesisTx{},
+		Deposits: []GenesisDeposit{},
+		Fees:     []GenesisFee{},
+		Wallets:  []GenesisWallet{},
+	}
+
+	var blocks []Block
+	ds.iterate(ctx, blockKey, func(key, value []byte) {
+		var block Block
+		if err := rlp.DecodeBytes(value, &block); err != nil {
+			panic(fmt.Sprintf("block store corrupted: %s", err))
+		}
+		blocks = append(blocks, block)
+	})
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Height.Cmp(blocks[j].Height) < 0 })
+	for _, block := range blocks {
+		var txLeaves []string
+		leaves, _ := ds.GetBlockTree(ctx, block.Height)
+		for _, leaf := range leaves {
+			txLeaves = append(txLeaves, encodeHex(leaf))
+		}
+		state.Blocks = append(state.Blocks, GenesisBlock{
+			Height:        block.Height.String(),
+			Header:        fmt.Sprintf("0x%x", block.Header),
+			TxnCount:      block.TxnCount,
+			FeeAmount:     block.FeeAmount.String(),
+			TMBlockHeight: block.TMBlockHeight,
+			TxLeaves:      txLeaves,
+		})
+	}
+
+	var txs []Transaction
+	ds.iterate(ctx, txKey, func(key, value []byte) {
+		var tx Transaction
+		if err := rlp.DecodeBytes(value, &tx); err != nil {
+			panic(fmt.Sprintf("transaction store corrupted: %s", err))
+		}
+		txs = append(txs, tx)
+	})
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Position.Priority().Cmp(txs[j].Position.Priority()) < 0 })
+	for _, tx := range txs {
+		var spenderTxs []string
+		for _, hash := range tx.SpenderTxs {
+			spenderTxs = append(spenderTxs, encodeHex(hash))
+		}
+		state.Txs = append(state.Txs, GenesisTx{
+			TxBytes:          encodeHex(tx.Transaction.TxBytes()),
+			Position:         tx.Position.String(),
+			ConfirmationHash: encodeHex(tx.ConfirmationHash),
+			Spent:            tx.Spent,
+			SpenderTxs:       spenderTxs,
+		})
+	}
+
+	type nonceDeposit struct {
+		nonce *big.Int
+		Deposit
+	}
+	var deposits []nonceDeposit
+	ds.iterate(ctx, depositKey, func(key, value []byte) {
+		var deposit Deposit
+		if err := rlp.DecodeBytes(value, &deposit); err != nil {
+			panic(fmt.Sprintf("deposit store corrupted: %s", err))
+		}
+		deposits = append(deposits, nonceDeposit{new(big.Int).SetBytes(key), deposit})
+	})
+	sort.Slice(deposits, func(i, j int) bool { return deposits[i].nonce.Cmp(deposits[j].nonce) < 0 })
+	for _, deposit := range deposits {
+		state.Deposits = append(state.Deposits, GenesisDeposit{
+			Nonce:       deposit.nonce.String(),
+			Owner:       deposit.Deposit.Deposit.Owner.Hex(),
+			Amount:      deposit.Deposit.Deposit.Amount.String(),
+			EthBlockNum: deposit.Deposit.Deposit.EthBlockNum.String(),
+			Spent:       deposit.Spent,
+			SpenderTx:   encodeHex(deposit.SpenderTx),
+		})
+	}
+
+	type positionFee struct {
+		pos plasma.Position
+		Output
+	}
+	var fees []positionFee
+	ds.iterate(ctx, feeKey, func(key, value []byte) {
+		var pos plasma.Position
+		if err := rlp.DecodeBytes(key, &pos); err != nil {
+			panic(fmt.Sprintf("fee store corrupted: %s", err))
+		}
+		var fee Output
+		if err := rlp.DecodeBytes(value, &fee); err != nil {
+			panic(fmt.Sprintf("output store corrupted: %s", err))
+		}
+		fees = append(fees, positionFee{pos, fee})
+	})
+	sort.Slice(fees, func(i, j int) bool { return fees[i].pos.BlockNum.Cmp(fees[j].pos.BlockNum) < 0 })
+	for _, fee := range fees {
+		state.Fees = append(state.Fees, GenesisFee{
+			Position:  fee.pos.String(),
+			Owner:     fee.Output.Output.Owner.Hex(),
+			Amount:    fee.Output.Output.Amount.String(),
+			Spent:     fee.Spent,
+			SpenderTx: encodeHex(fee.SpenderTx),
+		})
+	}
+
+	// wallets are already ordered by address
+	ds.iterate(ctx, walletKey, func(key, value []byte) {
+		var wallet Wallet
+		if err := rlp.DecodeBytes(value, &wallet); err != nil {
+			panic(fmt.Sprintf("wallet store corrupted: %s", err))
+		}
+
+		addr := common.BytesToAddress(key)
+		outputs := []GenesisWalletOutput{}
+		for seq := uint64(0); seq < wallet.NumOutputs; seq++ {
+			output, ok := ds.GetWalletOutput(ctx, addr, seq)
+			if !ok {
+				panic(fmt.Sprintf("wallet store corrupted: wallet 0x%x is missing output %d", addr, seq))
+			}
+			outputs = append(outputs, GenesisWalletOutput{
+				Position:  output.Position.String(),
+				Amount:    output.Amount.String(),
+				TxHash:    encodeHex(output.TxHash),
+				CreatedAt: output.CreatedAt.String(),
+				Spent:     output.Spent,
+				SpenderTx: encodeHex(output.SpenderTx),
+				SpentAt:   output.SpentAt.String(),
+			})
+		}
+
+		state.Wallets = append(state.Wallets, GenesisWallet{
+			Address: addr.Hex(),
+			Balance: wallet.Balance.String(),
+			Outputs: outputs,
+		})
+	})
+
+	return state
+}
+
+// InitGenesis loads the provided state into the data store. An error is
+// returned if any record is malformed or if a wallet does not reflect the
+// outputs it references.
+func (ds DataStore) InitGenesis(ctx sdk.Context, state GenesisState) error {
+	var height *big.Int
+	for i, b := range state.Blocks {
+		blockHeight, ok := new(big.Int).SetString(b.Height, 10)
+		if !ok || blockHeight.Sign() <= 0 {
+			return fmt.Errorf("block %d: invalid height %q", i, b.Height)
+		}
+		header := common.FromHex(b.Header)
+		if len(header) != 32 {
+			return fmt.Errorf("block %d: header must be 32 bytes", i)
+		}
+		feeAmount, ok := new(big.Int).SetString(b.FeeAmount, 10)
+		if !ok || feeAmount.Sign() < 0 {
+			return fmt.Errorf("block %d: invalid fee amount %q", i, b.FeeAmount)
+		}
+
+		// the tendermint height is kept for the record but not indexed, since it
+		// belongs to the exported chain and collides with the heights of this one
+		var h [32]byte
+		copy(h[:], header)
+		ds.setBlock(ctx, blockHeight, Block{plasma.NewBlock(h, b.TxnCount, feeAmount, blockHeight), b.TMBlockHeight})
+
+		// blocks created before the merkle trees were persisted do not have any leaves
+		if len(b.TxLeaves) > 0 {
+			var leaves [][]byte
+			for _, l := range b.TxLeaves {
+				leaf := common.FromHex(l)
+				if len(leaf) != 32 {
+					return fmt.Errorf("block %d: merkle leaves must be 32 bytes", i)
+				}
+				leaves = append(leaves, leaf)
+			}
+			if !bytes.Equal(merkleRoot(leaves), header) {
+				return fmt.Errorf("block %d: merkle leaves do not match the header", i)
+			}
+			ds.setBlockTree(ctx, blockHeight, leaves)
+		}
+		if height == nil || blockHeight.Cmp(height) > 0 {
+			height = blockHeight
+		}
+	}
+	if height != nil {
+		ds.Set(ctx, GetBlockHeightKey(), height.Bytes())
+	}
+
+	for i, t := range state.Txs {
+		var transaction plasma.Transaction
+		if err := rlp.DecodeBytes(common.FromHex(t.TxBytes), &transaction); err != nil {
+			return fmt.Errorf("tx %d: rlp: %s", i, err)
+		}
+		pos, err := plasma.FromPositionString(t.Position)
+		if err != nil {
+			return fmt.Errorf("tx %d: %s", i, err)
+		}
+		if len(t.Spent) != len(transaction.Outputs) || len(t.SpenderTxs) != len(transaction.Outputs) {
+			return fmt.Errorf("tx %d: spend information must be provided for each output", i)
+		}
+
+		spenderTxs := make([][]byte, len(t.SpenderTxs))
+		for j, hash := range t.SpenderTxs {
+			spenderTxs[j] = common.FromHex(hash)
+		}
+
+		tx := Transaction{
+			Transaction:      transaction,
+			ConfirmationHash: common.FromHex(t.ConfirmationHash),
+			Spent:            t.Spent,
+			SpenderTxs:       spenderTxs,
+			Position:         pos,
+		}
+		ds.setTx(ctx, tx)
+		for j := range transaction.Outputs {
+			ds.setOutput(ctx, plasma.NewPosition(pos.BlockNum, pos.TxIndex, uint8(j), big.NewInt(0)), transaction.TxHash())
+		}
+	}
+
+	for i, d := range state.Deposits {
+		nonce, ok := new(big.Int).SetString(d.Nonce, 10)
+		if !ok || nonce.Sign() <= 0 {
+			return fmt.Errorf("deposit %d: invalid nonce %q", i, d.Nonce)
+		}
+		owner, amount, err := parseOwnerAmount(d.Owner, d.Amount)
+		if err != nil {
+			return fmt.Errorf("deposit %d: %s", i, err)
+		}
+		ethBlockNum, ok := new(big.Int).SetString(d.EthBlockNum, 10)
+		if !ok {
+			return fmt.Errorf("deposit %d: invalid ethereum block number %q", i, d.EthBlockNum)
+		}
+
+		ds.setDeposit(ctx, nonce, Deposit{plasma.NewDeposit(owner, amount, ethBlockNum), d.Spent, common.FromHex(d.SpenderTx)})
+	}
+
+	for i, f := range state.Fees {
+		pos, err := plasma.FromPositionString(f.Position)
+		if err != nil {
+			return fmt.Errorf("fee %d: %s", i, err)
+		} else if !pos.IsFee() {
+			return fmt.Errorf("fee %d: %s is not a fee position", i, pos)
+		}
+		owner, amount, err := parseOwnerAmount(f.Owner, f.Amount)
+		if err != nil {
+			return fmt.Errorf("fee %d: %s", i, err)
+		}
+
+		ds.setFee(ctx, pos, Output{plasma.NewOutput(owner, amount), f.Spent, common.FromHex(f.SpenderTx)})
+	}
+
+	for i, w := range state.Wallets {
+		addr, balance, err := parseOwnerAmount(w.Address, w.Balance)
+		if err != nil {
+			return fmt.Errorf("wallet %d: %s", i, err)
+		}
+
+		// the balance must reflect the unspent outputs owned by the wallet
+		total := big.NewInt(0)
+		for j, o := range w.Outputs {
+			output, err := parseWalletOutput(o)
+			if err != nil {
+				return fmt.Errorf("wallet %d: output %d: %s", i, j, err)
+			}
+
+			stored, ok := ds.GetOutput(ctx, output.Position)
+			if !ok {
+				return fmt.Errorf("wallet %d: position %s does not exist", i, output.Position)
+			} else if !bytes.Equal(stored.Output.Owner[:], addr[:]) || stored.Output.Amount.Cmp(output.Amount) != 0 || stored.Spent != output.Spent {
+				return fmt.Errorf("wallet %d: position %s does not match the output of 0x%x", i, output.Position, addr)
+			}
+			if !output.Spent {
+				total = total.Add(total, output.Amount)
+			}
+
+			ds.setWalletOutput(ctx, addr, uint64(j), output)
+		}
+		if total.Cmp(balance) != 0 {
+			return fmt.Errorf("wallet %d: balance %s does not equal the sum of unspent outputs %s", i, balance, total)
+		}
+
+		ds.setWallet(ctx, addr, Wallet{balance, uint64(len(w.Outputs))})
+	}
+
+	return nil
+}
+
+/* Helpers */
+
+// iterate calls fn with every key value pair under the provided prefix.
+// The prefix is stripped from the key.
+func (ds DataStore) iterate(ctx sdk.Context, prefix []byte, fn func(key, value []byte)) {
+	iter := sdk.KVStorePrefixIterator(ds.KVStore(ctx), prefix)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		fn(iter.Key()[len(prefix):], iter.Value())
+	}
+}
+
+func encodeHex(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("0x%x", data)
+}
+
+func parseWalletOutput(o GenesisWalletOutput) (WalletOutput, error) {
+	pos, err := plasma.FromPositionString(o.Position)
+	if err != nil {
+		return WalletOutput{}, err
+	}
+	amount, ok := new(big.Int).SetString(o.Amount, 10)
+	if !ok || amount.Sign() < 0 {
+		return WalletOutput{}, fmt.Errorf("invalid amount %q", o.Amount)
+	}
+	createdAt, ok := new(big.Int).SetString(o.CreatedAt, 10)
+	if !ok || createdAt.Sign() < 0 {
+		return WalletOutput{}, fmt.Errorf("invalid block number %q", o.CreatedAt)
+	}
+	spentAt, ok := new(big.Int).SetString(o.SpentAt, 10)
+	if !ok || spentAt.Sign() < 0 {
+		return WalletOutput{}, fmt.Errorf("invalid block number %q", o.SpentAt)
+	}
+
+	return WalletOutput{
+		Position:  pos,
+		Amount:    amount,
+		TxHash:    common.FromHex(o.TxHash),
+		CreatedAt: createdAt,
+		Spent:     o.Spent,
+		SpenderTx: common.FromHex(o.SpenderTx),
+		SpentAt:   spentAt,
+	}, nil
+}
+
+func parseOwnerAmount(owner, amount string) (common.Address, *big.Int, error) {
+	if !common.IsHexAddress(owner) {
+		return utils.ZeroAddress, nil, fmt.Errorf("invalid address %q", owner)
+	}
+	amt, ok := new(big.Int).SetString(amount, 10)
+	if !ok || amt.Sign() < 0 {
+		return utils.ZeroAddress, nil, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	return common.HexToAddress(owner), amt, nil
+}
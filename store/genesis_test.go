@@ -0,0 +1,118 @@
+package store
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// Test that the exported state can be imported into an empty store
+func TestGenesisExportImport(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+
+	addr0 := common.BytesToAddress([]byte("an ethereum address"))
+	addr1 := common.BytesToAddress([]byte("another address"))
+
+	// block 1 includes two deposits
+	var header [32]byte
+	copy(header[:], crypto.Keccak256([]byte("header")))
+	ds.StoreBlock(ctx, 5, plasma.NewBlock(header, 2, big.NewInt(1), big.NewInt(1)))
+	ds.StoreDeposit(ctx, big.NewInt(1), plasma.NewDeposit(addr0, big.NewInt(100), big.NewInt(20)))
+	ds.StoreDeposit(ctx, big.NewInt(2), plasma.NewDeposit(addr0, big.NewInt(50), big.NewInt(21)))
+	ds.StoreFee(ctx, big.NewInt(1), plasma.NewOutput(addr1, big.NewInt(1)))
+
+	// spend the first deposit in block 2
+	var sig [65]byte
+	sig[0] = byte(1)
+	tx := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(getPosition("(0.0.0.1)"), sig, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(addr1, big.NewInt(90)), plasma.NewOutput(addr0, big.NewInt(9))},
+			Fee:     utils.Big1,
+		},
+		ConfirmationHash: crypto.Keccak256([]byte("confirmation hash")),
+		Spent:            []bool{false, false},
+		SpenderTxs:       [][]byte{[]byte{}, []byte{}},
+		Position:         getPosition("(2.0.0.0)"),
+	}
+	require.True(t, ds.SpendDeposit(ctx, big.NewInt(1), tx.Transaction.TxHash()).IsOK())
+	ds.StoreTx(ctx, tx)
+	ds.StoreOutputs(ctx, tx)
+	txBytes := [][]byte{tx.Transaction.TxBytes()}
+	var txHeader [32]byte
+	copy(txHeader[:], merkleRoot([][]byte{txLeaf(txBytes[0])}))
+	ds.StoreBlock(ctx, 6, plasma.NewBlock(txHeader, 1, big.NewInt(1), big.NewInt(2)))
+	ds.StoreBlockTree(ctx, big.NewInt(2), txBytes)
+
+	state := ds.ExportGenesis(ctx)
+	require.Len(t, state.Blocks, 2)
+	require.Len(t, state.Txs, 1)
+	require.Len(t, state.Deposits, 2)
+	require.Len(t, state.Fees, 1)
+	require.Len(t, state.Wallets, 2)
+
+	// the state should survive a round trip through the genesis file encoding
+	cdc := codec.New()
+	bz, err := cdc.MarshalJSON(state)
+	require.NoError(t, err)
+	var recoveredState GenesisState
+	require.NoError(t, cdc.UnmarshalJSON(bz, &recoveredState))
+
+	newCtx, newKey := setup()
+	newDS := NewDataStore(newKey)
+	require.NoError(t, newDS.InitGenesis(newCtx, recoveredState))
+
+	require.True(t, reflect.DeepEqual(state, newDS.ExportGenesis(newCtx)), "mismatch in exported and imported state")
+	require.Equal(t, ds.PlasmaBlockHeight(ctx), newDS.PlasmaBlockHeight(newCtx))
+
+	proof, sdkErr := newDS.GetTxProof(newCtx, getPosition("(2.0.0.0)"))
+	require.Nil(t, sdkErr, "block tree not imported")
+	require.Equal(t, txBytes[0], proof.TxBytes)
+
+	output, ok := newDS.GetOutput(newCtx, getPosition("(2.0.1.0)"))
+	require.True(t, ok, "output not imported")
+	require.Equal(t, big.NewInt(9), output.Output.Amount)
+
+	// secondary indexes are rebuilt
+	pos, ok := newDS.GetTxPosition(newCtx, tx.Transaction.TxHash())
+	require.True(t, ok, "transaction position not indexed")
+	require.Equal(t, "(2.0.0.0)", pos.String())
+	spender, ok := newDS.GetSpender(newCtx, getPosition("(0.0.0.1)"))
+	require.True(t, ok, "deposit spender not indexed")
+	require.Equal(t, tx.Transaction.TxHash(), spender)
+	_, ok = newDS.GetBlockAtTMHeight(newCtx, 6)
+	require.False(t, ok, "tendermint height of the exported chain indexed")
+
+	// a block committed by the new chain at a tendermint height of the exported chain
+	newDS.StoreBlock(newCtx, 6, plasma.NewBlock(header, 0, big.NewInt(0), big.NewInt(3)))
+	block, ok := newDS.GetBlockAtTMHeight(newCtx, 6)
+	require.True(t, ok, "tendermint height not indexed")
+	require.Equal(t, big.NewInt(3), block.Height)
+	block, ok = newDS.GetBlock(newCtx, big.NewInt(2))
+	require.True(t, ok)
+	require.Equal(t, uint64(6), block.TMBlockHeight, "tendermint height of the imported block not kept")
+
+	wallet, ok := newDS.GetWallet(newCtx, addr0)
+	require.True(t, ok, "wallet not imported")
+	require.Equal(t, big.NewInt(59), wallet.Balance)
+
+	// merkle leaves must match the block header
+	state.Blocks[1].TxLeaves = []string{encodeHex(crypto.Keccak256([]byte("leaf")))}
+	badCtx, badKey := setup()
+	err = NewDataStore(badKey).InitGenesis(badCtx, state)
+	require.Error(t, err, "imported merkle leaves that do not match the header")
+	state.Blocks[1].TxLeaves = recoveredState.Blocks[1].TxLeaves
+
+	// wallets must reflect the outputs they reference
+	state.Wallets[0].Balance = "1"
+	badCtx, badKey = setup()
+	err = NewDataStore(badKey).InitGenesis(badCtx, state)
+	require.Error(t, err, "imported a wallet with an incorrect balance")
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+	"strconv"
+)
+
+const (
+	// DefaultHistoryLimit is the number of outputs in a page of wallet history
+	// when no limit is requested
+	DefaultHistoryLimit = 50
+
+	// MaxHistoryLimit is the largest page of wallet history that can be requested
+	MaxHistoryLimit = 100
+
+	// MaxHistoryScan is the number of outputs scanned for a page of wallet
+	// history, whether or not they match the filters
+	MaxHistoryScan = 1000
+)
+
+// Directions of wallet history
+const (
+	HistoryReceived = "received" // outputs received by the wallet
+	HistorySent     = "sent"     // outputs spent by the wallet
+)
+
+// HistoryParams filters a page of the history of a wallet. The zero value
+// requests the most recent outputs received or spent by the wallet.
+//
+// The block range applies to the block an output was received in for
+// `HistoryReceived` and to the block it was spent in for `HistorySent`.
+// Without a direction, an output matches if either block is in range.
+type HistoryParams struct {
+	Cursor    string   // cursor returned with the previous page. Empty for the first page
+	Limit     int      // maximum number of outputs in the page. DefaultHistoryLimit if zero
+	Direction string   // HistoryReceived, HistorySent or empty for both
+	Spent     *bool    // only spent or unspent outputs if set
+	FromBlock *big.Int // inclusive lower bound on the plasma block if set
+	ToBlock   *big.Int // inclusive upper bound on the plasma block if set
+}
+
+// WalletHistory is a page of the history of a wallet, most recently
+// received outputs first. The page holds fewer outputs than the limit, or
+// none, if the scan stopped at MaxHistoryScan outputs before the history was
+// exhausted. The next page continues where the scan stopped.
+type WalletHistory struct {
+	Outputs    []WalletOutput
+	NextCursor string // cursor of the next page. Empty if there are no more outputs
+}
+
+// ValidateBasic checks the parameters for consistency
+func (params HistoryParams) ValidateBasic() error {
+	if params.Limit < 0 || params.Limit > MaxHistoryLimit {
+		return fmt.Errorf("limit must be between 1 and %d, or 0 for the default of %d", MaxHistoryLimit, DefaultHistoryLimit)
+	}
+	if params.Cursor != "" {
+		if _, err := strconv.ParseUint(params.Cursor, 10, 64); err != nil {
+			return fmt.Errorf("malformed cursor")
+		}
+	}
+
+	switch params.Direction {
+	case "", HistoryReceived, HistorySent:
+	default:
+		return fmt.Errorf("direction must be %s or %s", HistoryReceived, HistorySent)
+	}
+
+	if params.FromBlock != nil && params.FromBlock.Sign() < 0 || params.ToBlock != nil && params.ToBlock.Sign() < 0 {
+		return fmt.Errorf("block range cannot be negative")
+	} else if params.FromBlock != nil && params.ToBlock != nil && params.FromBlock.Cmp(params.ToBlock) > 0 {
+		return fmt.Errorf("block range must start before it ends")
+	}
+
+	return nil
+}
+
+// matches reports if the output passes the filters of the parameters
+func (params HistoryParams) matches(output WalletOutput) bool {
+	if params.Spent != nil && *params.Spent != output.Spent {
+		return false
+	}
+
+	received := params.inRange(output.CreatedAt)
+	sent := output.Spent && params.inRange(output.SpentAt)
+	switch params.Direction {
+	case HistoryReceived:
+		return received
+	case HistorySent:
+		return sent
+	default:
+		return received || sent
+	}
+}
+
+func (params HistoryParams) inRange(blockNum *big.Int) bool {
+	if params.FromBlock != nil && blockNum.Cmp(params.FromBlock) < 0 {
+		return false
+	}
+
+	return params.ToBlock == nil || blockNum.Cmp(params.ToBlock) <= 0
+}
+
+// GetWalletHistory returns a page of the outputs of the wallet at the given
+// address that match the parameters, scanning at most MaxHistoryScan outputs.
+// The parameters are expected to be valid
+func (ds DataStore) GetWalletHistory(ctx sdk.Context, addr common.Address, params HistoryParams) WalletHistory {
+	limit := params.Limit
+	if limit == 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	// outputs are iterated from the newest one, starting at the cursor
+	end := GetWalletOutputKey(addr, ^uint64(0))
+	if params.Cursor != "" {
+		seq, _ := strconv.ParseUint(params.Cursor, 10, 64)
+		end = GetWalletOutputKey(addr, seq)
+	}
+	// the end of the range is exclusive while the cursor is not
+	end = append(end, 0)
+
+	iter := ds.KVStore(ctx).ReverseIterator(walletOutputPrefix(addr), end)
+	defer iter.Close()
+
+	history := WalletHistory{Outputs: []WalletOutput{}}
+	for scanned := 0; iter.Valid(); iter.Next() {
+		seq := binary.BigEndian.Uint64(iter.Key()[len(iter.Key())-8:])
+		if len(history.Outputs) == limit || scanned == MaxHistoryScan {
+			history.NextCursor = strconv.FormatUint(seq, 10)
+			break
+		}
+		scanned++
+
+		var output WalletOutput
+		if err := rlp.DecodeBytes(iter.Value(), &output); err != nil {
+			panic(fmt.Sprintf("wallet store corrupted: %s", err))
+		}
+
+		if params.matches(output) {
+			history.Outputs = append(history.Outputs, output)
+		}
+	}
+
+	return history
+}
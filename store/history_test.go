@@ -0,0 +1,174 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"math/big"
+	"testing"
+)
+
+// Test that the history of a wallet is paginated and filtered
+func TestWalletHistory(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+
+	addr := common.BytesToAddress([]byte("an ethereum address"))
+	other := common.BytesToAddress([]byte("another address"))
+
+	// block 1 includes 5 deposits and a fee
+	for i := int64(1); i <= 5; i++ {
+		ds.StoreDeposit(ctx, big.NewInt(i), plasma.NewDeposit(addr, big.NewInt(i*10), big.NewInt(i)))
+	}
+	ds.StoreFee(ctx, big.NewInt(1), plasma.NewOutput(addr, utils.Big1))
+	ds.StoreBlock(ctx, 1, plasma.NewBlock([32]byte{}, 0, utils.Big1, big.NewInt(1)))
+
+	// block 2 spends the first two deposits to the other address
+	var sig [65]byte
+	tx := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(getPosition("(0.0.0.1)"), sig, nil), plasma.NewInput(getPosition("(0.0.0.2)"), sig, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(other, big.NewInt(25)), plasma.NewOutput(addr, big.NewInt(5))},
+			Fee:     utils.Big0,
+		},
+		ConfirmationHash: []byte("confirmation hash"),
+		Spent:            []bool{false, false},
+		SpenderTxs:       [][]byte{[]byte{}, []byte{}},
+		Position:         getPosition("(2.0.0.0)"),
+	}
+	require.True(t, ds.SpendDeposit(ctx, big.NewInt(1), tx.Transaction.TxHash()).IsOK())
+	require.True(t, ds.SpendDeposit(ctx, big.NewInt(2), tx.Transaction.TxHash()).IsOK())
+	ds.StoreTx(ctx, tx)
+	ds.StoreOutputs(ctx, tx)
+	ds.StoreBlock(ctx, 2, plasma.NewBlock([32]byte{}, 1, utils.Big0, big.NewInt(2)))
+
+	wallet, ok := ds.GetWallet(ctx, addr)
+	require.True(t, ok)
+	require.Equal(t, uint64(7), wallet.NumOutputs)
+	require.Equal(t, big.NewInt(126), wallet.Balance)
+
+	utxos := ds.GetUnspentForWallet(ctx, addr)
+	require.Len(t, utxos, 5, "spent deposits returned as unspent")
+	require.Equal(t, "(0.0.0.3)", utxos[0].Position.String())
+	require.Equal(t, tx.ConfirmationHash, utxos[4].ConfirmationHash, "confirmation hash not returned for a transaction output")
+
+	spent, unspent := true, false
+	type historyCase struct {
+		params    HistoryParams
+		positions []string
+	}
+	cases := []historyCase{
+		{HistoryParams{}, []string{"(2.0.1.0)", "(1.65535.0.0)", "(0.0.0.5)", "(0.0.0.4)", "(0.0.0.3)", "(0.0.0.2)", "(0.0.0.1)"}},
+		{HistoryParams{Spent: &spent}, []string{"(0.0.0.2)", "(0.0.0.1)"}},
+		{HistoryParams{Spent: &unspent, Limit: 2}, []string{"(2.0.1.0)", "(1.65535.0.0)"}},
+		{HistoryParams{Direction: HistorySent}, []string{"(0.0.0.2)", "(0.0.0.1)"}},
+		{HistoryParams{Direction: HistoryReceived, FromBlock: big.NewInt(2)}, []string{"(2.0.1.0)"}},
+		{HistoryParams{FromBlock: big.NewInt(2)}, []string{"(2.0.1.0)", "(0.0.0.2)", "(0.0.0.1)"}},
+		{HistoryParams{ToBlock: big.NewInt(1), Direction: HistoryReceived, Limit: 3}, []string{"(1.65535.0.0)", "(0.0.0.5)", "(0.0.0.4)"}},
+	}
+	for i, c := range cases {
+		history := ds.GetWalletHistory(ctx, addr, c.params)
+		require.Equal(t, c.positions, historyPositions(history), fmt.Sprintf("case %d: unexpected history", i))
+	}
+
+	// page through the history two outputs at a time
+	var pages [][]string
+	params := HistoryParams{Limit: 2}
+	for {
+		history := ds.GetWalletHistory(ctx, addr, params)
+		pages = append(pages, historyPositions(history))
+		if history.NextCursor == "" {
+			break
+		}
+		params.Cursor = history.NextCursor
+	}
+	require.Equal(t, [][]string{{"(2.0.1.0)", "(1.65535.0.0)"}, {"(0.0.0.5)", "(0.0.0.4)"}, {"(0.0.0.3)", "(0.0.0.2)"}, {"(0.0.0.1)"}}, pages)
+
+	// spends record the spender and the block they were included in
+	history := ds.GetWalletHistory(ctx, addr, HistoryParams{Direction: HistorySent, Limit: 1})
+	require.Equal(t, tx.Transaction.TxHash(), history.Outputs[0].SpenderTx)
+	require.Equal(t, big.NewInt(2), history.Outputs[0].SpentAt)
+	require.Equal(t, big.NewInt(1), history.Outputs[0].CreatedAt)
+
+	// the other address only received a single output
+	history = ds.GetWalletHistory(ctx, other, HistoryParams{})
+	require.Equal(t, []string{"(2.0.0.0)"}, historyPositions(history))
+	require.Equal(t, tx.Transaction.TxHash(), history.Outputs[0].TxHash)
+}
+
+// Test that a page stops scanning at MaxHistoryScan outputs and returns where it stopped
+func TestWalletHistoryScanLimit(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+	addr := common.BytesToAddress([]byte("an ethereum address"))
+
+	// a single output received in block 1 followed by outputs received in block 2
+	ds.StoreDeposit(ctx, big.NewInt(1), plasma.NewDeposit(addr, utils.Big1, utils.Big1))
+	ds.StoreBlock(ctx, 1, plasma.NewBlock([32]byte{}, 0, utils.Big0, big.NewInt(1)))
+	for i := int64(2); i <= MaxHistoryScan+1; i++ {
+		ds.StoreDeposit(ctx, big.NewInt(i), plasma.NewDeposit(addr, utils.Big1, utils.Big1))
+	}
+
+	params := HistoryParams{ToBlock: big.NewInt(1)}
+	history := ds.GetWalletHistory(ctx, addr, params)
+	require.Empty(t, history.Outputs, "outputs outside of the block range returned")
+	require.Equal(t, "0", history.NextCursor, "cursor not returned where the scan stopped")
+
+	params.Cursor = history.NextCursor
+	history = ds.GetWalletHistory(ctx, addr, params)
+	require.Equal(t, []string{"(0.0.0.1)"}, historyPositions(history))
+	require.Empty(t, history.NextCursor)
+}
+
+// Test the validation of history parameters and the history querier route
+func TestQueryHistory(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+	querier := NewQuerier(ds)
+
+	addr := common.BytesToAddress([]byte("an ethereum address"))
+	ds.StoreDeposit(ctx, big.NewInt(1), plasma.NewDeposit(addr, big.NewInt(10), big.NewInt(1)))
+
+	invalid := []HistoryParams{
+		{Limit: MaxHistoryLimit + 1},
+		{Limit: -1},
+		{Cursor: "abc"},
+		{Direction: "both"},
+		{FromBlock: big.NewInt(-1)},
+		{FromBlock: big.NewInt(5), ToBlock: big.NewInt(4)},
+	}
+	for i, params := range invalid {
+		require.Error(t, params.ValidateBasic(), fmt.Sprintf("case %d: accepted invalid parameters", i))
+
+		data, err := json.Marshal(params)
+		require.NoError(t, err)
+		_, sdkErr := querier(ctx, []string{QueryHistory, addr.Hex()}, abci.RequestQuery{Data: data})
+		require.NotNil(t, sdkErr, fmt.Sprintf("case %d: queried with invalid parameters", i))
+		require.Equal(t, CodeInvalidPath, sdkErr.Code())
+	}
+
+	// no parameters return the first page
+	res, sdkErr := querier(ctx, []string{QueryHistory, addr.Hex()}, abci.RequestQuery{})
+	require.Nil(t, sdkErr)
+	var history WalletHistory
+	require.NoError(t, json.Unmarshal(res, &history))
+	require.Equal(t, []string{"(0.0.0.1)"}, historyPositions(history))
+	require.Empty(t, history.NextCursor)
+
+	_, sdkErr = querier(ctx, []string{QueryHistory, common.BytesToAddress([]byte("no wallet")).Hex()}, abci.RequestQuery{})
+	require.NotNil(t, sdkErr)
+	require.Equal(t, CodeDNE, sdkErr.Code())
+}
+
+func historyPositions(history WalletHistory) []string {
+	positions := []string{}
+	for _, output := range history.Outputs {
+		positions = append(positions, output.Position.String())
+	}
+
+	return positions
+}
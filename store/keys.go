@@ -1,6 +1,7 @@
 package store
 
 import (
+	"encoding/binary"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
@@ -15,6 +16,26 @@ var (
 	outputKey      = []byte{0x4}
 	blockKey       = []byte{0x5}
 	blockHeightKey = []byte{0x6}
+	validatorKey   = []byte{0x7}
+	blockTreeKey   = []byte{0x8}
+
+	// wallet history
+	walletOutputKey   = []byte{0x9}
+	walletPositionKey = []byte{0xa}
+	walletUnspentKey  = []byte{0xb}
+
+	// secondary indexes
+	txPositionKey = []byte{0xc}
+	tmBlockKey    = []byte{0xd}
+	spenderKey    = []byte{0xe}
+
+	paramsKey          = []byte{0xf}
+	feeAddressNonceKey = []byte{0x10}
+
+	// validator set
+	validatorPowerKey       = []byte{0x11}
+	validatorUpdateNonceKey = []byte{0x12}
+	operatorKey             = []byte{0x13}
 )
 
 // GetWalletKey returns the key to retrieve wallet for given address.
@@ -22,6 +43,24 @@ func GetWalletKey(addr common.Address) []byte {
 	return prefixKey(walletKey, addr.Bytes())
 }
 
+// GetWalletOutputKey returns the key to retrieve the output received by the
+// wallet at `addr` with sequence `seq`. Keys are ordered by sequence.
+func GetWalletOutputKey(addr common.Address, seq uint64) []byte {
+	return prefixKey(walletOutputPrefix(addr), sequenceBytes(seq))
+}
+
+// GetWalletPositionKey returns the key to retrieve the sequence of the output
+// at `pos` within the wallet at `addr`.
+func GetWalletPositionKey(addr common.Address, pos plasma.Position) []byte {
+	return prefixKey(prefixKey(walletPositionKey, addr.Bytes()), pos.Bytes())
+}
+
+// GetWalletUnspentKey returns the key marking the output with sequence `seq`
+// as unspent within the wallet at `addr`.
+func GetWalletUnspentKey(addr common.Address, seq uint64) []byte {
+	return prefixKey(walletUnspentPrefix(addr), sequenceBytes(seq))
+}
+
 // GetDepositKey returns the key to retrieve deposit for given nonce.
 func GetDepositKey(nonce *big.Int) []byte {
 	return prefixKey(depositKey, nonce.Bytes())
@@ -42,16 +81,82 @@ func GetTxKey(hash []byte) []byte {
 	return prefixKey(txKey, hash)
 }
 
+// GetTxPositionKey returns the key to retrieve the position of the transaction with the given hash.
+func GetTxPositionKey(hash []byte) []byte {
+	return prefixKey(txPositionKey, hash)
+}
+
+// GetSpenderKey returns the key to retrieve the hash of the transaction spending the given position.
+func GetSpenderKey(pos plasma.Position) []byte {
+	return prefixKey(spenderKey, pos.Bytes())
+}
+
+// GetTMBlockKey returns the key to retrieve the plasma block committed at the given tendermint height
+func GetTMBlockKey(tmBlockHeight uint64) []byte {
+	return prefixKey(tmBlockKey, sequenceBytes(tmBlockHeight))
+}
+
 // GetBlockKey returns the key for the specified height
 func GetBlockKey(height *big.Int) []byte {
 	return prefixKey(blockKey, height.Bytes())
 }
 
+// GetBlockTreeKey returns the key for the merkle tree of the block at the specified height
+func GetBlockTreeKey(height *big.Int) []byte {
+	return prefixKey(blockTreeKey, height.Bytes())
+}
+
 // GetBlockHeightKey returns the key for the height counter
 func GetBlockHeightKey() []byte {
 	return blockHeightKey
 }
 
+// GetParamsKey returns the key for the parameters of the chain
+func GetParamsKey() []byte {
+	return paramsKey
+}
+
+// GetValidatorKey returns the key for the validator of the chain
+func GetValidatorKey() []byte {
+	return validatorKey
+}
+
+// GetFeeAddressNonceKey returns the key for the number of fee address updates
+func GetFeeAddressNonceKey() []byte {
+	return feeAddressNonceKey
+}
+
+// GetValidatorPowerKey returns the key for the voting power of the validator
+// with the amino encoded consensus public key `consPubKey`
+func GetValidatorPowerKey(consPubKey []byte) []byte {
+	return prefixKey(validatorPowerKey, consPubKey)
+}
+
+// GetValidatorUpdateNonceKey returns the key for the number of validator updates
+func GetValidatorUpdateNonceKey() []byte {
+	return validatorUpdateNonceKey
+}
+
+// GetOperatorKey returns the key for the address authorized to update the validator set
+func GetOperatorKey() []byte {
+	return operatorKey
+}
+
+func walletOutputPrefix(addr common.Address) []byte {
+	return prefixKey(walletOutputKey, addr.Bytes())
+}
+
+func walletUnspentPrefix(addr common.Address) []byte {
+	return prefixKey(walletUnspentKey, addr.Bytes())
+}
+
+// sequenceBytes encodes `seq` in big endian so that keys sort by sequence
+func sequenceBytes(seq uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, seq)
+	return b
+}
+
 func prefixKey(prefix, key []byte) []byte {
 	return append(prefix, key...)
 }
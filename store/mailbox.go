@@ -0,0 +1,181 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const (
+	// MailboxQuerierRouteName to mount the mailbox querier
+	MailboxQuerierRouteName = "mailbox"
+
+	// QueryConfirmSigs retrieves the confirm signatures posted
+	// for the transaction that created the given position
+	QueryConfirmSigs = "confirmsigs"
+
+	// QueryPostConfirmSigs verifies and stores the confirm
+	// signatures in the request data for the transaction that
+	// created the given position
+	QueryPostConfirmSigs = "post"
+)
+
+var emptySig [65]byte
+
+// ConfirmSigs are the confirm signatures posted for the transaction that
+// created an output. Signatures are ordered by the inputs of the transaction
+// and are empty if they have not been posted
+type ConfirmSigs struct {
+	Position   plasma.Position
+	TxHash     []byte
+	Signatures [][]byte
+	Complete   bool // every input has posted its confirm signature
+}
+
+// Mailbox holds the confirm signatures senders post for the recipients of a
+// transaction. Posted signatures are node local and are not part of consensus.
+// Signatures are stored by transaction hash so that every output of the
+// transaction shares them
+type Mailbox struct {
+	db dbm.DB
+}
+
+// NewMailbox returns a mailbox backed by the database
+func NewMailbox(db dbm.DB) Mailbox {
+	return Mailbox{db}
+}
+
+// GetConfirmSigs returns the confirm signatures posted for the transaction
+// that created the output at the position
+func (mb Mailbox) GetConfirmSigs(ctx sdk.Context, ds DataStore, pos plasma.Position) (ConfirmSigs, sdk.Error) {
+	tx, err := mailboxTx(ctx, ds, pos)
+	if err != nil {
+		return ConfirmSigs{}, err
+	}
+
+	return mb.confirmSigs(pos, tx), nil
+}
+
+// PostConfirmSigs stores the confirm signatures for the transaction that
+// created the output at the position. Signatures are ordered by the inputs of
+// the transaction and empty signatures are skipped. Every other signature must
+// be created by the owner of the input over the confirmation hash
+func (mb Mailbox) PostConfirmSigs(ctx sdk.Context, ds DataStore, pos plasma.Position, sigs [][65]byte) (ConfirmSigs, sdk.Error) {
+	tx, err := mailboxTx(ctx, ds, pos)
+	if err != nil {
+		return ConfirmSigs{}, err
+	}
+
+	inputs := tx.Transaction.Inputs
+	if len(sigs) == 0 || len(sigs) > len(inputs) {
+		return ConfirmSigs{}, ErrInvalidSignature("between 1 and %d confirm signatures must be posted", len(inputs))
+	}
+
+	stored := mb.confirmSigs(pos, tx)
+	hash := utils.ToEthSignedMessageHash(tx.ConfirmationHash)
+	for i, sig := range sigs {
+		if sig == emptySig {
+			continue
+		}
+
+		owner, ok := ds.GetOutput(ctx, inputs[i].Position)
+		if !ok {
+			panic(fmt.Sprintf("Corrupted store: input position for given transaction does not exist: %s", inputs[i].Position))
+		}
+
+		pubKey, err := crypto.SigToPub(hash, sig[:])
+		if err != nil || crypto.PubkeyToAddress(*pubKey) != owner.Output.Owner {
+			return ConfirmSigs{}, ErrInvalidSignature("confirm signature for input %d is not signed by 0x%x", i, owner.Output.Owner)
+		}
+		stored.Signatures[i] = append([]byte{}, sig[:]...)
+	}
+
+	var value []byte
+	for _, sig := range stored.Signatures {
+		if len(sig) == 0 {
+			sig = emptySig[:]
+		}
+		value = append(value, sig...)
+	}
+	mb.db.SetSync(tx.Transaction.TxHash(), value)
+
+	return mb.confirmSigs(pos, tx), nil
+}
+
+func (mb Mailbox) confirmSigs(pos plasma.Position, tx Transaction) ConfirmSigs {
+	txHash := tx.Transaction.TxHash()
+	value := mb.db.Get(txHash)
+
+	complete := true
+	sigs := make([][]byte, len(tx.Transaction.Inputs))
+	for i := range sigs {
+		if len(value) >= (i+1)*65 && !bytes.Equal(value[i*65:(i+1)*65], emptySig[:]) {
+			sigs[i] = value[i*65 : (i+1)*65]
+		} else {
+			complete = false
+		}
+	}
+
+	return ConfirmSigs{
+		Position:   pos,
+		TxHash:     txHash,
+		Signatures: sigs,
+		Complete:   complete,
+	}
+}
+
+// only outputs of transactions have confirm signatures
+func mailboxTx(ctx sdk.Context, ds DataStore, pos plasma.Position) (Transaction, sdk.Error) {
+	if pos.IsDeposit() || pos.IsFee() {
+		return Transaction{}, ErrDNE("deposits and fees do not have confirm signatures")
+	}
+
+	tx, ok := ds.GetTxWithPosition(ctx, pos)
+	if !ok {
+		return Transaction{}, ErrDNE("no transaction exists for the position provided: %s", pos)
+	}
+	return tx, nil
+}
+
+// NewMailboxQuerier returns an SDK querier to post and retrieve confirm
+// signatures from the mailbox
+func NewMailboxQuerier(ds DataStore, mb Mailbox) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
+		if len(path) != 2 {
+			return nil, ErrInvalidPath("expected %s/<position> or %s/<position>", QueryConfirmSigs, QueryPostConfirmSigs)
+		}
+
+		pos, err := plasma.FromPositionString(path[1])
+		if err != nil {
+			return nil, ErrInvalidPath("position is encoded in the format (blocknum,txIndex,oIndex,depositNonce)")
+		}
+
+		var sigs ConfirmSigs
+		var sdkErr sdk.Error
+		switch path[0] {
+		case QueryConfirmSigs:
+			sigs, sdkErr = mb.GetConfirmSigs(ctx, ds, pos)
+		case QueryPostConfirmSigs:
+			if len(req.Data) == 0 || len(req.Data)%65 != 0 {
+				return nil, ErrInvalidSignature("confirm signatures must be 65 bytes in length")
+			}
+			posted := make([][65]byte, len(req.Data)/65)
+			for i := range posted {
+				copy(posted[i][:], req.Data[i*65:])
+			}
+			sigs, sdkErr = mb.PostConfirmSigs(ctx, ds, pos, posted)
+		default:
+			return nil, ErrInvalidPath("unregistered query path")
+		}
+		if sdkErr != nil {
+			return nil, sdkErr
+		}
+
+		return marshalResponse(sigs)
+	}
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"math/big"
+	"testing"
+)
+
+func confirmSig(t *testing.T, hash []byte, key *ecdsa.PrivateKey) [65]byte {
+	sig, err := crypto.Sign(utils.ToEthSignedMessageHash(hash), key)
+	require.NoError(t, err)
+	var confirmSig [65]byte
+	copy(confirmSig[:], sig)
+	return confirmSig
+}
+
+func TestMailbox(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+	mb := NewMailbox(dbm.NewMemDB())
+
+	key0, _ := crypto.GenerateKey()
+	key1, _ := crypto.GenerateKey()
+	recipient, _ := crypto.GenerateKey()
+
+	// outputs owned by key0 and key1 spent into a single transaction
+	var sig [65]byte
+	funding := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs: []plasma.Input{plasma.NewInput(getPosition("(0.0.0.1)"), sig, nil)},
+			Outputs: []plasma.Output{
+				plasma.NewOutput(crypto.PubkeyToAddress(key0.PublicKey), big.NewInt(10)),
+				plasma.NewOutput(crypto.PubkeyToAddress(key1.PublicKey), big.NewInt(10)),
+			},
+			Fee: utils.Big0,
+		},
+		ConfirmationHash: []byte("funding confirmation hash"),
+		Spent:            []bool{false, false},
+		SpenderTxs:       [][]byte{[]byte{}, []byte{}},
+		Position:         getPosition("(1.0.0.0)"),
+	}
+	ds.StoreTx(ctx, funding)
+	ds.StoreOutputs(ctx, funding)
+
+	tx := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs: []plasma.Input{
+				plasma.NewInput(getPosition("(1.0.0.0)"), sig, nil),
+				plasma.NewInput(getPosition("(1.0.1.0)"), sig, nil),
+			},
+			Outputs: []plasma.Output{plasma.NewOutput(crypto.PubkeyToAddress(recipient.PublicKey), big.NewInt(20))},
+			Fee:     utils.Big0,
+		},
+		ConfirmationHash: crypto.Keccak256([]byte("confirmation hash")),
+		Spent:            []bool{false},
+		SpenderTxs:       [][]byte{[]byte{}},
+		Position:         getPosition("(2.0.0.0)"),
+	}
+	ds.StoreTx(ctx, tx)
+	ds.StoreOutputs(ctx, tx)
+	pos := tx.Position
+
+	sigs, err := mb.GetConfirmSigs(ctx, ds, pos)
+	require.Nil(t, err)
+	require.Equal(t, tx.Transaction.TxHash(), sigs.TxHash)
+	require.Equal(t, [][]byte{nil, nil}, sigs.Signatures, "signatures posted before any were")
+	require.False(t, sigs.Complete)
+
+	// signatures not created by the input owner are rejected
+	_, err = mb.PostConfirmSigs(ctx, ds, pos, [][65]byte{confirmSig(t, tx.ConfirmationHash, key1)})
+	require.NotNil(t, err, "accepted a confirm signature of the wrong owner")
+	require.Equal(t, CodeInvalidSignature, err.Code())
+	_, err = mb.PostConfirmSigs(ctx, ds, pos, [][65]byte{confirmSig(t, []byte("wrong hash"), key0)})
+	require.NotNil(t, err, "accepted a confirm signature over the wrong hash")
+	require.Equal(t, CodeInvalidSignature, err.Code())
+	_, err = mb.PostConfirmSigs(ctx, ds, pos, make([][65]byte, 3))
+	require.NotNil(t, err, "accepted more signatures than inputs")
+
+	// each owner posts their signature separately
+	sig0 := confirmSig(t, tx.ConfirmationHash, key0)
+	sigs, err = mb.PostConfirmSigs(ctx, ds, pos, [][65]byte{sig0})
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{sig0[:], nil}, sigs.Signatures)
+	require.False(t, sigs.Complete)
+
+	sig1 := confirmSig(t, tx.ConfirmationHash, key1)
+	sigs, err = mb.PostConfirmSigs(ctx, ds, pos, [][65]byte{[65]byte{}, sig1})
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{sig0[:], sig1[:]}, sigs.Signatures, "posted signatures were not merged")
+	require.True(t, sigs.Complete)
+
+	// deposits and fees have no confirm signatures
+	for _, p := range []string{"(0.0.0.1)", "(1.65535.0.0)", "(3.0.0.0)"} {
+		_, err = mb.GetConfirmSigs(ctx, ds, getPosition(p))
+		require.NotNil(t, err, fmt.Sprintf("retrieved confirm signatures for %s", p))
+		require.Equal(t, CodeDNE, err.Code())
+	}
+}
+
+func TestMailboxQuerier(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+	querier := NewMailboxQuerier(ds, NewMailbox(dbm.NewMemDB()))
+
+	owner, _ := crypto.GenerateKey()
+	var sig [65]byte
+	funding := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(getPosition("(0.0.0.1)"), sig, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(crypto.PubkeyToAddress(owner.PublicKey), big.NewInt(10))},
+			Fee:     utils.Big0,
+		},
+		ConfirmationHash: []byte("funding confirmation hash"),
+		Spent:            []bool{false},
+		SpenderTxs:       [][]byte{[]byte{}},
+		Position:         getPosition("(1.0.0.0)"),
+	}
+	ds.StoreTx(ctx, funding)
+	ds.StoreOutputs(ctx, funding)
+
+	tx := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(getPosition("(1.0.0.0)"), sig, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(crypto.PubkeyToAddress(owner.PublicKey), big.NewInt(10))},
+			Fee:     utils.Big0,
+		},
+		ConfirmationHash: crypto.Keccak256([]byte("confirmation hash")),
+		Spent:            []bool{false},
+		SpenderTxs:       [][]byte{[]byte{}},
+		Position:         getPosition("(2.0.0.0)"),
+	}
+	ds.StoreTx(ctx, tx)
+	ds.StoreOutputs(ctx, tx)
+
+	posted := confirmSig(t, tx.ConfirmationHash, owner)
+	res, err := querier(ctx, []string{QueryPostConfirmSigs, "(2.0.0.0)"}, abci.RequestQuery{Data: posted[:]})
+	require.Nil(t, err)
+
+	res, err = querier(ctx, []string{QueryConfirmSigs, "(2.0.0.0)"}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var sigs ConfirmSigs
+	require.NoError(t, json.Unmarshal(res, &sigs))
+	require.Equal(t, [][]byte{posted[:]}, sigs.Signatures)
+	require.True(t, sigs.Complete)
+
+	type errorCase struct {
+		path []string
+		data []byte
+		code uint32
+	}
+	cases := []errorCase{
+		{[]string{QueryConfirmSigs}, nil, uint32(CodeInvalidPath)},
+		{[]string{QueryConfirmSigs, "position"}, nil, uint32(CodeInvalidPath)},
+		{[]string{"unknown", "(2.0.0.0)"}, nil, uint32(CodeInvalidPath)},
+		{[]string{QueryConfirmSigs, "(3.0.0.0)"}, nil, uint32(CodeDNE)},
+		{[]string{QueryPostConfirmSigs, "(2.0.0.0)"}, nil, uint32(CodeInvalidSignature)},
+		{[]string{QueryPostConfirmSigs, "(2.0.0.0)"}, posted[:64], uint32(CodeInvalidSignature)},
+	}
+	for i, c := range cases {
+		_, err := querier(ctx, c.path, abci.RequestQuery{Data: c.data})
+		require.NotNil(t, err, fmt.Sprintf("case %d: query did not fail", i))
+		require.Equal(t, c.code, uint32(err.Code()), fmt.Sprintf("case %d: unexpected error code", i))
+	}
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// The functions below reproduce the simple merkle tree tendermint commits to
+// in the `DataHash` of a block header. Leaves are the sha256 hashes of the
+// transaction bytes and inner nodes hash the length prefixed children.
+
+// txLeaf returns the merkle leaf of the transaction bytes
+func txLeaf(txBytes []byte) []byte {
+	hash := sha256.Sum256(txBytes)
+	return hash[:]
+}
+
+// merkleRoot computes the root of the tree constructed over the leaves
+func merkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		return nil
+	case 1:
+		return leaves[0]
+	default:
+		numLeft := (len(leaves) + 1) / 2
+		return innerHash(merkleRoot(leaves[:numLeft]), merkleRoot(leaves[numLeft:]))
+	}
+}
+
+// merkleProof returns the aunts of the leaf at `index` from the leaf's sibling
+// up to the root's child, flattened into a single byte slice
+func merkleProof(leaves [][]byte, index int) []byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+
+	numLeft := (len(leaves) + 1) / 2
+	if index < numLeft {
+		return append(merkleProof(leaves[:numLeft], index), merkleRoot(leaves[numLeft:])...)
+	}
+
+	return append(merkleProof(leaves[numLeft:], index-numLeft), merkleRoot(leaves[:numLeft])...)
+}
+
+func innerHash(left, right []byte) []byte {
+	hasher := sha256.New()
+	for _, child := range [][]byte{left, right} {
+		var prefix [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(prefix[:], uint64(len(child)))
+		hasher.Write(prefix[:n])
+		hasher.Write(child)
+	}
+
+	return hasher.Sum(nil)
+}
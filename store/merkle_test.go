@@ -0,0 +1,37 @@
+package store
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"testing"
+)
+
+// The tree must match the tree tendermint commits to in the block header
+func TestMerkleTree(t *testing.T) {
+	for total := 1; total <= 17; total++ {
+		var txs tmtypes.Txs
+		var txBytes, leaves [][]byte
+		for i := 0; i < total; i++ {
+			tx := []byte(fmt.Sprintf("transaction %d", i))
+			txs = append(txs, tx)
+			txBytes = append(txBytes, tx)
+			leaves = append(leaves, txLeaf(tx))
+		}
+
+		root := merkleRoot(leaves)
+		require.Equal(t, txs.Hash(), root, "total %d: mismatch in merkle root", total)
+
+		_, proofs := merkle.SimpleProofsFromByteSlices(txBytes)
+		for i, expected := range proofs {
+			var flattened []byte
+			for _, aunt := range expected.Aunts {
+				flattened = append(flattened, aunt...)
+			}
+
+			proof := merkleProof(leaves, i)
+			require.Equal(t, flattened, proof, "total %d, index %d: mismatch in merkle proof", total, i)
+		}
+	}
+}
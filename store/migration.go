@@ -0,0 +1,145 @@
+package store
+
+import (
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+	"sort"
+)
+
+// HasLegacyWallets returns whether the wallets in the store use the layout of
+// a release before wallet outputs were stored as separate records.
+func (ds DataStore) HasLegacyWallets(ctx sdk.Context) bool {
+	iter := sdk.KVStorePrefixIterator(ds.KVStore(ctx), walletKey)
+	defer iter.Close()
+
+	// every wallet of a store is kept in the same layout
+	return iter.Valid() && isLegacyWallet(iter.Value())
+}
+
+// MigrateWallets rewrites every wallet stored in the legacy layout into a
+// Wallet and the WalletOutput records of the positions it references. The
+// outputs are sequenced by the block they were created in and their position.
+// Transactions, deposits and fees are rewritten as well so that they are
+// indexed by position and spender.
+func (ds DataStore) MigrateWallets(ctx sdk.Context) {
+	// the store is not written to while it is iterated
+	var addrs []common.Address
+	var wallets []legacyWallet
+	ds.iterate(ctx, walletKey, func(key, value []byte) {
+		var wallet legacyWallet
+		if err := rlp.DecodeBytes(value, &wallet); err != nil {
+			return
+		}
+		addrs = append(addrs, common.BytesToAddress(key))
+		wallets = append(wallets, wallet)
+	})
+
+	var txs []Transaction
+	ds.iterate(ctx, txKey, func(key, value []byte) {
+		var tx Transaction
+		if err := rlp.DecodeBytes(value, &tx); err != nil {
+			panic(fmt.Sprintf("transaction store corrupted: %s", err))
+		}
+		txs = append(txs, tx)
+	})
+	for _, tx := range txs {
+		ds.setTx(ctx, tx)
+	}
+
+	var nonces []*big.Int
+	ds.iterate(ctx, depositKey, func(key, value []byte) {
+		nonces = append(nonces, new(big.Int).SetBytes(key))
+	})
+	for _, nonce := range nonces {
+		deposit, _ := ds.GetDeposit(ctx, nonce)
+		ds.setDeposit(ctx, nonce, deposit)
+	}
+
+	var fees []plasma.Position
+	ds.iterate(ctx, feeKey, func(key, value []byte) {
+		var pos plasma.Position
+		if err := rlp.DecodeBytes(key, &pos); err != nil {
+			panic(fmt.Sprintf("fee store corrupted: %s", err))
+		}
+		fees = append(fees, pos)
+	})
+	for _, pos := range fees {
+		fee, _ := ds.GetFee(ctx, pos)
+		ds.setFee(ctx, pos, fee)
+	}
+
+	for i, addr := range addrs {
+		var outputs []WalletOutput
+		for _, pos := range append(wallets[i].Unspent, wallets[i].Spent...) {
+			outputs = append(outputs, ds.legacyWalletOutput(ctx, addr, pos))
+		}
+		sort.SliceStable(outputs, func(i, j int) bool {
+			if c := outputs[i].CreatedAt.Cmp(outputs[j].CreatedAt); c != 0 {
+				return c < 0
+			}
+			return outputs[i].Position.Priority().Cmp(outputs[j].Position.Priority()) < 0
+		})
+
+		for seq, output := range outputs {
+			ds.setWalletOutput(ctx, addr, uint64(seq), output)
+		}
+		ds.setWallet(ctx, addr, Wallet{wallets[i].Balance, uint64(len(outputs))})
+	}
+}
+
+// legacyWalletOutput reconstructs the wallet output at the given position from
+// the deposit, fee or transaction that created it. The block a deposit was
+// included in was not recorded by the legacy layout and is left at 0.
+func (ds DataStore) legacyWalletOutput(ctx sdk.Context, addr common.Address, pos plasma.Position) WalletOutput {
+	output := WalletOutput{
+		Position:  pos,
+		CreatedAt: big.NewInt(0),
+		SpentAt:   big.NewInt(0),
+	}
+
+	switch {
+	case pos.IsDeposit():
+		deposit, ok := ds.GetDeposit(ctx, pos.DepositNonce)
+		if !ok {
+			panic(fmt.Sprintf("wallet 0x%x references deposit %s that does not exist", addr, pos.DepositNonce))
+		}
+		output.Amount = deposit.Deposit.Amount
+		output.Spent, output.SpenderTx = deposit.Spent, deposit.SpenderTx
+	case pos.IsFee():
+		fee, ok := ds.GetFee(ctx, pos)
+		if !ok {
+			panic(fmt.Sprintf("wallet 0x%x references fee %s that does not exist", addr, pos))
+		}
+		output.Amount = fee.Output.Amount
+		output.CreatedAt = pos.BlockNum
+		output.Spent, output.SpenderTx = fee.Spent, fee.SpenderTx
+	default:
+		tx, ok := ds.GetTxWithPosition(ctx, pos)
+		if !ok {
+			panic(fmt.Sprintf("wallet 0x%x references output %s that does not exist", addr, pos))
+		}
+		output.Amount = tx.Transaction.Outputs[pos.OutputIndex].Amount
+		output.TxHash = tx.Transaction.TxHash()
+		output.CreatedAt = pos.BlockNum
+		output.Spent, output.SpenderTx = tx.Spent[pos.OutputIndex], tx.SpenderTxs[pos.OutputIndex]
+	}
+
+	if output.Spent {
+		spender, ok := ds.GetTx(ctx, output.SpenderTx)
+		if !ok {
+			panic(fmt.Sprintf("output %s was spent by transaction 0x%x that does not exist", pos, output.SpenderTx))
+		}
+		output.SpentAt = spender.Position.BlockNum
+	}
+
+	return output
+}
+
+func isLegacyWallet(data []byte) bool {
+	var wallet legacyWallet
+	return rlp.DecodeBytes(data, &wallet) == nil
+}
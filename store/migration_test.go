@@ -0,0 +1,90 @@
+package store
+
+import (
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+// Test that wallets stored in the layout of an earlier release are migrated in place
+func TestMigrateWallets(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+	addr := common.BytesToAddress([]byte("asdfasdf"))
+
+	require.False(t, ds.HasLegacyWallets(ctx), "empty store reported in the legacy layout")
+
+	// a deposit spent into an output of the wallet and a fee
+	depositPos := getPosition("(0.0.0.1)")
+	ds.StoreDeposit(ctx, big.NewInt(1), plasma.NewDeposit(addr, big.NewInt(10), big.NewInt(1)))
+	tx := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(depositPos, [65]byte{}, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(10))},
+			Fee:     utils.Big0,
+		},
+		Spent:      []bool{false},
+		SpenderTxs: [][]byte{{}},
+		Position:   getPosition("(1.0.0.0)"),
+	}
+	hash := tx.Transaction.TxHash()
+	ds.StoreTx(ctx, tx)
+	require.True(t, ds.SpendDeposit(ctx, big.NewInt(1), hash).IsOK())
+	ds.StoreOutputs(ctx, tx)
+	feePos := getPosition("(1.65535.0.0)")
+	ds.StoreFee(ctx, big.NewInt(1), plasma.NewOutput(addr, big.NewInt(5)))
+	require.False(t, ds.HasLegacyWallets(ctx))
+
+	wallet, _ := ds.GetWallet(ctx, addr)
+	var expected []WalletOutput
+	for seq := uint64(0); seq < wallet.NumOutputs; seq++ {
+		output, _ := ds.GetWalletOutput(ctx, addr, seq)
+		expected = append(expected, output)
+
+		ds.Delete(ctx, GetWalletOutputKey(addr, seq))
+		ds.Delete(ctx, GetWalletPositionKey(addr, output.Position))
+		ds.Delete(ctx, GetWalletUnspentKey(addr, seq))
+	}
+	// the block a deposit was included in is not recorded by the legacy layout
+	expected[0].CreatedAt = big.NewInt(0)
+
+	// rewrite the wallet and drop the indexes of the earlier release
+	legacy := legacyWallet{
+		Balance: big.NewInt(15),
+		Unspent: []plasma.Position{feePos, tx.Position},
+		Spent:   []plasma.Position{depositPos},
+	}
+	data, err := rlp.EncodeToBytes(&legacy)
+	require.NoError(t, err)
+	ds.Set(ctx, GetWalletKey(addr), data)
+	ds.Delete(ctx, GetSpenderKey(depositPos))
+	ds.Delete(ctx, GetTxPositionKey(hash))
+
+	require.True(t, ds.HasLegacyWallets(ctx), "legacy wallet not detected")
+	wallet, ok := ds.GetWallet(ctx, addr)
+	require.True(t, ok)
+	require.Equal(t, Wallet{big.NewInt(15), 0}, wallet, "balance of the legacy wallet not reported")
+
+	ds.MigrateWallets(ctx)
+	require.False(t, ds.HasLegacyWallets(ctx), "legacy wallet not migrated")
+
+	wallet, _ = ds.GetWallet(ctx, addr)
+	require.Equal(t, Wallet{big.NewInt(15), uint64(len(expected))}, wallet)
+	for seq, output := range expected {
+		migrated, ok := ds.GetWalletOutput(ctx, addr, uint64(seq))
+		require.True(t, ok, "output %d not migrated", seq)
+		require.Equal(t, output, migrated, "mismatch in output %d", seq)
+	}
+	require.Len(t, ds.GetUnspentForWallet(ctx, addr), 2)
+
+	spender, ok := ds.GetSpender(ctx, depositPos)
+	require.True(t, ok, "spender of the deposit not indexed")
+	require.Equal(t, hash, spender)
+	pos, ok := ds.GetTxPosition(ctx, hash)
+	require.True(t, ok, "position of the transaction not indexed")
+	require.Equal(t, tx.Position, pos)
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"encoding/binary"
 	"fmt"
 	"github.com/FourthState/plasma-mvp-sidechain/plasma"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -12,7 +13,9 @@ import (
 // -----------------------------------------------------------------------------
 /* Getters */
 
-// GetWallet returns the wallet at the associated address.
+// GetWallet returns the wallet at the associated address. Wallets stored in
+// the legacy layout report their balance without any outputs until they are
+// migrated.
 func (ds DataStore) GetWallet(ctx sdk.Context, addr common.Address) (Wallet, bool) {
 	key := GetWalletKey(addr)
 	data := ds.Get(ctx, key)
@@ -22,12 +25,33 @@ func (ds DataStore) GetWallet(ctx sdk.Context, addr common.Address) (Wallet, boo
 
 	var wallet Wallet
 	if err := rlp.DecodeBytes(data, &wallet); err != nil {
-		panic(fmt.Sprintf("transaction store corrupted: %s", err))
+		var legacy legacyWallet
+		if rlp.DecodeBytes(data, &legacy) != nil {
+			panic(fmt.Sprintf("wallet store corrupted: %s", err))
+		}
+		return Wallet{legacy.Balance, 0}, true
 	}
 
 	return wallet, true
 }
 
+// GetWalletOutput returns the output received by the wallet at the associated
+// address with the given sequence.
+func (ds DataStore) GetWalletOutput(ctx sdk.Context, addr common.Address, seq uint64) (WalletOutput, bool) {
+	key := GetWalletOutputKey(addr, seq)
+	data := ds.Get(ctx, key)
+	if data == nil {
+		return WalletOutput{}, false
+	}
+
+	var output WalletOutput
+	if err := rlp.DecodeBytes(data, &output); err != nil {
+		panic(fmt.Sprintf("wallet store corrupted: %s", err))
+	}
+
+	return output, true
+}
+
 // GetDeposit returns the deposit at the given nonce.
 func (ds DataStore) GetDeposit(ctx sdk.Context, nonce *big.Int) (Deposit, bool) {
 	key := GetDepositKey(nonce)
@@ -116,6 +140,29 @@ func (ds DataStore) GetTxWithPosition(ctx sdk.Context, pos plasma.Position) (Tra
 	return ds.GetTx(ctx, hash)
 }
 
+// GetTxPosition returns the position of the transaction with the provided
+// transaction hash.
+func (ds DataStore) GetTxPosition(ctx sdk.Context, hash []byte) (plasma.Position, bool) {
+	data := ds.Get(ctx, GetTxPositionKey(hash))
+	if data == nil {
+		return plasma.Position{}, false
+	}
+
+	var pos plasma.Position
+	if err := rlp.DecodeBytes(data, &pos); err != nil {
+		panic(fmt.Sprintf("transaction index corrupted: %s", err))
+	}
+
+	return pos, true
+}
+
+// GetSpender returns the hash of the transaction that spent the deposit, fee
+// or output at the provided position.
+func (ds DataStore) GetSpender(ctx sdk.Context, pos plasma.Position) ([]byte, bool) {
+	hash := ds.Get(ctx, GetSpenderKey(pos))
+	return hash, hash != nil
+}
+
 // -----------------------------------------------------------------------------
 /* Has */
 
@@ -166,6 +213,23 @@ func (ds DataStore) setWallet(ctx sdk.Context, addr common.Address, wallet Walle
 	ds.Set(ctx, key, data)
 }
 
+// setWalletOutput overwrites the output stored at the given address and
+// sequence and keeps the unspent index in line with its spend state.
+func (ds DataStore) setWalletOutput(ctx sdk.Context, addr common.Address, seq uint64, output WalletOutput) {
+	data, err := rlp.EncodeToBytes(&output)
+	if err != nil {
+		panic(fmt.Sprintf("error marshaling wallet output with position %s: %s", output.Position, err))
+	}
+
+	ds.Set(ctx, GetWalletOutputKey(addr, seq), data)
+	ds.Set(ctx, GetWalletPositionKey(addr, output.Position), sequenceBytes(seq))
+	if output.Spent {
+		ds.Delete(ctx, GetWalletUnspentKey(addr, seq))
+	} else {
+		ds.Set(ctx, GetWalletUnspentKey(addr, seq), []byte{})
+	}
+}
+
 // setDeposit overwrites the deposit stored at the given nonce.
 func (ds DataStore) setDeposit(ctx sdk.Context, nonce *big.Int, deposit Deposit) {
 	data, err := rlp.EncodeToBytes(&deposit)
@@ -175,6 +239,9 @@ func (ds DataStore) setDeposit(ctx sdk.Context, nonce *big.Int, deposit Deposit)
 
 	key := GetDepositKey(nonce)
 	ds.Set(ctx, key, data)
+	if deposit.Spent {
+		ds.Set(ctx, GetSpenderKey(plasma.NewPosition(big.NewInt(0), 0, 0, nonce)), deposit.SpenderTx)
+	}
 }
 
 // setFee overwrites the fee stored at the given position.
@@ -186,6 +253,9 @@ func (ds DataStore) setFee(ctx sdk.Context, pos plasma.Position, fee Output) {
 
 	key := GetFeeKey(pos)
 	ds.Set(ctx, key, data)
+	if fee.Spent {
+		ds.Set(ctx, GetSpenderKey(pos), fee.SpenderTx)
+	}
 }
 
 // setOutput adds a mapping from position to transaction hash.
@@ -194,15 +264,23 @@ func (ds DataStore) setOutput(ctx sdk.Context, pos plasma.Position, hash []byte)
 	ds.Set(ctx, key, hash)
 }
 
-// setTx overwrites the mapping from transaction hash to transaction.
+// setTx overwrites the mapping from transaction hash to transaction and
+// indexes the position of the transaction and the spenders of its outputs.
 func (ds DataStore) setTx(ctx sdk.Context, tx Transaction) {
 	data, err := rlp.EncodeToBytes(&tx)
 	if err != nil {
 		panic(fmt.Sprintf("error marshaling transaction: %s", err))
 	}
 
-	key := GetTxKey(tx.Transaction.TxHash())
-	ds.Set(ctx, key, data)
+	hash := tx.Transaction.TxHash()
+	ds.Set(ctx, GetTxKey(hash), data)
+	ds.Set(ctx, GetTxPositionKey(hash), tx.Position.Bytes())
+	for i, spent := range tx.Spent {
+		if spent {
+			pos := plasma.NewPosition(tx.Position.BlockNum, tx.Position.TxIndex, uint8(i), big.NewInt(0))
+			ds.Set(ctx, GetSpenderKey(pos), tx.SpenderTxs[i])
+		}
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -212,14 +290,14 @@ func (ds DataStore) setTx(ctx sdk.Context, tx Transaction) {
 // wallet.
 func (ds DataStore) StoreDeposit(ctx sdk.Context, nonce *big.Int, deposit plasma.Deposit) {
 	ds.setDeposit(ctx, nonce, Deposit{deposit, false, make([]byte, 0)})
-	ds.addToWallet(ctx, deposit.Owner, deposit.Amount, plasma.NewPosition(big.NewInt(0), 0, 0, nonce))
+	ds.addToWallet(ctx, deposit.Owner, deposit.Amount, plasma.NewPosition(big.NewInt(0), 0, 0, nonce), nil, ds.NextPlasmaBlockHeight(ctx))
 }
 
 // StoreFee adds an unspent fee and updates the fee owner's wallet.
 func (ds DataStore) StoreFee(ctx sdk.Context, blockNum *big.Int, output plasma.Output) {
 	pos := plasma.NewPosition(blockNum, 1<<16-1, 0, big.NewInt(0))
 	ds.setFee(ctx, pos, Output{output, false, make([]byte, 0)})
-	ds.addToWallet(ctx, output.Owner, output.Amount, pos)
+	ds.addToWallet(ctx, output.Owner, output.Amount, pos, nil, blockNum)
 }
 
 // StoreTx adds the transaction.
@@ -229,9 +307,11 @@ func (ds DataStore) StoreTx(ctx sdk.Context, tx Transaction) {
 
 // StoreOutputs adds new Output UTXO's to respective owner's wallets.
 func (ds DataStore) StoreOutputs(ctx sdk.Context, tx Transaction) {
+	hash := tx.Transaction.TxHash()
 	for i, output := range tx.Transaction.Outputs {
-		ds.addToWallet(ctx, output.Owner, output.Amount, plasma.NewPosition(tx.Position.BlockNum, tx.Position.TxIndex, uint8(i), big.NewInt(0)))
-		ds.setOutput(ctx, plasma.NewPosition(tx.Position.BlockNum, tx.Position.TxIndex, uint8(i), big.NewInt(0)), tx.Transaction.TxHash())
+		pos := plasma.NewPosition(tx.Position.BlockNum, tx.Position.TxIndex, uint8(i), big.NewInt(0))
+		ds.addToWallet(ctx, output.Owner, output.Amount, pos, hash, tx.Position.BlockNum)
+		ds.setOutput(ctx, pos, hash)
 	}
 }
 
@@ -252,7 +332,7 @@ func (ds DataStore) SpendDeposit(ctx sdk.Context, nonce *big.Int, spenderTx []by
 	deposit.SpenderTx = spenderTx
 
 	ds.setDeposit(ctx, nonce, deposit)
-	ds.subtractFromWallet(ctx, deposit.Deposit.Owner, deposit.Deposit.Amount, plasma.NewPosition(big.NewInt(0), 0, 0, nonce))
+	ds.subtractFromWallet(ctx, deposit.Deposit.Owner, deposit.Deposit.Amount, plasma.NewPosition(big.NewInt(0), 0, 0, nonce), spenderTx)
 
 	return sdk.Result{}
 }
@@ -271,7 +351,7 @@ func (ds DataStore) SpendFee(ctx sdk.Context, pos plasma.Position, spenderTx []b
 	fee.SpenderTx = spenderTx
 
 	ds.setFee(ctx, pos, fee)
-	ds.subtractFromWallet(ctx, fee.Output.Owner, fee.Output.Amount, pos)
+	ds.subtractFromWallet(ctx, fee.Output.Owner, fee.Output.Amount, pos, spenderTx)
 
 	return sdk.Result{}
 }
@@ -293,28 +373,37 @@ func (ds DataStore) SpendOutput(ctx sdk.Context, pos plasma.Position, spenderTx
 	tx.SpenderTxs[pos.OutputIndex] = spenderTx
 
 	ds.setTx(ctx, tx)
-	ds.subtractFromWallet(ctx, tx.Transaction.Outputs[pos.OutputIndex].Owner, tx.Transaction.Outputs[pos.OutputIndex].Amount, pos)
+	ds.subtractFromWallet(ctx, tx.Transaction.Outputs[pos.OutputIndex].Owner, tx.Transaction.Outputs[pos.OutputIndex].Amount, pos, spenderTx)
 
 	return sdk.Result{}
 }
 
-// GetUnspentForWallet returns the unspent outputs that belong to the given
-// wallet. Returns the struct TxOutput so the user has access to the
-// transactional information related to the output.
-func (ds DataStore) GetUnspentForWallet(ctx sdk.Context, wallet Wallet) (utxos []TxOutput) {
-	for _, p := range wallet.Unspent {
-		output, ok := ds.GetOutput(ctx, p)
+// GetUnspentForWallet returns the unspent outputs that belong to the wallet
+// at the given address in the order they were received. Returns the struct
+// TxOutput so the user has access to the transactional information related
+// to the output.
+func (ds DataStore) GetUnspentForWallet(ctx sdk.Context, addr common.Address) (utxos []TxOutput) {
+	prefix := walletUnspentPrefix(addr)
+	ds.iterate(ctx, prefix, func(key, value []byte) {
+		seq := binary.BigEndian.Uint64(key)
+		output, ok := ds.GetWalletOutput(ctx, addr, seq)
 		if !ok {
-			panic(fmt.Sprintf("Corrupted store: Wallet contains unspent position (%v) that doesn't exist in store", p))
+			panic(fmt.Sprintf("Corrupted store: Wallet 0x%x contains unspent sequence %d that doesn't exist in store", addr, seq))
 		}
-		tx, ok := ds.GetTxWithPosition(ctx, p)
-		if !ok {
-			panic(fmt.Sprintf("Corrupted store: Wallet contains unspent position (%v) that doesn't have corresponding tx", p))
+
+		var confirmationHash []byte
+		if len(output.TxHash) > 0 {
+			tx, ok := ds.GetTx(ctx, output.TxHash)
+			if !ok {
+				panic(fmt.Sprintf("Corrupted store: Wallet contains unspent position (%v) that doesn't have corresponding tx", output.Position))
+			}
+			confirmationHash = tx.ConfirmationHash
 		}
 
-		txo := NewTxOutput(output.Output, p, tx.ConfirmationHash, tx.Transaction.TxHash(), output.Spent, output.SpenderTx)
+		txo := NewTxOutput(plasma.NewOutput(addr, output.Amount), output.Position, confirmationHash, output.TxHash, output.Spent, output.SpenderTx)
 		utxos = append(utxos, txo)
-	}
+	})
+
 	return utxos
 }
 
@@ -337,23 +426,32 @@ func (ds DataStore) depositToOutput(ctx sdk.Context, nonce *big.Int) (Output, bo
 }
 
 // addToWallet adds the passed in amount to the wallet with the given
-// address and adds the position provided to the list of unspent positions
-// within the wallet.
-func (ds DataStore) addToWallet(ctx sdk.Context, addr common.Address, amount *big.Int, pos plasma.Position) {
+// address and records the output at the provided position as the next
+// output received by the wallet.
+func (ds DataStore) addToWallet(ctx sdk.Context, addr common.Address, amount *big.Int, pos plasma.Position, txHash []byte, createdAt *big.Int) {
 	wallet, ok := ds.GetWallet(ctx, addr)
 	if !ok {
-		wallet = Wallet{big.NewInt(0), make([]plasma.Position, 0), make([]plasma.Position, 0)}
+		wallet = Wallet{big.NewInt(0), 0}
+	}
+
+	output := WalletOutput{
+		Position:  pos,
+		Amount:    amount,
+		TxHash:    txHash,
+		CreatedAt: createdAt,
+		SpentAt:   big.NewInt(0),
 	}
+	ds.setWalletOutput(ctx, addr, wallet.NumOutputs, output)
 
 	wallet.Balance = new(big.Int).Add(wallet.Balance, amount)
-	wallet.Unspent = append(wallet.Unspent, pos)
+	wallet.NumOutputs++
 	ds.setWallet(ctx, addr, wallet)
 }
 
 // subtractFromWallet subtracts the passed in amount from the wallet with
-// the given address and moves the provided position from the unspent list
-// to the spent list.
-func (ds DataStore) subtractFromWallet(ctx sdk.Context, addr common.Address, amount *big.Int, pos plasma.Position) {
+// the given address and marks the output at the provided position as spent
+// in the next plasma block.
+func (ds DataStore) subtractFromWallet(ctx sdk.Context, addr common.Address, amount *big.Int, pos plasma.Position, spenderTx []byte) {
 	wallet, ok := ds.GetWallet(ctx, addr)
 	if !ok {
 		panic(fmt.Sprintf("output store has been corrupted"))
@@ -364,18 +462,20 @@ func (ds DataStore) subtractFromWallet(ctx sdk.Context, addr common.Address, amo
 	if wallet.Balance.Sign() == -1 {
 		panic(fmt.Sprintf("wallet with address 0x%x has a negative balance", addr))
 	}
-
-	wallet.Unspent = removePosition(wallet.Unspent, pos)
-	wallet.Spent = append(wallet.Spent, pos)
 	ds.setWallet(ctx, addr, wallet)
-}
 
-// helper function to remove a position from the unspent list.
-func removePosition(positions []plasma.Position, pos plasma.Position) []plasma.Position {
-	for i, p := range positions {
-		if p.String() == pos.String() {
-			positions = append(positions[:i], positions[i+1:]...)
-		}
+	data := ds.Get(ctx, GetWalletPositionKey(addr, pos))
+	if data == nil {
+		panic(fmt.Sprintf("wallet with address 0x%x does not contain position %s", addr, pos))
 	}
-	return positions
+	seq := binary.BigEndian.Uint64(data)
+	output, ok := ds.GetWalletOutput(ctx, addr, seq)
+	if !ok {
+		panic(fmt.Sprintf("wallet with address 0x%x does not contain sequence %d", addr, seq))
+	}
+
+	output.Spent = true
+	output.SpenderTx = spenderTx
+	output.SpentAt = ds.NextPlasmaBlockHeight(ctx)
+	ds.setWalletOutput(ctx, addr, seq, output)
 }
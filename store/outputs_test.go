@@ -30,6 +30,8 @@ func TestDeposits(t *testing.T) {
 		require.False(t, ok, "did not return error on nonexistent deposit")
 		res := outputStore.SpendDeposit(ctx, nonce, hash)
 		require.Equal(t, res.Code, CodeDNE, "did not return that deposit does not exist")
+		_, ok = outputStore.GetSpender(ctx, plasma.NewPosition(nil, 0, 0, nonce))
+		require.False(t, ok, "returned a spender for a nonexistent deposit")
 
 		// Create and store new deposit
 		plasmaDeposit := plasma.NewDeposit(addr, big.NewInt(i*4123), big.NewInt(i*123))
@@ -53,6 +55,10 @@ func TestDeposits(t *testing.T) {
 		recoveredDeposit, ok = outputStore.GetDeposit(ctx, nonce)
 		require.True(t, ok, "error when retrieving deposit")
 		require.True(t, reflect.DeepEqual(deposit, recoveredDeposit), "mismatch in stored and retrieved deposit")
+
+		spender, ok := outputStore.GetSpender(ctx, plasma.NewPosition(nil, 0, 0, nonce))
+		require.True(t, ok, "spender of the deposit not indexed")
+		require.Equal(t, hash, spender, "mismatch in spender of the deposit")
 	}
 }
 
@@ -97,6 +103,10 @@ func TestFees(t *testing.T) {
 		recoveredFee, ok = outputStore.GetFee(ctx, pos)
 		require.True(t, ok, "error when retrieving fee")
 		require.True(t, reflect.DeepEqual(fee, recoveredFee), "mismatch in stored and retrieved fee")
+
+		spender, ok := outputStore.GetSpender(ctx, pos)
+		require.True(t, ok, "spender of the fee not indexed")
+		require.Equal(t, hash, spender, "mismatch in spender of the fee")
 	}
 }
 
@@ -192,6 +202,11 @@ func TestTransactions(t *testing.T) {
 			require.True(t, exists, fmt.Sprintf("returned false for stored output with index %d on case %d", j, i))
 		}
 
+		// Check the position index
+		recoveredPos, ok := outputStore.GetTxPosition(ctx, plasmaTx.Transaction.TxHash())
+		require.True(t, ok, "position of the transaction not indexed")
+		require.Equal(t, pos.String(), recoveredPos.String(), fmt.Sprintf("mismatch in stored and indexed position on case %d", i))
+
 		// Check for Tx
 		exists = outputStore.HasTx(ctx, plasmaTx.Transaction.TxHash())
 		require.True(t, exists, "returned false for transaction that was stored")
@@ -206,8 +221,13 @@ func TestTransactions(t *testing.T) {
 			require.True(t, ok, "error when retrieving transaction")
 			require.True(t, reflect.DeepEqual(tx, recoveredTx), fmt.Sprintf("mismatch in stored transaction and retrieved transaction on case %d", i))
 
+			_, ok = outputStore.GetSpender(ctx, p)
+			require.False(t, ok, "returned a spender for an unspent output")
 			res := outputStore.SpendOutput(ctx, p, plasmaTx.Transaction.MerkleHash())
 			require.True(t, res.IsOK(), "returned error when spending output")
+			spender, ok := outputStore.GetSpender(ctx, p)
+			require.True(t, ok, "spender of the output not indexed")
+			require.Equal(t, plasmaTx.Transaction.MerkleHash(), spender, fmt.Sprintf("mismatch in spender of output %d on case %d", j, i))
 			res = outputStore.SpendOutput(ctx, p, plasmaTx.Transaction.MerkleHash())
 			require.Equal(t, res.Code, CodeOutputSpent, fmt.Sprintf("allowed output with index %d to be spent twice on case %d", j, i))
 
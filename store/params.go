@@ -0,0 +1,48 @@
+package store
+
+import (
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DefaultMaxTxsPerBlock is the largest number of transactions a plasma block
+// can contain. Transaction index 65535 is reserved for the fee output
+const DefaultMaxTxsPerBlock uint16 = 1<<16 - 1
+
+// Params are the consensus parameters of the chain set in genesis.
+// MaxTxsPerBlock bounds the spends and deposits included in a plasma block.
+type Params struct {
+	MaxTxsPerBlock uint16
+}
+
+// DefaultParams returns the parameters of a chain started from a genesis
+// file that does not specify them.
+func DefaultParams() Params {
+	return Params{DefaultMaxTxsPerBlock}
+}
+
+// GetParams returns the parameters of the chain.
+func (ds DataStore) GetParams(ctx sdk.Context) (Params, bool) {
+	data := ds.Get(ctx, GetParamsKey())
+	if data == nil {
+		return Params{}, false
+	}
+
+	var params Params
+	if err := rlp.DecodeBytes(data, &params); err != nil {
+		panic(fmt.Sprintf("params store corrupted: %s", err))
+	}
+
+	return params, true
+}
+
+// StoreParams overwrites the parameters of the chain.
+func (ds DataStore) StoreParams(ctx sdk.Context, params Params) {
+	data, err := rlp.EncodeToBytes(&params)
+	if err != nil {
+		panic(fmt.Sprintf("error marshaling params: %s", err))
+	}
+
+	ds.Set(ctx, GetParamsKey(), data)
+}
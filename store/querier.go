@@ -10,6 +10,7 @@ import (
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"math/big"
+	"strconv"
 )
 
 const (
@@ -25,6 +26,10 @@ const (
 	// speficied block
 	QueryBlock = "block"
 
+	// QueryTMBlock retrieves the plasma block committed
+	// at a specified tendermint block height
+	QueryTMBlock = "tmblock"
+
 	// QueryBlocks retrieves metadata about 10 blocks from
 	// a specified start point or the last 10 from the latest
 	// block
@@ -40,6 +45,25 @@ const (
 	// by the specified address
 	QueryInfo = "info"
 
+	// QueryHistory retrieves a page of the outputs received
+	// and spent by the specified address. The page is filtered
+	// by the HistoryParams in the request data
+	QueryHistory = "history"
+
+	// QueryFees retrieves the unspent fee outputs
+	// collected by an address along with the output
+	// of its latest fee sweep
+	QueryFees = "fees"
+
+	// QueryFeeAddress retrieves the address fees are
+	// collected to along with the nonce of the next
+	// fee address update
+	QueryFeeAddress = "feeaddress"
+
+	// QueryValidators retrieves the validator set along
+	// with the nonce of the next validator update
+	QueryValidators = "validators"
+
 	// QueryTxOutput retrieves a single output at
 	// the given position and returns it with transactional
 	// information
@@ -51,6 +75,19 @@ const (
 
 	// QueryTx retrieves a transaction at the given hash
 	QueryTx = "tx"
+
+	// QueryTxPosition retrieves the position of the
+	// transaction at the given hash
+	QueryTxPosition = "position"
+
+	// QuerySpender retrieves the transaction that spent
+	// the deposit, fee or output at the given position
+	QuerySpender = "spender"
+
+	// QueryTxProof retrieves the transaction at the given
+	// position along with the merkle proof of its inclusion
+	// and the header of the plasma block
+	QueryTxProof = "proof"
 )
 
 // NewQuerier returns an SDK querier to interact with the store
@@ -67,16 +104,32 @@ func NewQuerier(ds DataStore) sdk.Querier {
 			return queryBlock(ctx, ds, path[1:])
 		case QueryBlocks:
 			return queryBlocks(ctx, ds, path[1:])
+		case QueryTMBlock:
+			return queryTMBlock(ctx, ds, path[1:])
 		case QueryBalance:
 			return queryBalance(ctx, ds, path[1:])
 		case QueryInfo:
 			return queryInfo(ctx, ds, path[1:])
+		case QueryHistory:
+			return queryHistory(ctx, ds, path[1:], req.Data)
+		case QueryFees:
+			return queryFees(ctx, ds, path[1:])
+		case QueryFeeAddress:
+			return queryFeeAddress(ctx, ds)
+		case QueryValidators:
+			return queryValidators(ctx, ds)
 		case QueryTxOutput:
 			return queryTxOutput(ctx, ds, path[1:])
 		case QueryTxInput:
 			return queryTxInput(ctx, ds, path[1:])
 		case QueryTx:
 			return queryTx(ctx, ds, path[1:])
+		case QueryTxPosition:
+			return queryTxPosition(ctx, ds, path[1:])
+		case QuerySpender:
+			return querySpender(ctx, ds, path[1:])
+		case QueryTxProof:
+			return queryTxProof(ctx, ds, path[1:])
 		default:
 			return nil, ErrInvalidPath("unregistered query path")
 		}
@@ -155,6 +208,24 @@ func queryBlocks(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Erro
 	return marshalResponse(blocks)
 }
 
+func queryTMBlock(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, ErrInvalidPath("expected %s/<tendermint height>", QueryTMBlock)
+	}
+
+	tmHeight, err := strconv.ParseUint(path[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidPath("tendermint block height must be in decimal format")
+	}
+
+	block, ok := ds.GetBlockAtTMHeight(ctx, tmHeight)
+	if !ok {
+		return nil, ErrDNE("no plasma block was committed at tendermint height %d", tmHeight)
+	}
+
+	return marshalResponse(block)
+}
+
 func queryBalance(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
 	if len(path) != 1 {
 		return nil, ErrInvalidPath("expected %s/<address>", QueryBalance)
@@ -183,15 +254,67 @@ func queryInfo(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error)
 		return nil, err
 	}
 
-	acc, ok := ds.GetWallet(ctx, addr)
-	if !ok {
+	if !ds.HasWallet(ctx, addr) {
 		return nil, ErrDNE("no wallet exists for the address provided: 0x%x", addr)
 	}
 
-	outputs := ds.GetUnspentForWallet(ctx, acc)
+	outputs := ds.GetUnspentForWallet(ctx, addr)
 	return marshalResponse(outputs)
 }
 
+func queryFees(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, ErrInvalidPath("expected %s/<address>", QueryFees)
+	}
+
+	addr, err := parseAddress(path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if !ds.HasWallet(ctx, addr) {
+		return nil, ErrDNE("no wallet exists for the address provided: 0x%x", addr)
+	}
+
+	return marshalResponse(ds.GetFeeOutputs(ctx, addr))
+}
+
+func queryFeeAddress(ctx sdk.Context, ds DataStore) ([]byte, sdk.Error) {
+	validator, _ := ds.GetValidator(ctx)
+	return marshalResponse(FeeAddress{validator.FeeAddress, ds.GetFeeAddressNonce(ctx)})
+}
+
+func queryValidators(ctx sdk.Context, ds DataStore) ([]byte, sdk.Error) {
+	return marshalResponse(ValidatorSet{ds.GetValidatorSet(ctx), ds.GetValidatorUpdateNonce(ctx)})
+}
+
+func queryHistory(ctx sdk.Context, ds DataStore, path []string, data []byte) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, ErrInvalidPath("expected %s/<address>", QueryHistory)
+	}
+
+	addr, err := parseAddress(path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var params HistoryParams
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, ErrInvalidPath("malformed history parameters: %s", err)
+		}
+	}
+	if err := params.ValidateBasic(); err != nil {
+		return nil, ErrInvalidPath(err.Error())
+	}
+
+	if !ds.HasWallet(ctx, addr) {
+		return nil, ErrDNE("no wallet exists for the address provided: 0x%x", addr)
+	}
+
+	return marshalResponse(ds.GetWalletHistory(ctx, addr, params))
+}
+
 func queryTxOutput(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
 	if len(path) != 1 {
 		return nil, ErrInvalidPath("expected %s/<position>", QueryTxOutput)
@@ -207,6 +330,11 @@ func queryTxOutput(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Er
 		return nil, ErrDNE("no output exists for the position provided: %s", pos)
 	}
 
+	// deposits and fees are not created by a transaction
+	if pos.IsDeposit() || pos.IsFee() {
+		return marshalResponse(NewTxOutput(o.Output, pos, nil, nil, o.Spent, o.SpenderTx))
+	}
+
 	tx, ok := ds.GetTxWithPosition(ctx, pos)
 	if !ok {
 		return nil, ErrDNE("no transaction exists for the position provided: %s", pos)
@@ -254,11 +382,9 @@ func queryTx(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
 	if len(path) != 1 {
 		return nil, ErrInvalidPath("expected %s/<txhash>", QueryTx)
 	}
-	txHash, err := hex.DecodeString(utils.RemoveHexPrefix(path[0]))
+	txHash, err := parseTxHash(path[0])
 	if err != nil {
-		return nil, ErrInvalidPath("tx hash expected in hexadecimal format. hex: %s", err)
-	} else if len(txHash) != 32 {
-		return nil, ErrInvalidPath("tx hash expected to be 32 bytes in length")
+		return nil, err
 	}
 
 	tx, ok := ds.GetTx(ctx, txHash)
@@ -269,6 +395,67 @@ func queryTx(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
 	return marshalResponse(tx)
 }
 
+func queryTxPosition(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, ErrInvalidPath("expected %s/<txhash>", QueryTxPosition)
+	}
+
+	txHash, err := parseTxHash(path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	pos, ok := ds.GetTxPosition(ctx, txHash)
+	if !ok {
+		return nil, ErrDNE("no transaction exists for the hash provided: 0x%x", txHash)
+	}
+
+	return marshalResponse(pos)
+}
+
+func querySpender(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, ErrInvalidPath("expected %s/<position>", QuerySpender)
+	}
+
+	pos, err := plasma.FromPositionString(path[0])
+	if err != nil {
+		return nil, ErrInvalidPath("position is encoded in the format (blocknum,txIndex,oIndex,depositNonce)")
+	}
+
+	hash, ok := ds.GetSpender(ctx, pos)
+	if !ok {
+		return nil, ErrDNE("no spend exists for the position provided: %s", pos)
+	}
+
+	tx, ok := ds.GetTx(ctx, hash)
+	if !ok {
+		panic(fmt.Sprintf("Corrupted store: spender of position %s does not exist: 0x%x", pos, hash))
+	}
+
+	return marshalResponse(tx)
+}
+
+func queryTxProof(ctx sdk.Context, ds DataStore, path []string) ([]byte, sdk.Error) {
+	if len(path) != 1 {
+		return nil, ErrInvalidPath("expected %s/<position>", QueryTxProof)
+	}
+
+	pos, err := plasma.FromPositionString(path[0])
+	if err != nil {
+		return nil, ErrInvalidPath("position is encoded in the format (blocknum,txIndex,oIndex,depositNonce)")
+	} else if pos.IsDeposit() || pos.IsFee() {
+		return nil, ErrInvalidPath("deposits and fees are not included in the merkle tree of a plasma block")
+	}
+
+	proof, sdkErr := ds.GetTxProof(ctx, pos)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	return marshalResponse(proof)
+}
+
 /** helpers **/
 
 func marshalResponse(resp interface{}) ([]byte, sdk.Error) {
@@ -289,6 +476,17 @@ func parseHeight(height string) (*big.Int, sdk.Error) {
 	return h, nil
 }
 
+func parseTxHash(hash string) ([]byte, sdk.Error) {
+	txHash, err := hex.DecodeString(utils.RemoveHexPrefix(hash))
+	if err != nil {
+		return nil, ErrInvalidPath("tx hash expected in hexadecimal format. hex: %s", err)
+	} else if len(txHash) != 32 {
+		return nil, ErrInvalidPath("tx hash expected to be 32 bytes in length")
+	}
+
+	return txHash, nil
+}
+
 func parseAddress(addr string) (ethcmn.Address, sdk.Error) {
 	addr = utils.RemoveHexPrefix(addr)
 
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/FourthState/plasma-mvp-sidechain/plasma"
+	"github.com/FourthState/plasma-mvp-sidechain/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"math/big"
+	"testing"
+)
+
+// Test the querier routes of the secondary indexes
+func TestQueryIndexes(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+	querier := NewQuerier(ds)
+
+	addr := common.BytesToAddress([]byte("an ethereum address"))
+	ds.StoreDeposit(ctx, big.NewInt(1), plasma.NewDeposit(addr, big.NewInt(10), big.NewInt(1)))
+	ds.StoreBlock(ctx, 3, plasma.NewBlock([32]byte{}, 0, utils.Big0, big.NewInt(1)))
+
+	var sig [65]byte
+	tx := Transaction{
+		Transaction: plasma.Transaction{
+			Inputs:  []plasma.Input{plasma.NewInput(getPosition("(0.0.0.1)"), sig, nil)},
+			Outputs: []plasma.Output{plasma.NewOutput(addr, big.NewInt(10))},
+			Fee:     utils.Big0,
+		},
+		ConfirmationHash: []byte("confirmation hash"),
+		Spent:            []bool{false},
+		SpenderTxs:       [][]byte{[]byte{}},
+		Position:         getPosition("(2.0.0.0)"),
+	}
+	require.True(t, ds.SpendDeposit(ctx, big.NewInt(1), tx.Transaction.TxHash()).IsOK())
+	ds.StoreTx(ctx, tx)
+	ds.StoreOutputs(ctx, tx)
+	ds.StoreBlock(ctx, 7, plasma.NewBlock([32]byte{}, 1, utils.Big0, big.NewInt(2)))
+
+	// tx hash -> position
+	res, err := querier(ctx, []string{QueryTxPosition, fmt.Sprintf("0x%x", tx.Transaction.TxHash())}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var pos plasma.Position
+	require.NoError(t, json.Unmarshal(res, &pos))
+	require.Equal(t, "(2.0.0.0)", pos.String())
+
+	// position -> spender
+	res, err = querier(ctx, []string{QuerySpender, "(0.0.0.1)"}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var spender Transaction
+	require.NoError(t, json.Unmarshal(res, &spender))
+	require.Equal(t, tx.Transaction.TxHash(), spender.Transaction.TxHash())
+
+	// tendermint height -> plasma block
+	res, err = querier(ctx, []string{QueryTMBlock, "7"}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var block Block
+	require.NoError(t, json.Unmarshal(res, &block))
+	require.Equal(t, big.NewInt(2), block.Height)
+
+	type errorCase struct {
+		path []string
+		code uint32
+	}
+	cases := []errorCase{
+		{[]string{QueryTxPosition, "0x1234"}, uint32(CodeInvalidPath)},
+		{[]string{QueryTxPosition, fmt.Sprintf("0x%x", make([]byte, 32))}, uint32(CodeDNE)},
+		{[]string{QuerySpender, "(2.0.0.0)"}, uint32(CodeDNE)},
+		{[]string{QuerySpender, "position"}, uint32(CodeInvalidPath)},
+		{[]string{QueryTMBlock, "5"}, uint32(CodeDNE)},
+		{[]string{QueryTMBlock, "-1"}, uint32(CodeInvalidPath)},
+		{[]string{QueryTMBlock}, uint32(CodeInvalidPath)},
+	}
+	for i, c := range cases {
+		_, err := querier(ctx, c.path, abci.RequestQuery{})
+		require.NotNil(t, err, fmt.Sprintf("case %d: query did not fail", i))
+		require.Equal(t, c.code, uint32(err.Code()), fmt.Sprintf("case %d: unexpected error code", i))
+	}
+}
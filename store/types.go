@@ -6,12 +6,32 @@ import (
 	"math/big"
 )
 
-// Wallet holds reference to the total balance, unspent, and spent outputs
-// at a given address
+// Wallet holds the total balance at a given address along with the number
+// of outputs it has received. Outputs are stored as separate WalletOutput
+// records so that a spend does not rewrite the history of the wallet
 type Wallet struct {
-	Balance *big.Int          // total amount available to be spent
-	Unspent []plasma.Position // position of unspent transaction outputs
-	Spent   []plasma.Position // position of spent transaction outputs
+	Balance    *big.Int // total amount available to be spent
+	NumOutputs uint64   // number of outputs received. Sequence of the next output
+}
+
+// legacyWallet is the layout of wallets stored by releases before wallet outputs
+// were stored as separate records. Such wallets are rewritten by MigrateWallets
+type legacyWallet struct {
+	Balance *big.Int
+	Unspent []plasma.Position
+	Spent   []plasma.Position
+}
+
+// WalletOutput is an output in the history of a wallet. Outputs are ordered
+// by the sequence in which the wallet received them
+type WalletOutput struct {
+	Position  plasma.Position
+	Amount    *big.Int
+	TxHash    []byte   // transaction that created this output. Empty for deposits and fees
+	CreatedAt *big.Int // plasma block this output was created or included in
+	Spent     bool
+	SpenderTx []byte   // transaction hash that spent this output
+	SpentAt   *big.Int // plasma block this output was spent in. 0 if unspent
 }
 
 // Deposit wraps a plasma deposit with spend information.
@@ -86,3 +106,14 @@ type Block struct {
 	plasma.Block
 	TMBlockHeight uint64
 }
+
+// TxProof holds a transaction along with the merkle proof of its inclusion
+// in a plasma block. Proof is the concatenation of the 32-byte aunts from the
+// leaf up to, but excluding, the root as expected by TMSimpleMerkleTree.sol
+type TxProof struct {
+	Block   Block
+	TxBytes []byte
+	Index   int // index of the transaction's leaf in the merkle tree
+	Total   int // total number of leaves in the merkle tree
+	Proof   []byte
+}
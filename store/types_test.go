@@ -15,9 +15,8 @@ import (
 func TestWalletSerialization(t *testing.T) {
 	// Construct Wallet
 	acc := Wallet{
-		Balance: big.NewInt(234578),
-		Unspent: []plasma.Position{getPosition("(8745.1239.1.0)"), getPosition("(23409.12456.0.0)"), getPosition("(894301.1.1.0)"), getPosition("(0.0.0.540124)")},
-		Spent:   []plasma.Position{getPosition("(0.0.0.3)"), getPosition("(7.734.1.3)")},
+		Balance:    big.NewInt(234578),
+		NumOutputs: 6,
 	}
 
 	bytes, err := rlp.EncodeToBytes(&acc)
@@ -28,6 +27,26 @@ func TestWalletSerialization(t *testing.T) {
 	require.NoError(t, err)
 
 	require.True(t, reflect.DeepEqual(acc, recoveredAcc), "mismatch in serialized and deserialized wallet")
+
+	// Construct WalletOutput
+	output := WalletOutput{
+		Position:  getPosition("(8745.1239.1.0)"),
+		Amount:    big.NewInt(1000),
+		TxHash:    []byte("transaction hash"),
+		CreatedAt: big.NewInt(8745),
+		Spent:     true,
+		SpenderTx: []byte("spender hash"),
+		SpentAt:   big.NewInt(8750),
+	}
+
+	bytes, err = rlp.EncodeToBytes(&output)
+	require.NoError(t, err)
+
+	recoveredOutput := WalletOutput{}
+	err = rlp.DecodeBytes(bytes, &recoveredOutput)
+	require.NoError(t, err)
+
+	require.True(t, reflect.DeepEqual(output, recoveredOutput), "mismatch in serialized and deserialized wallet output")
 }
 
 // Test that the Deposit can be serialized and deserialized without loss of information
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Validator holds the consensus public key of the validator along with the
+// ethereum address that fees are collected to. ConsPubKey is the amino
+// encoded tendermint public key.
+type Validator struct {
+	ConsPubKey []byte
+	FeeAddress common.Address
+}
+
+// FeeAddress is the address fees are collected to. Nonce is signed into the
+// next update of the fee address
+type FeeAddress struct {
+	Address common.Address
+	Nonce   uint64
+}
+
+// GetValidator returns the validator of the chain.
+func (ds DataStore) GetValidator(ctx sdk.Context) (Validator, bool) {
+	data := ds.Get(ctx, GetValidatorKey())
+	if data == nil {
+		return Validator{}, false
+	}
+
+	var validator Validator
+	if err := rlp.DecodeBytes(data, &validator); err != nil {
+		panic(fmt.Sprintf("validator store corrupted: %s", err))
+	}
+
+	return validator, true
+}
+
+// StoreValidator overwrites the validator of the chain.
+func (ds DataStore) StoreValidator(ctx sdk.Context, validator Validator) {
+	data, err := rlp.EncodeToBytes(&validator)
+	if err != nil {
+		panic(fmt.Sprintf("error marshaling validator: %s", err))
+	}
+
+	ds.Set(ctx, GetValidatorKey(), data)
+}
+
+// GetFeeAddressNonce returns the number of updates made to the fee address of
+// the validator. The next update must be signed over this nonce.
+func (ds DataStore) GetFeeAddressNonce(ctx sdk.Context) uint64 {
+	data := ds.Get(ctx, GetFeeAddressNonceKey())
+	if data == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(data)
+}
+
+// SetFeeAddressNonce overwrites the number of updates made to the fee address.
+func (ds DataStore) SetFeeAddressNonce(ctx sdk.Context, nonce uint64) {
+	ds.Set(ctx, GetFeeAddressNonceKey(), sequenceBytes(nonce))
+}
+
+// UpdateFeeAddress sets the fee address of the validator and increments the
+// fee address nonce.
+func (ds DataStore) UpdateFeeAddress(ctx sdk.Context, addr common.Address) {
+	validator, _ := ds.GetValidator(ctx)
+	validator.FeeAddress = addr
+	ds.StoreValidator(ctx, validator)
+	ds.SetFeeAddressNonce(ctx, ds.GetFeeAddressNonce(ctx)+1)
+}
+
+// ValidatorPower is a member of the validator set. ConsPubKey is the amino
+// encoded tendermint public key.
+type ValidatorPower struct {
+	ConsPubKey []byte
+	Power      uint64
+}
+
+// ValidatorSet is the validator set of the chain. Nonce is signed into the
+// next validator update
+type ValidatorSet struct {
+	Validators []ValidatorPower
+	Nonce      uint64
+}
+
+// GetValidatorSet returns the validators with non-zero power ordered by their
+// consensus public key. Chains started before the validator set was stored
+// consist of the genesis validator with power 1.
+func (ds DataStore) GetValidatorSet(ctx sdk.Context) []ValidatorPower {
+	var set []ValidatorPower
+	ds.iterate(ctx, validatorPowerKey, func(key, value []byte) {
+		set = append(set, ValidatorPower{
+			ConsPubKey: append([]byte{}, key...),
+			Power:      binary.BigEndian.Uint64(value),
+		})
+	})
+
+	if len(set) == 0 {
+		if validator, ok := ds.GetValidator(ctx); ok {
+			set = append(set, ValidatorPower{validator.ConsPubKey, 1})
+		}
+	}
+
+	return set
+}
+
+// SetValidatorPower sets the voting power of the validator with the amino
+// encoded consensus public key `consPubKey`. The validator is removed from
+// the set if `power` is zero.
+func (ds DataStore) SetValidatorPower(ctx sdk.Context, consPubKey []byte, power uint64) {
+	// persist the implicit genesis validator of chains started before the
+	// validator set was stored
+	if !ds.hasValidatorSet(ctx) {
+		for _, validator := range ds.GetValidatorSet(ctx) {
+			ds.Set(ctx, GetValidatorPowerKey(validator.ConsPubKey), sequenceBytes(validator.Power))
+		}
+	}
+
+	if power == 0 {
+		ds.Delete(ctx, GetValidatorPowerKey(consPubKey))
+	} else {
+		ds.Set(ctx, GetValidatorPowerKey(consPubKey), sequenceBytes(power))
+	}
+}
+
+// GetValidatorUpdateNonce returns the number of updates made to the validator
+// set. The next update must be signed over this nonce.
+func (ds DataStore) GetValidatorUpdateNonce(ctx sdk.Context) uint64 {
+	data := ds.Get(ctx, GetValidatorUpdateNonceKey())
+	if data == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(data)
+}
+
+// SetValidatorUpdateNonce overwrites the number of updates made to the validator set.
+func (ds DataStore) SetValidatorUpdateNonce(ctx sdk.Context, nonce uint64) {
+	ds.Set(ctx, GetValidatorUpdateNonceKey(), sequenceBytes(nonce))
+}
+
+// GetOperator returns the address authorized to update the validator set. It is
+// set in genesis, so validator updates never depend on the rootchain.
+func (ds DataStore) GetOperator(ctx sdk.Context) (common.Address, bool) {
+	data := ds.Get(ctx, GetOperatorKey())
+	if data == nil {
+		return common.Address{}, false
+	}
+
+	return common.BytesToAddress(data), true
+}
+
+// SetOperator overwrites the address authorized to update the validator set.
+func (ds DataStore) SetOperator(ctx sdk.Context, operator common.Address) {
+	ds.Set(ctx, GetOperatorKey(), operator.Bytes())
+}
+
+func (ds DataStore) hasValidatorSet(ctx sdk.Context) bool {
+	iter := sdk.KVStorePrefixIterator(ds.KVStore(ctx), validatorPowerKey)
+	defer iter.Close()
+
+	return iter.Valid()
+}
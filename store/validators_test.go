@@ -0,0 +1,54 @@
+package store
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"testing"
+)
+
+// Test that the validator set is updated and the fee address is kept with the genesis validator
+func TestValidatorSet(t *testing.T) {
+	ctx, key := setup()
+	ds := NewDataStore(key)
+
+	require.Empty(t, ds.GetValidatorSet(ctx))
+
+	// chains started before the validator set was stored have a single validator
+	ds.StoreValidator(ctx, Validator{ConsPubKey: []byte("genesis")})
+	require.Equal(t, []ValidatorPower{{[]byte("genesis"), 1}}, ds.GetValidatorSet(ctx))
+
+	// the genesis validator is kept when the set is updated
+	ds.SetValidatorPower(ctx, []byte("added"), 5)
+	require.Equal(t, []ValidatorPower{{[]byte("added"), 5}, {[]byte("genesis"), 1}}, ds.GetValidatorSet(ctx))
+
+	ds.SetValidatorPower(ctx, []byte("genesis"), 0)
+	ds.SetValidatorPower(ctx, []byte("added"), 2)
+	require.Equal(t, []ValidatorPower{{[]byte("added"), 2}}, ds.GetValidatorSet(ctx))
+
+	require.Equal(t, uint64(0), ds.GetValidatorUpdateNonce(ctx))
+	ds.SetValidatorUpdateNonce(ctx, 3)
+	require.Equal(t, uint64(3), ds.GetValidatorUpdateNonce(ctx))
+
+	data, err := NewQuerier(ds)(ctx, []string{QueryValidators}, abci.RequestQuery{})
+	require.NoError(t, err)
+	var set ValidatorSet
+	require.NoError(t, json.Unmarshal(data, &set))
+	require.Equal(t, ValidatorSet{[]ValidatorPower{{[]byte("added"), 2}}, 3}, set)
+
+	// fee address updates keep the validator
+	ds.UpdateFeeAddress(ctx, common.HexToAddress("1"))
+	validator, ok := ds.GetValidator(ctx)
+	require.True(t, ok)
+	require.Equal(t, []byte("genesis"), validator.ConsPubKey)
+	require.Equal(t, common.HexToAddress("1"), validator.FeeAddress)
+	require.Equal(t, uint64(1), ds.GetFeeAddressNonce(ctx))
+
+	_, ok = ds.GetOperator(ctx)
+	require.False(t, ok)
+	ds.SetOperator(ctx, common.HexToAddress("2"))
+	operator, ok := ds.GetOperator(ctx)
+	require.True(t, ok)
+	require.Equal(t, common.HexToAddress("2"), operator)
+}